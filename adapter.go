@@ -2,6 +2,8 @@ package simsdk
 
 import (
 	"context"
+	"log/slog"
+	"time"
 
 	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -11,12 +13,47 @@ import (
 var _ simsdkrpc.PluginServiceServer = (*grpcAdapter)(nil)
 
 type grpcAdapter struct {
-	plugin PluginWithHandlers
+	plugin        PluginWithHandlers
+	lifecycle     *LifecycleTracker
+	streamOptions []StreamOption
+	mux           bool // serve MessageStream with ServeStreamMux
+	middleware    []Middleware
+	chain         Middleware // nil without middleware
+	quarantine    QuarantineOptions
+	panics        *panicTracker
+	log           *slog.Logger
+	metrics       *sdkMetrics
+	tracer        Tracer // nil uses DefaultTracer
 	simsdkrpc.UnimplementedPluginServiceServer
 }
 
-func NewGRPCAdapter(p PluginWithHandlers) simsdkrpc.PluginServiceServer {
-	return &grpcAdapter{plugin: p}
+// AdapterOption customizes the gRPC adapter returned by NewGRPCAdapter.
+type AdapterOption func(*grpcAdapter)
+
+// WithStreamOptions applies the given options to every MessageStream served by the adapter.
+func WithStreamOptions(opts ...StreamOption) AdapterOption {
+	return func(g *grpcAdapter) { g.streamOptions = append(g.streamOptions, opts...) }
+}
+
+// WithStreamMux serves each MessageStream with ServeStreamMux, so every
+// component initialized on a stream gets its own handler from the plugin's
+// GetStreamHandler. Messages for a component that was not initialized on the
+// stream are then nak'd. Without it, one handler serves the whole stream.
+func WithStreamMux() AdapterOption {
+	return func(g *grpcAdapter) { g.mux = true }
+}
+
+func NewGRPCAdapter(p PluginWithHandlers, opts ...AdapterOption) simsdkrpc.PluginServiceServer {
+	g := &grpcAdapter{plugin: p, lifecycle: NewLifecycleTracker()}
+	for _, opt := range opts {
+		opt(g)
+	}
+	g.chain = chainOf(g.middleware)
+	g.log = loggerOr(g.log).With(slog.String("plugin", p.GetManifest().Name))
+	g.panics = newPanicTracker(g.quarantine, g.log)
+	// Options passed through WithStreamOptions come last, so they win.
+	g.streamOptions = append([]StreamOption{WithStreamLogger(g.log), withPanicTracker(g.panics)}, g.streamOptions...)
+	return g
 }
 
 func (g *grpcAdapter) GetManifest(ctx context.Context, _ *simsdkrpc.ManifestRequest) (*simsdkrpc.ManifestResponse, error) {
@@ -27,33 +64,240 @@ func (g *grpcAdapter) GetManifest(ctx context.Context, _ *simsdkrpc.ManifestRequ
 
 func (g *grpcAdapter) CreateComponentInstance(ctx context.Context, req *simsdkrpc.CreateComponentRequest) (*simsdkrpc.CreateComponentResponse, error) {
 	sdkReq := fromProtoCreateComponentRequest(req)
-	err := g.plugin.CreateComponentInstance(sdkReq)
+	err := g.lifecycleCall(ctx, "CreateComponentInstance", sdkReq.ComponentID, sdkReq, func() error {
+		return g.plugin.CreateComponentInstance(sdkReq)
+	})
 	if err != nil {
-		return nil, err
+		return nil, ToGRPCError(err)
 	}
+	// A plugin may accept a repeated create as a no-op, so an existing
+	// component keeps its tracked state, e.g. paused.
+	g.lifecycle.trackNew(sdkReq.ComponentID, ComponentRunning)
+	g.panics.clear(sdkReq.ComponentID)
 	return &simsdkrpc.CreateComponentResponse{}, nil
 }
 
 func (g *grpcAdapter) DestroyComponentInstance(ctx context.Context, id *wrapperspb.StringValue) (*emptypb.Empty, error) {
-	return &emptypb.Empty{}, g.plugin.DestroyComponentInstance(id.Value)
+	err := g.lifecycleCall(ctx, "DestroyComponentInstance", id.GetValue(), nil, func() error {
+		return g.plugin.DestroyComponentInstance(id.GetValue())
+	})
+	if err != nil {
+		return nil, ToGRPCError(err)
+	}
+	g.lifecycle.Forget(id.Value)
+	g.panics.clear(id.Value)
+	return &emptypb.Empty{}, nil
 }
 
 func (g *grpcAdapter) HandleMessage(ctx context.Context, msg *simsdkrpc.SimMessage) (*simsdkrpc.MessageResponse, error) {
-	in := fromProtoSimMessage(msg)
-	outbound, err := g.plugin.HandleMessage(in)
+	outbound, err := g.handleMessage(ctx, "HandleMessage", fromProtoSimMessage(msg))
 	if err != nil {
-		return nil, err
+		return nil, ToGRPCError(err)
 	}
 
 	response := &simsdkrpc.MessageResponse{}
 	for _, out := range outbound {
+		g.metrics.sentMessage(out.ComponentID, out.MessageType)
 		response.OutboundMessages = append(response.OutboundMessages, toProtoSimMessage(out))
 	}
 	return response, nil
 }
 
+// handleMessage passes a unary message to the plugin through the middleware,
+// recovering panics and rejecting messages for quarantined components. The
+// call is traced, and outbound messages are stamped as responses to in and
+// continue the trace.
+func (g *grpcAdapter) handleMessage(ctx context.Context, method string, in SimMessage) (out []SimMessage, err error) {
+	start := time.Now()
+	ctx, span := StartMessageSpan(extractGRPCTraceContext(ctx), g.tracer, "simsdk."+method, SpanKindServer, &in)
+	defer func() {
+		g.metrics.handled(in.ComponentID, in.MessageType, start, err != nil)
+		if err != nil {
+			span.RecordError(err)
+		}
+		for i := range out {
+			out[i] = *withTraceContext(ctx, stampResponse(&in, &out[i]))
+		}
+		span.End()
+	}()
+
+	if err := g.panics.check(in.ComponentID); err != nil {
+		return nil, err
+	}
+	call := &Call{Kind: UnaryCall, Method: method, ComponentID: in.ComponentID, Message: &in}
+	if g.chain == nil {
+		var out []SimMessage
+		err := g.panics.run(call, func() (err error) {
+			out, err = g.plugin.HandleMessage(in)
+			return err
+		})
+		return out, err
+	}
+	ptrs, err := intercept(g.chain, ctx, call,
+		func(_ context.Context, call *Call) (ptrs []*SimMessage, err error) {
+			err = g.panics.run(call, func() error {
+				out, err := g.plugin.HandleMessage(*call.Message)
+				ptrs = make([]*SimMessage, len(out))
+				for i := range out {
+					ptrs[i] = &out[i]
+				}
+				return err
+			})
+			return ptrs, err
+		})
+	msgs := make([]SimMessage, len(ptrs))
+	for i, m := range ptrs {
+		msgs[i] = *m
+	}
+	return msgs, err
+}
+
 func (g *grpcAdapter) MessageStream(stream simsdkrpc.PluginService_MessageStreamServer) error {
-	return ServeStream(g.plugin.GetStreamHandler(), stream)
+	if g.mux {
+		return ServeStreamMux(g.plugin.GetStreamHandler, stream, g.streamOptions...)
+	}
+	return ServeStream(g.plugin.GetStreamHandler(), stream, g.streamOptions...)
+}
+
+// InvokeControlFunction runs a control function through the middleware as a
+// lifecycle call, so Authorize and Validate gate it like the other RPCs.
+func (g *grpcAdapter) InvokeControlFunction(ctx context.Context, req *simsdkrpc.ControlFunctionRequest) (*simsdkrpc.ControlFunctionResponse, error) {
+	handler, ok := g.plugin.(ControlFunctionHandler)
+	if !ok {
+		return nil, ToGRPCError(NewError(ErrUnimplemented, req.GetComponentId(),
+			"plugin %q does not support control functions", g.plugin.GetManifest().Name))
+	}
+
+	sdkReq := fromProtoControlFunctionRequest(req)
+	var result ControlFunctionResult
+	err := g.lifecycleCall(ctx, "InvokeControlFunction", sdkReq.ComponentID, sdkReq, func() (err error) {
+		spec, ok := g.plugin.GetManifest().FindControlFunction(sdkReq.FunctionID)
+		if !ok {
+			return NewError(ErrNotFound, sdkReq.ComponentID, "unknown control function %q", sdkReq.FunctionID)
+		}
+		if err := ValidateParameters(spec.Fields, sdkReq.Parameters); err != nil {
+			return WrapError(ErrInvalidArgument, sdkReq.ComponentID, err)
+		}
+		result, err = handler.InvokeControlFunction(sdkReq)
+		return err
+	})
+	if err != nil {
+		return nil, ToGRPCError(err)
+	}
+	return &simsdkrpc.ControlFunctionResponse{
+		Result:   result.Result,
+		Metadata: result.Metadata,
+	}, nil
+}
+
+func (g *grpcAdapter) ListComponents(ctx context.Context, req *simsdkrpc.ListComponentsRequest) (*simsdkrpc.ListComponentsResponse, error) {
+	provider, ok := g.plugin.(ComponentStatusProvider)
+	if !ok {
+		return nil, ToGRPCError(NewError(ErrUnimplemented, "",
+			"plugin %q does not support component introspection", g.plugin.GetManifest().Name))
+	}
+
+	var components []ComponentStatus
+	err := g.panics.run(&Call{Kind: LifecycleCall, Method: "ListComponents"}, func() error {
+		components = provider.ListComponents()
+		return nil
+	})
+	if err != nil {
+		return nil, ToGRPCError(err)
+	}
+
+	resp := &simsdkrpc.ListComponentsResponse{}
+	for _, cs := range components {
+		if req.GetComponentType() != "" && cs.ComponentType != req.GetComponentType() {
+			continue
+		}
+		resp.Components = append(resp.Components, ToProtoComponentStatus(cs))
+	}
+	return resp, nil
+}
+
+func (g *grpcAdapter) GetComponentStatus(ctx context.Context, id *wrapperspb.StringValue) (*simsdkrpc.ComponentStatus, error) {
+	provider, ok := g.plugin.(ComponentStatusProvider)
+	if !ok {
+		return nil, ToGRPCError(NewError(ErrUnimplemented, id.GetValue(),
+			"plugin %q does not support component introspection", g.plugin.GetManifest().Name))
+	}
+
+	var cs ComponentStatus
+	err := g.panics.run(&Call{Kind: LifecycleCall, Method: "GetComponentStatus", ComponentID: id.GetValue()}, func() (err error) {
+		cs, err = provider.GetComponentStatus(id.GetValue())
+		return err
+	})
+	if err != nil {
+		return nil, ToGRPCError(err)
+	}
+	g.panics.status(id.GetValue(), &cs)
+	return ToProtoComponentStatus(cs), nil
+}
+
+func (g *grpcAdapter) PauseComponent(ctx context.Context, id *wrapperspb.StringValue) (*emptypb.Empty, error) {
+	lc, err := g.componentLifecycle(id.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	err = g.lifecycleCall(ctx, "PauseComponent", id.GetValue(), nil, func() error {
+		return g.lifecycle.Transition(id.GetValue(), ComponentPaused, func() error {
+			return lc.PauseComponent(id.GetValue())
+		})
+	})
+	if err != nil {
+		return nil, ToGRPCError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (g *grpcAdapter) ResumeComponent(ctx context.Context, id *wrapperspb.StringValue) (*emptypb.Empty, error) {
+	lc, err := g.componentLifecycle(id.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	err = g.lifecycleCall(ctx, "ResumeComponent", id.GetValue(), nil, func() error {
+		return g.lifecycle.Transition(id.GetValue(), ComponentRunning, func() error {
+			return lc.ResumeComponent(id.GetValue())
+		})
+	})
+	if err != nil {
+		return nil, ToGRPCError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (g *grpcAdapter) ResetComponent(ctx context.Context, id *wrapperspb.StringValue) (*emptypb.Empty, error) {
+	lc, err := g.componentLifecycle(id.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	err = g.lifecycleCall(ctx, "ResetComponent", id.GetValue(), nil, func() error {
+		return g.lifecycle.Reset(id.GetValue(), func() error {
+			return lc.ResetComponent(id.GetValue())
+		})
+	})
+	if err != nil {
+		return nil, ToGRPCError(err)
+	}
+	g.panics.clear(id.GetValue())
+	return &emptypb.Empty{}, nil
+}
+
+// lifecycleCall runs fn through the middleware as a lifecycle call, recovering panics.
+func (g *grpcAdapter) lifecycleCall(ctx context.Context, method, componentID string, req any, fn func() error) error {
+	_, err := intercept(g.chain, ctx, &Call{Kind: LifecycleCall, Method: method, ComponentID: componentID, Request: req},
+		func(_ context.Context, call *Call) ([]*SimMessage, error) { return nil, g.panics.run(call, fn) })
+	return err
+}
+
+func (g *grpcAdapter) componentLifecycle(componentID string) (ComponentLifecycle, error) {
+	lc, ok := g.plugin.(ComponentLifecycle)
+	if !ok {
+		return nil, ToGRPCError(NewError(ErrUnimplemented, componentID,
+			"plugin %q does not support pause/resume/reset", g.plugin.GetManifest().Name))
+	}
+	return lc, nil
 }
 
 // --- helper converters for adapter ---
@@ -66,6 +310,15 @@ func fromProtoCreateComponentRequest(req *simsdkrpc.CreateComponentRequest) Crea
 	}
 }
 
+func fromProtoControlFunctionRequest(req *simsdkrpc.ControlFunctionRequest) ControlFunctionRequest {
+	return ControlFunctionRequest{
+		FunctionID:  req.GetFunctionId(),
+		ComponentID: req.GetComponentId(),
+		Parameters:  req.GetParameters(),
+		Payload:     req.GetPayload(),
+	}
+}
+
 func fromProtoSimMessage(p *simsdkrpc.SimMessage) SimMessage {
 	return SimMessage{
 		MessageType: p.GetMessageType(),
@@ -73,6 +326,8 @@ func fromProtoSimMessage(p *simsdkrpc.SimMessage) SimMessage {
 		ComponentID: p.GetComponentId(),
 		Payload:     p.GetPayload(),
 		Metadata:    p.GetMetadata(),
+		SimTime:     fromProtoTime(p.GetSimTime()),
+		WallTime:    fromProtoTime(p.GetWallTime()),
 	}
 }
 
@@ -83,5 +338,7 @@ func toProtoSimMessage(m SimMessage) *simsdkrpc.SimMessage {
 		ComponentId: m.ComponentID,
 		Payload:     m.Payload,
 		Metadata:    m.Metadata,
+		SimTime:     toProtoTime(m.SimTime),
+		WallTime:    toProtoTime(m.WallTime),
 	}
 }
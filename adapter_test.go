@@ -202,6 +202,11 @@ func TestGRPCAdapter_HandleMessage(t *testing.T) {
 					MessageId:   "ack-msg-1",
 					ComponentId: "cmp-1",
 					Payload:     []byte("received"),
+					Metadata: map[string]string{
+						CorrelationIDKey:   "msg-1",
+						CausationIDKey:     "msg-1",
+						OriginComponentKey: "cmp-1",
+					},
 				},
 			},
 		},
@@ -0,0 +1,290 @@
+package simsdk
+
+import (
+	"context"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+)
+
+// missingResult naks messages a batch handler returned no result for.
+const missingResult = "batch handler returned no result for message"
+
+// MessageResult is the outcome of one message handled by a BatchHandler. A
+// nil Err acks the message; otherwise it is nakked with Err's message.
+type MessageResult struct {
+	Outbound []SimMessage
+	Err      error
+}
+
+// BatchHandler is implemented by plugins that handle a HandleMessages batch in
+// one call. It returns one result per message, in batch order. Plugins without
+// it get each message of the batch through HandleMessage.
+type BatchHandler interface {
+	HandleMessages(msgs []SimMessage) []MessageResult
+}
+
+// StreamBatchResult is the outcome of one message handled by a StreamBatchHandler.
+type StreamBatchResult struct {
+	Responses []*SimMessage
+	Err       error
+}
+
+// StreamBatchHandler is implemented by stream handlers that handle a batch
+// envelope in one call. It is used when every message of the batch goes to the
+// same handler; otherwise each message goes through OnSimMessage.
+type StreamBatchHandler interface {
+	OnSimMessageBatch(msgs []*SimMessage) []StreamBatchResult
+}
+
+// BatchSender is implemented by the SDK's stream senders. SendBatch sends
+// several messages in one envelope.
+type BatchSender interface {
+	StreamSender
+	SendBatch(ctx context.Context, msgs []*SimMessage) error
+}
+
+// SendBatch queues msgs as a single batch envelope. With flow control it waits
+// for one credit per message. In reliable mode every message needs its own
+// sequence, so the messages are sent reliably one by one instead.
+func (s *grpcStreamSender) SendBatch(ctx context.Context, msgs []*SimMessage) error {
+	if s.closed.Load() {
+		return ErrStreamClosed
+	}
+	if s.reliable != nil {
+		for _, msg := range msgs {
+			if err := s.SendContext(ctx, msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	batch := &simsdkrpc.MessageBatch{}
+	var releases []func(int)
+	releaseAll := func() {
+		for _, release := range releases {
+			release(1)
+		}
+	}
+	for _, msg := range msgs {
+		release, err := s.acquireCredit(ctx)
+		if err != nil {
+			releaseAll()
+			return err
+		}
+		releases = append(releases, release)
+		batch.Messages = append(batch.Messages, s.protoMessage(withTraceContext(ctx, msg)))
+	}
+	err := s.writer.enqueue(ctx, &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_Batch{Batch: batch},
+	}, true)
+	if err != nil {
+		releaseAll()
+	}
+	return err
+}
+
+func (g *grpcAdapter) HandleMessages(ctx context.Context, batch *simsdkrpc.MessageBatch) (*simsdkrpc.MessageBatchResponse, error) {
+	msgs := make([]SimMessage, len(batch.GetMessages()))
+	for i, m := range batch.GetMessages() {
+		msgs[i] = fromProtoSimMessage(m)
+	}
+
+	var results []MessageResult
+	if bh, ok := g.plugin.(BatchHandler); ok && len(msgs) > 0 && g.chain == nil && !g.panics.anyQuarantined(msgs) {
+		err := g.panics.run(batchCall(UnaryCall, "HandleMessages", msgs[0].ComponentID), func() error {
+			results = bh.HandleMessages(msgs)
+			return nil
+		})
+		if err != nil {
+			results = failAll(len(msgs), err)
+		}
+		for i := range min(len(msgs), len(results)) {
+			for j := range results[i].Outbound {
+				results[i].Outbound[j] = *stampResponse(&msgs[i], &results[i].Outbound[j])
+			}
+		}
+		for i, m := range msgs {
+			g.metrics.handled(m.ComponentID, m.MessageType, time.Time{}, i >= len(results) || results[i].Err != nil)
+		}
+	} else {
+		results = make([]MessageResult, len(msgs))
+		for i, m := range msgs {
+			out, err := g.handleMessage(ctx, "HandleMessages", m)
+			results[i] = MessageResult{Outbound: out, Err: err}
+		}
+	}
+
+	response := &simsdkrpc.MessageBatchResponse{}
+	for i, m := range msgs {
+		if i >= len(results) {
+			response.Results = append(response.Results, batchResult(m.MessageID, batchSequence(batch, i), missingResult))
+			continue
+		}
+		if err := results[i].Err; err != nil {
+			response.Results = append(response.Results, batchResult(m.MessageID, batchSequence(batch, i), err.Error()))
+			continue
+		}
+		result := batchResult(m.MessageID, batchSequence(batch, i), "")
+		for _, out := range results[i].Outbound {
+			g.metrics.sentMessage(out.ComponentID, out.MessageType)
+			result.OutboundMessages = append(result.OutboundMessages, toProtoSimMessage(out))
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response, nil
+}
+
+// handleBatch handles a batch envelope and answers with one batch result
+// holding an ack or nak per message. Responses are sent as usual.
+func (s *streamSession) handleBatch(b *simsdkrpc.MessageBatch) error {
+	defer s.inflight.end(s.inflight.begin())
+	all := b.GetMessages()
+	msgs := all[:s.admit(len(all))]
+	defer func() {
+		for range msgs {
+			s.replenishCredits()
+		}
+	}()
+
+	response := &simsdkrpc.MessageBatchResponse{}
+	if bh := s.batchHandlerFor(msgs); bh != nil {
+		var in []*SimMessage
+		naks := make([]string, len(msgs))
+		for i, m := range msgs {
+			if err := s.codec.decode(m); err != nil {
+				naks[i] = err.Error()
+				continue
+			}
+			in = append(in, FromProtoSimMessage(m))
+		}
+		var results []StreamBatchResult
+		err := s.panics.run(batchCall(StreamCall, "OnSimMessageBatch", msgs[0].ComponentId), func() error {
+			results = bh.OnSimMessageBatch(in)
+			return nil
+		})
+		if err != nil {
+			results = make([]StreamBatchResult, len(in))
+			for i := range results {
+				results[i].Err = err
+			}
+		}
+		next := 0 // index into results, which skip undecodable messages
+		for i, m := range msgs {
+			if naks[i] == "" {
+				naks[i] = missingResult
+				if next < len(results) {
+					naks[i] = ""
+					if err := results[next].Err; err != nil {
+						naks[i] = err.Error()
+					} else if err := s.sendResponses(stampResponses(in[next], results[next].Responses)); err != nil {
+						return err
+					}
+				}
+				next++
+			}
+			s.metrics.handled(m.ComponentId, m.MessageType, time.Time{}, naks[i] != "")
+			response.Results = append(response.Results, batchResult(m.MessageId, batchSequence(b, i), naks[i]))
+		}
+	} else {
+		for i, m := range msgs {
+			nak, err := s.processMessage(m)
+			if err != nil {
+				return err
+			}
+			response.Results = append(response.Results, batchResult(m.MessageId, batchSequence(b, i), nak))
+		}
+	}
+	for i := len(msgs); i < len(all); i++ {
+		s.metrics.handled(all[i].ComponentId, all[i].MessageType, time.Time{}, true)
+		response.Results = append(response.Results, batchResult(all[i].MessageId, batchSequence(b, i), noCredits))
+	}
+
+	_ = s.writer.enqueue(s.ctx, &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_BatchResult{BatchResult: response},
+	}, false)
+	return nil
+}
+
+// batchHandlerFor returns the StreamBatchHandler every message goes to, or
+// nil when the messages go to different handlers, the handler cannot take a
+// batch, middleware must see each message, or a component is quarantined.
+func (s *streamSession) batchHandlerFor(msgs []*simsdkrpc.SimMessage) StreamBatchHandler {
+	if len(msgs) == 0 || s.chain != nil {
+		return nil
+	}
+	for _, m := range msgs {
+		if s.panics.check(m.ComponentId) != nil {
+			return nil
+		}
+	}
+	handler := s.handlerFor(msgs[0].ComponentId)
+	if handler == nil {
+		return nil
+	}
+	for _, m := range msgs[1:] {
+		if s.handlerFor(m.ComponentId) != handler {
+			return nil
+		}
+	}
+	bh, _ := handler.(StreamBatchHandler)
+	return bh
+}
+
+// handleBatchResult feeds the per-message results of a batch the core
+// answered into the reliable tracker.
+func (s *streamSession) handleBatchResult(r *simsdkrpc.MessageBatchResponse) {
+	if s.reliable == nil {
+		return
+	}
+	for _, res := range r.GetResults() {
+		if ack := res.GetAck(); ack != nil {
+			s.reliable.ack(ack)
+		} else if nak := res.GetNak(); nak != nil {
+			s.reliable.nak(nak)
+		}
+	}
+}
+
+// batchCall describes a whole batch handed to a batch handler. A panic is
+// counted against the component of the first message.
+func batchCall(kind CallKind, method, componentID string) *Call {
+	return &Call{Kind: kind, Method: method, ComponentID: componentID}
+}
+
+// failAll returns n results failing with err.
+func failAll(n int, err error) []MessageResult {
+	results := make([]MessageResult, n)
+	for i := range results {
+		results[i].Err = err
+	}
+	return results
+}
+
+// batchResult acks a message, or naks it when nak is non-empty.
+func batchResult(messageID string, seq uint64, nak string) *simsdkrpc.MessageResult {
+	if nak != "" {
+		return &simsdkrpc.MessageResult{Result: &simsdkrpc.MessageResult_Nak{
+			Nak: &simsdkrpc.PluginNak{MessageId: messageID, ErrorMessage: nak, Sequence: seq},
+		}}
+	}
+	return &simsdkrpc.MessageResult{Result: &simsdkrpc.MessageResult_Ack{
+		Ack: &simsdkrpc.PluginAck{MessageId: messageID, Sequence: seq},
+	}}
+}
+
+// batchSequence returns the reliable-mode sequence of the i-th message, or 0.
+func batchSequence(b *simsdkrpc.MessageBatch, i int) uint64 {
+	if seqs := b.GetSequences(); i < len(seqs) {
+		return seqs[i]
+	}
+	return 0
+}
+
+// stampResponses stamps each of a batch handler's responses to in.
+func stampResponses(in *SimMessage, responses []*SimMessage) []*SimMessage {
+	for i, resp := range responses {
+		responses[i] = stampResponse(in, resp)
+	}
+	return responses
+}
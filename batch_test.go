@@ -0,0 +1,141 @@
+package simsdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// batchPlugin fails its second message and drops the result of the rest.
+type batchPlugin struct {
+	dummyPlugin
+	batches int
+}
+
+func (p *batchPlugin) HandleMessages(msgs []SimMessage) []MessageResult {
+	p.batches++
+	return []MessageResult{
+		{Outbound: []SimMessage{{MessageID: "out-" + msgs[0].MessageID}}},
+		{Err: errors.New("bad reading")},
+	}
+}
+
+func batchOf(ids ...string) *simsdkrpc.MessageBatch {
+	b := &simsdkrpc.MessageBatch{}
+	for _, id := range ids {
+		b.Messages = append(b.Messages, &simsdkrpc.SimMessage{MessageId: id, ComponentId: "a"})
+	}
+	return b
+}
+
+func TestGRPCAdapter_HandleMessagesFallsBackToHandleMessage(t *testing.T) {
+	adapter := NewGRPCAdapter(&dummyPlugin{})
+	resp, err := adapter.HandleMessages(context.Background(), batchOf("m1", "m2"))
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 2)
+	for i, r := range resp.Results {
+		require.Equal(t, []string{"m1", "m2"}[i], r.GetAck().GetMessageId())
+		require.Equal(t, "Reply1", r.OutboundMessages[0].MessageId)
+	}
+}
+
+func TestGRPCAdapter_HandleMessagesUsesBatchHandler(t *testing.T) {
+	plugin := &batchPlugin{}
+	batch := batchOf("m1", "m2", "m3")
+	batch.Sequences = []uint64{4, 5, 6}
+	resp, err := NewGRPCAdapter(plugin).HandleMessages(context.Background(), batch)
+	require.NoError(t, err)
+	require.Equal(t, 1, plugin.batches)
+
+	require.Equal(t, uint64(4), resp.Results[0].GetAck().GetSequence())
+	require.Equal(t, "out-m1", resp.Results[0].OutboundMessages[0].MessageId)
+	require.Equal(t, "bad reading", resp.Results[1].GetNak().GetErrorMessage())
+	require.Equal(t, uint64(5), resp.Results[1].GetNak().GetSequence())
+	require.Equal(t, "m3", resp.Results[2].GetNak().GetMessageId(), "a missing result naks the message")
+}
+
+func batchEnvelope(b *simsdkrpc.MessageBatch) *simsdkrpc.PluginMessageEnvelope {
+	return &simsdkrpc.PluginMessageEnvelope{Content: &simsdkrpc.PluginMessageEnvelope_Batch{Batch: b}}
+}
+
+func TestServeStreamMux_BatchEnvelopeFallsBackPerMessage(t *testing.T) {
+	var handler *recordingHandler
+	batch := batchOf("m1", "m2")
+	batch.Messages[1].ComponentId = "ghost"
+	batch.Sequences = []uint64{7, 8}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{initEnvelope("a"), batchEnvelope(batch)}}
+
+	require.NoError(t, ServeStreamMux(func() StreamHandler {
+		handler = &recordingHandler{}
+		return handler
+	}, stream))
+	require.Equal(t, []string{"m1"}, handler.messages)
+
+	var result *simsdkrpc.MessageBatchResponse
+	for _, env := range stream.sent {
+		require.Nil(t, env.GetAck(), "batch messages are answered in the batch result only")
+		if r := env.GetBatchResult(); r != nil {
+			result = r
+		}
+	}
+	require.NotNil(t, result)
+	require.Equal(t, uint64(7), result.Results[0].GetAck().GetSequence())
+	require.Equal(t, "m2", result.Results[1].GetNak().GetMessageId())
+	require.Equal(t, uint64(8), result.Results[1].GetNak().GetSequence())
+}
+
+// batchingHandler takes stream batches in one call and sends its own batch on init.
+type batchingHandler struct {
+	recordingHandler
+	batches [][]string
+}
+
+func (h *batchingHandler) OnInit(init *simsdkrpc.PluginInit) error {
+	return h.sender.(BatchSender).SendBatch(context.Background(), []*SimMessage{{MessageID: "s1"}, {MessageID: "s2"}})
+}
+
+func (h *batchingHandler) OnSimMessageBatch(msgs []*SimMessage) []StreamBatchResult {
+	var ids []string
+	var results []StreamBatchResult
+	for _, m := range msgs {
+		ids = append(ids, m.MessageID)
+		results = append(results, StreamBatchResult{Responses: []*SimMessage{{MessageID: "re-" + m.MessageID}}})
+	}
+	h.batches = append(h.batches, ids)
+	return results
+}
+
+func TestServeStream_BatchHandlerAndSendBatch(t *testing.T) {
+	handler := &batchingHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{initEnvelope("a"), batchEnvelope(batchOf("m1", "m2"))}}
+
+	require.NoError(t, ServeStream(handler, stream, WithFlowControl(FlowControlOptions{Window: 4})))
+	require.Equal(t, [][]string{{"m1", "m2"}}, handler.batches)
+	require.Empty(t, handler.messages)
+
+	var sentBatch *simsdkrpc.MessageBatch
+	var replies []string
+	var acked int
+	for _, env := range stream.sent {
+		switch {
+		case env.GetBatch() != nil:
+			sentBatch = env.GetBatch()
+		case env.GetSimMessage() != nil:
+			replies = append(replies, env.GetSimMessage().MessageId)
+		case env.GetBatchResult() != nil:
+			for _, r := range env.GetBatchResult().Results {
+				if r.GetAck() != nil {
+					acked++
+				}
+			}
+		}
+	}
+	require.NotNil(t, sentBatch)
+	require.Len(t, sentBatch.Messages, 2)
+	require.Equal(t, "a", sentBatch.Messages[0].ComponentId, "batched messages are stamped like single sends")
+	require.Equal(t, []string{"re-m1", "re-m2"}, replies)
+	require.Equal(t, 2, acked)
+}
@@ -0,0 +1,289 @@
+package simsdk
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// DefaultChunkSize is the largest payload sent in one envelope; larger
+	// payloads are split into chunks. It stays well below gRPC's default 4 MB
+	// message limit.
+	DefaultChunkSize = 1 << 20
+	// DefaultMaxChunkedMessageSize is the largest payload reassembled from chunks.
+	DefaultMaxChunkedMessageSize = 64 << 20
+	// DefaultMaxPartialMessages is how many messages may be partially received at once.
+	DefaultMaxPartialMessages = 16
+	// DefaultChunkTimeout is how long the SDK waits for the rest of a chunked message.
+	DefaultChunkTimeout = 30 * time.Second
+)
+
+// ChunkingOptions configures how large SimMessage payloads are split into
+// chunks and reassembled. Inbound chunks are always reassembled; outbound
+// payloads are only split with WithChunking, since older cores cannot
+// reassemble them. Zero fields take the defaults.
+type ChunkingOptions struct {
+	ChunkSize      int           // Payload bytes per chunk; negative disables splitting outbound messages
+	MaxMessageSize int           // Largest reassembled payload; bigger messages are nak'd
+	MaxPartial     int           // Messages partially received at once; more are nak'd
+	Timeout        time.Duration // Time allowed for all chunks of a message to arrive
+}
+
+func (o ChunkingOptions) withDefaults() ChunkingOptions {
+	if o.ChunkSize == 0 {
+		o.ChunkSize = DefaultChunkSize
+	}
+	if o.MaxMessageSize <= 0 {
+		o.MaxMessageSize = DefaultMaxChunkedMessageSize
+	}
+	if o.MaxPartial <= 0 {
+		o.MaxPartial = DefaultMaxPartialMessages
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultChunkTimeout
+	}
+	return o
+}
+
+// WithChunking splits outbound payloads larger than the chunk size, and
+// batches larger than it into smaller ones, and tunes the limits applied when
+// reassembling inbound chunks. Only enable it for cores that reassemble chunks.
+func WithChunking(opts ChunkingOptions) StreamOption {
+	return func(o *streamOptions) { o.chunking = &opts }
+}
+
+// chunkingSender splits SimMessage envelopes with large payloads into chunk
+// envelopes. It sits below the stream writer, so the chunks of a message are
+// always sent back to back and a redelivered message is chunked again.
+type chunkingSender struct {
+	out  envelopeSender
+	size int
+}
+
+func (c *chunkingSender) Send(env *simsdkrpc.PluginMessageEnvelope) error {
+	if b := env.GetBatch(); b != nil && c.size > 0 && proto.Size(b) > c.size {
+		return c.sendBatch(b, env.Sequence)
+	}
+	sm := env.GetSimMessage()
+	if c.size <= 0 || len(sm.GetPayload()) <= c.size {
+		return c.out.Send(env)
+	}
+	for _, chunk := range splitMessage(sm, c.size) {
+		if err := c.out.Send(&simsdkrpc.PluginMessageEnvelope{
+			Content:  &simsdkrpc.PluginMessageEnvelope_Chunk{Chunk: chunk},
+			Sequence: env.Sequence,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendBatch sends a batch larger than the chunk size as several batches that
+// fit, and each message whose payload alone is too large as chunks. Results
+// are per message, so the core answers the pieces as it would the batch.
+func (c *chunkingSender) sendBatch(b *simsdkrpc.MessageBatch, seq uint64) error {
+	part, size := &simsdkrpc.MessageBatch{}, 0
+	flush := func() error {
+		if len(part.Messages) == 0 {
+			return nil
+		}
+		err := c.out.Send(&simsdkrpc.PluginMessageEnvelope{
+			Content:  &simsdkrpc.PluginMessageEnvelope_Batch{Batch: part},
+			Sequence: seq,
+		})
+		part, size = &simsdkrpc.MessageBatch{}, 0
+		return err
+	}
+	for i, m := range b.GetMessages() {
+		if len(m.GetPayload()) > c.size {
+			if err := flush(); err != nil {
+				return err
+			}
+			msgSeq := seq
+			if i < len(b.GetSequences()) {
+				msgSeq = b.GetSequences()[i]
+			}
+			if err := c.Send(&simsdkrpc.PluginMessageEnvelope{
+				Content:  &simsdkrpc.PluginMessageEnvelope_SimMessage{SimMessage: m},
+				Sequence: msgSeq,
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		n := proto.Size(m)
+		if size+n > c.size {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		part.Messages = append(part.Messages, m)
+		if i < len(b.GetSequences()) {
+			part.Sequences = append(part.Sequences, b.GetSequences()[i])
+		}
+		size += n
+	}
+	return flush()
+}
+
+// splitMessage cuts msg's payload into chunks of at most size bytes.
+func splitMessage(msg *simsdkrpc.SimMessage, size int) []*simsdkrpc.PluginChunk {
+	payload := msg.GetPayload()
+	count := (len(payload) + size - 1) / size
+	chunks := make([]*simsdkrpc.PluginChunk, 0, count)
+	for i := 0; i < count; i++ {
+		end := min((i+1)*size, len(payload))
+		chunk := &simsdkrpc.PluginChunk{
+			MessageId: msg.GetMessageId(),
+			Index:     uint32(i),
+			Count:     uint32(count),
+			Data:      payload[i*size : end],
+			TotalSize: uint64(len(payload)),
+		}
+		if i == 0 {
+			chunk.Header = withoutPayload(msg)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// withoutPayload returns a shallow copy of msg without its payload, so large
+// payloads are never copied.
+func withoutPayload(msg *simsdkrpc.SimMessage) *simsdkrpc.SimMessage {
+	header := &simsdkrpc.SimMessage{}
+	dst := header.ProtoReflect()
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Name() != "payload" {
+			dst.Set(fd, v)
+		}
+		return true
+	})
+	return header
+}
+
+// partialMessage is a chunked message still being received.
+type partialMessage struct {
+	header *simsdkrpc.SimMessage
+	count  uint32
+	next   uint32
+	seq    uint64
+	data   []byte
+	timer  *time.Timer
+}
+
+// chunkAssembler reassembles chunked inbound messages. Messages that break the
+// limits, arrive out of order or time out are dropped and nak'd.
+type chunkAssembler struct {
+	opts ChunkingOptions
+	nak  func(messageID string, seq uint64, reason string) error
+	log  *slog.Logger
+
+	mu      sync.Mutex
+	partial map[string]*partialMessage
+	closed  bool
+}
+
+func newChunkAssembler(opts ChunkingOptions, nak func(messageID string, seq uint64, reason string) error, logger *slog.Logger) *chunkAssembler {
+	return &chunkAssembler{opts: opts, nak: nak, log: logger, partial: make(map[string]*partialMessage)}
+}
+
+// add records a chunk and returns the complete message once its last chunk
+// has arrived.
+func (a *chunkAssembler) add(c *simsdkrpc.PluginChunk, seq uint64) *simsdkrpc.SimMessage {
+	msg, reason := a.addLocked(c, seq)
+	if reason != "" {
+		a.log.Warn("Dropping chunked message", slog.String("message_id", c.GetMessageId()), slog.String("reason", reason))
+		_ = a.nak(c.GetMessageId(), seq, reason)
+	}
+	return msg
+}
+
+func (a *chunkAssembler) addLocked(c *simsdkrpc.PluginChunk, seq uint64) (*simsdkrpc.SimMessage, string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil, ""
+	}
+
+	id := c.GetMessageId()
+	p, ok := a.partial[id]
+	if !ok {
+		switch {
+		case c.GetIndex() != 0 || c.GetHeader() == nil:
+			return nil, fmt.Sprintf("chunk %d/%d received without the first chunk", c.GetIndex()+1, c.GetCount())
+		case c.GetCount() == 0:
+			return nil, "chunk count is zero"
+		case c.GetTotalSize() > uint64(a.opts.MaxMessageSize):
+			return nil, fmt.Sprintf("payload of %d bytes exceeds the %d byte limit", c.GetTotalSize(), a.opts.MaxMessageSize)
+		case len(a.partial) >= a.opts.MaxPartial:
+			return nil, fmt.Sprintf("too many partially received messages (limit %d)", a.opts.MaxPartial)
+		}
+		p = &partialMessage{header: c.GetHeader(), count: c.GetCount(), seq: seq, data: make([]byte, 0, c.GetTotalSize())}
+		p.timer = time.AfterFunc(a.opts.Timeout, func() { a.expire(id, p) })
+		a.partial[id] = p
+	}
+
+	if c.GetIndex() != p.next || c.GetCount() != p.count {
+		a.dropLocked(id)
+		return nil, fmt.Sprintf("chunk %d/%d out of order, expected %d/%d", c.GetIndex()+1, c.GetCount(), p.next+1, p.count)
+	}
+	if len(p.data)+len(c.GetData()) > a.opts.MaxMessageSize {
+		a.dropLocked(id)
+		return nil, fmt.Sprintf("payload exceeds the %d byte limit", a.opts.MaxMessageSize)
+	}
+	p.data = append(p.data, c.GetData()...)
+	p.next++
+	if p.next < p.count {
+		return nil, ""
+	}
+
+	a.dropLocked(id)
+	p.header.Payload = p.data
+	return p.header, ""
+}
+
+// expire drops a message whose chunks did not all arrive in time.
+func (a *chunkAssembler) expire(id string, p *partialMessage) {
+	a.mu.Lock()
+	if a.closed || a.partial[id] != p {
+		a.mu.Unlock()
+		return
+	}
+	a.dropLocked(id)
+	a.mu.Unlock()
+	a.log.Warn("Dropping chunked message", slog.String("message_id", id), slog.String("reason", "timed out"))
+	_ = a.nak(id, p.seq, fmt.Sprintf("timed out after %v waiting for chunk %d/%d", a.opts.Timeout, p.next+1, p.count))
+}
+
+// dropLocked forgets a partial message. Caller holds a.mu.
+func (a *chunkAssembler) dropLocked(id string) {
+	if p, ok := a.partial[id]; ok {
+		p.timer.Stop()
+		delete(a.partial, id)
+	}
+}
+
+// pending returns the number of partially received messages.
+func (a *chunkAssembler) pending() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.partial)
+}
+
+// close drops every partial message.
+func (a *chunkAssembler) close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+	for id := range a.partial {
+		a.dropLocked(id)
+	}
+}
@@ -0,0 +1,195 @@
+package simsdk
+
+import (
+	"bytes"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// nakRecorder collects the naks sent by a chunkAssembler.
+type nakRecorder struct {
+	mu      sync.Mutex
+	reasons map[string]string
+}
+
+func (n *nakRecorder) nak(messageID string, _ uint64, reason string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.reasons == nil {
+		n.reasons = make(map[string]string)
+	}
+	n.reasons[messageID] = reason
+	return nil
+}
+
+func (n *nakRecorder) reason(messageID string) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.reasons[messageID]
+}
+
+func TestChunking_SplitAndReassemble(t *testing.T) {
+	payload := []byte("0123456789")
+	msg := &simsdkrpc.SimMessage{MessageId: "tile", MessageType: "Terrain", Payload: payload, Metadata: map[string]string{"lod": "2"}}
+	chunks := splitMessage(msg, 4)
+	require.Len(t, chunks, 3)
+	require.Equal(t, []byte("89"), chunks[2].Data)
+	require.Empty(t, chunks[0].Header.Payload)
+	require.Equal(t, "2", chunks[0].Header.Metadata["lod"])
+	require.Nil(t, chunks[1].Header)
+	require.Equal(t, payload, msg.Payload, "splitting leaves the original message intact")
+
+	var naks nakRecorder
+	a := newChunkAssembler(ChunkingOptions{}.withDefaults(), naks.nak, slog.Default())
+	defer a.close()
+	require.Nil(t, a.add(chunks[0], 9))
+	require.Nil(t, a.add(chunks[1], 9))
+	require.Equal(t, 1, a.pending())
+	got := a.add(chunks[2], 9)
+	require.NotNil(t, got)
+	require.Equal(t, payload, got.Payload)
+	require.Equal(t, "Terrain", got.MessageType)
+	require.Zero(t, a.pending())
+}
+
+func TestChunking_AssemblerLimits(t *testing.T) {
+	var naks nakRecorder
+	a := newChunkAssembler(ChunkingOptions{MaxMessageSize: 8, MaxPartial: 1, Timeout: 20 * time.Millisecond}.withDefaults(), naks.nak, slog.Default())
+	defer a.close()
+
+	big := splitMessage(&simsdkrpc.SimMessage{MessageId: "big", Payload: make([]byte, 9)}, 4)
+	require.Nil(t, a.add(big[0], 0))
+	require.Contains(t, naks.reason("big"), "exceeds")
+
+	ooo := splitMessage(&simsdkrpc.SimMessage{MessageId: "ooo", Payload: make([]byte, 8)}, 2)
+	require.Nil(t, a.add(ooo[0], 0))
+	require.Nil(t, a.add(ooo[2], 0))
+	require.Contains(t, naks.reason("ooo"), "out of order")
+	require.Nil(t, a.add(ooo[3], 0))
+	require.Contains(t, naks.reason("ooo"), "without the first chunk")
+
+	first := splitMessage(&simsdkrpc.SimMessage{MessageId: "first", Payload: make([]byte, 4)}, 2)
+	second := splitMessage(&simsdkrpc.SimMessage{MessageId: "second", Payload: make([]byte, 4)}, 2)
+	require.Nil(t, a.add(first[0], 0))
+	require.Nil(t, a.add(second[0], 0))
+	require.Contains(t, naks.reason("second"), "too many")
+
+	require.Eventually(t, func() bool { return naks.reason("first") != "" }, time.Second, time.Millisecond)
+	require.Contains(t, naks.reason("first"), "timed out")
+	require.Zero(t, a.pending())
+}
+
+// echoHandler returns each message's payload in a response.
+type echoHandler struct {
+	recordingHandler
+	payloads [][]byte
+}
+
+func (h *echoHandler) OnSimMessage(msg *SimMessage) ([]*SimMessage, error) {
+	h.payloads = append(h.payloads, msg.Payload)
+	return []*SimMessage{{MessageID: "echo-" + msg.MessageID, Payload: msg.Payload}}, nil
+}
+
+func TestServeStream_ChunkedPayloadsAreTransparent(t *testing.T) {
+	payload := bytes.Repeat([]byte("frame"), 100)
+	var incoming []*simsdkrpc.PluginMessageEnvelope
+	incoming = append(incoming, initEnvelope("cam"))
+	for _, c := range splitMessage(&simsdkrpc.SimMessage{MessageId: "f1", ComponentId: "cam", Payload: payload}, 64) {
+		incoming = append(incoming, &simsdkrpc.PluginMessageEnvelope{Content: &simsdkrpc.PluginMessageEnvelope_Chunk{Chunk: c}, Sequence: 3})
+	}
+	handler := &echoHandler{}
+	stream := &mockStream{incoming: incoming}
+
+	require.NoError(t, ServeStream(handler, stream, WithChunking(ChunkingOptions{ChunkSize: 128})))
+	require.Equal(t, [][]byte{payload}, handler.payloads)
+
+	var naks nakRecorder
+	a := newChunkAssembler(ChunkingOptions{}.withDefaults(), naks.nak, slog.Default())
+	defer a.close()
+	var echoed *simsdkrpc.SimMessage
+	var chunks int
+	for _, env := range stream.sent {
+		require.Nil(t, env.GetSimMessage(), "the large response is only sent in chunks")
+		if c := env.GetChunk(); c != nil {
+			chunks++
+			require.LessOrEqual(t, len(c.Data), 128)
+			if m := a.add(c, env.Sequence); m != nil {
+				echoed = m
+			}
+		}
+		if ack := env.GetAck(); ack != nil {
+			require.Equal(t, uint64(3), ack.Sequence)
+		}
+	}
+	require.Equal(t, 4, chunks)
+	require.NotNil(t, echoed)
+	require.Equal(t, "echo-f1", echoed.MessageId)
+	require.Equal(t, payload, echoed.Payload)
+}
+
+func TestServeStream_ChunkingIsOptIn(t *testing.T) {
+	payload := bytes.Repeat([]byte("frame"), 300_000) // larger than DefaultChunkSize
+	handler := &echoHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("cam"),
+		{Content: &simsdkrpc.PluginMessageEnvelope_SimMessage{SimMessage: &simsdkrpc.SimMessage{MessageId: "f1", ComponentId: "cam", Payload: payload}}},
+	}}
+
+	require.NoError(t, ServeStream(handler, stream))
+	var echoed int
+	for _, env := range stream.sent {
+		require.Nil(t, env.GetChunk(), "without WithChunking a core that cannot reassemble chunks gets whole messages")
+		if m := env.GetSimMessage(); m != nil && m.MessageId == "echo-f1" {
+			echoed++
+			require.Equal(t, payload, m.Payload)
+		}
+	}
+	require.Equal(t, 1, echoed)
+}
+
+func TestChunking_SplitsLargeBatches(t *testing.T) {
+	out := &mockStream{}
+	c := &chunkingSender{out: out, size: 64}
+	batch := &simsdkrpc.MessageBatch{Messages: []*simsdkrpc.SimMessage{
+		{MessageId: "a", Payload: make([]byte, 20)},
+		{MessageId: "b", Payload: make([]byte, 20)},
+		{MessageId: "big", Payload: make([]byte, 150)},
+		{MessageId: "c", Payload: make([]byte, 20)},
+	}}
+	require.NoError(t, c.Send(&simsdkrpc.PluginMessageEnvelope{Content: &simsdkrpc.PluginMessageEnvelope_Batch{Batch: batch}}))
+
+	var naks nakRecorder
+	a := newChunkAssembler(ChunkingOptions{}.withDefaults(), naks.nak, slog.Default())
+	defer a.close()
+	var ids []string
+	for _, env := range out.sent {
+		switch {
+		case env.GetBatch() != nil:
+			require.LessOrEqual(t, proto.Size(env.GetBatch()), 64)
+			for _, m := range env.GetBatch().GetMessages() {
+				ids = append(ids, m.MessageId)
+			}
+		case env.GetChunk() != nil:
+			require.LessOrEqual(t, len(env.GetChunk().Data), 64)
+			if m := a.add(env.GetChunk(), env.Sequence); m != nil {
+				ids = append(ids, m.MessageId)
+				require.Len(t, m.Payload, 150)
+			}
+		default:
+			t.Fatalf("unexpected envelope %v", env)
+		}
+	}
+	require.Equal(t, []string{"a", "b", "big", "c"}, ids, "every message is sent once, in order")
+
+	small := &simsdkrpc.PluginMessageEnvelope{Content: &simsdkrpc.PluginMessageEnvelope_Batch{Batch: &simsdkrpc.MessageBatch{
+		Messages: []*simsdkrpc.SimMessage{{MessageId: "d"}},
+	}}}
+	require.NoError(t, c.Send(small))
+	require.Same(t, small, out.sent[len(out.sent)-1], "a batch that fits is sent as is")
+}
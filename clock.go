@@ -0,0 +1,401 @@
+package simsdk
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Clock tells simulation time. Plugin code should use the Clock it is given
+// instead of the time package so that it follows the core's timeline,
+// including time scaling and pauses.
+type Clock interface {
+	// Now returns the current simulation time.
+	Now() time.Time
+	// After returns a channel that receives the simulation time once d of
+	// simulation time has passed.
+	After(d time.Duration) <-chan time.Time
+	// Sleep blocks until d of simulation time has passed.
+	Sleep(d time.Duration)
+	// NewTicker returns a ticker that fires every d of simulation time.
+	NewTicker(d time.Duration) *Ticker
+	// Scale returns how many seconds of simulation time pass per wall-clock second.
+	Scale() float64
+	// Paused reports whether simulation time is frozen.
+	Paused() bool
+}
+
+// ClockSetter is implemented by handlers that want the stream's simulation clock.
+type ClockSetter interface {
+	SetClock(clock Clock)
+}
+
+// Ticker delivers simulation time on C at a fixed simulation-time interval.
+// Like time.Ticker, it drops ticks for slow receivers.
+type Ticker struct {
+	C    <-chan time.Time
+	stop func()
+}
+
+// Stop turns off the ticker. It does not close C.
+func (t *Ticker) Stop() {
+	t.stop()
+}
+
+// stampTimes fills in the simulation and wall-clock times of an outbound
+// message that does not set them. The simulation time is left unset while the
+// clock does not follow the core's timeline yet.
+func stampTimes(m *simsdkrpc.SimMessage, clock Clock) {
+	if m.SimTime == nil && isSynced(clock) {
+		m.SimTime = timestamppb.New(clock.Now())
+	}
+	if m.WallTime == nil {
+		m.WallTime = timestamppb.Now()
+	}
+}
+
+// waitCanceler is implemented by the SDK's clocks so a pending After the
+// caller no longer needs can be dropped.
+type waitCanceler interface {
+	cancelAfter(ch <-chan time.Time)
+}
+
+// isSynced reports whether clock tells simulation time. A SimClock does once
+// it has been synced; other clocks are set explicitly and always do.
+func isSynced(clock Clock) bool {
+	if c, ok := clock.(*SimClock); ok {
+		return c.Synced()
+	}
+	return clock != nil
+}
+
+// clockWaiter is a pending After, Sleep or Ticker.
+type clockWaiter struct {
+	at     time.Time
+	period time.Duration // zero for one-shot waiters
+	ch     chan time.Time
+}
+
+// timerQueue holds the waiters of a clock. Callers hold the clock's lock.
+type timerQueue struct {
+	waiters []*clockWaiter
+}
+
+func (q *timerQueue) add(w *clockWaiter) {
+	q.waiters = append(q.waiters, w)
+}
+
+// removeChan removes the waiter delivering on ch.
+func (q *timerQueue) removeChan(ch <-chan time.Time) {
+	for _, w := range q.waiters {
+		if w.ch == ch {
+			q.remove(w)
+			return
+		}
+	}
+}
+
+func (q *timerQueue) remove(w *clockWaiter) {
+	for i, x := range q.waiters {
+		if x == w {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// next returns the earliest deadline.
+func (q *timerQueue) next() (time.Time, bool) {
+	if len(q.waiters) == 0 {
+		return time.Time{}, false
+	}
+	earliest := q.waiters[0].at
+	for _, w := range q.waiters[1:] {
+		if w.at.Before(earliest) {
+			earliest = w.at
+		}
+	}
+	return earliest, true
+}
+
+// fire delivers every waiter due at now, in deadline order. Tickers are
+// rescheduled past now; one-shot waiters are removed.
+func (q *timerQueue) fire(now time.Time) {
+	var due []*clockWaiter
+	keep := q.waiters[:0]
+	for _, w := range q.waiters {
+		if w.at.After(now) {
+			keep = append(keep, w)
+		} else {
+			due = append(due, w)
+		}
+	}
+	q.waiters = keep
+	sort.SliceStable(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+
+	for _, w := range due {
+		select {
+		case w.ch <- now:
+		default:
+		}
+		if w.period > 0 {
+			for !w.at.After(now) {
+				w.at = w.at.Add(w.period)
+			}
+			q.waiters = append(q.waiters, w)
+		}
+	}
+}
+
+// SimClock follows the core's simulation clock. Between clock syncs it
+// advances at Scale times wall-clock speed, and stands still while paused. A
+// new SimClock runs at wall-clock speed from its start time.
+type SimClock struct {
+	mu       sync.Mutex
+	base     time.Time // simulation time at wallBase
+	wallBase time.Time
+	scale    float64
+	paused   bool
+	synced   bool
+	queue    timerQueue
+	timer    *time.Timer
+}
+
+var _ Clock = (*SimClock)(nil)
+
+// NewSimClock returns a running clock that reads start now.
+func NewSimClock(start time.Time) *SimClock {
+	return &SimClock{base: start, wallBase: time.Now(), scale: 1}
+}
+
+// Sync sets the clock from a core clock sync: simTime was the simulation time
+// at wallTime. A zero wallTime means now; a non-positive scale means 1.
+func (c *SimClock) Sync(simTime, wallTime time.Time, scale float64, paused bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if scale <= 0 {
+		scale = 1
+	}
+	now := time.Now()
+	if !wallTime.IsZero() && !paused {
+		simTime = simTime.Add(time.Duration(float64(now.Sub(wallTime)) * scale))
+	}
+	c.base, c.wallBase, c.scale, c.paused = simTime, now, scale, paused
+	c.synced = true
+	c.update()
+}
+
+// Synced reports whether Sync has been called. Until then the clock only
+// counts wall-clock time from its start, and the SDK does not stamp outbound
+// messages or hold responses with it.
+func (c *SimClock) Synced() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.synced
+}
+
+// SetScale changes how fast simulation time passes.
+func (c *SimClock) SetScale(scale float64) {
+	if scale <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rebase()
+	c.scale = scale
+	c.update()
+}
+
+// Pause freezes simulation time.
+func (c *SimClock) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rebase()
+	c.paused = true
+	c.update()
+}
+
+// Resume lets simulation time advance again.
+func (c *SimClock) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rebase()
+	c.paused = false
+	c.update()
+}
+
+func (c *SimClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now()
+}
+
+func (c *SimClock) Scale() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.scale
+}
+
+func (c *SimClock) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused
+}
+
+func (c *SimClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &clockWaiter{at: c.now().Add(d), ch: make(chan time.Time, 1)}
+	c.queue.add(w)
+	c.update()
+	return w.ch
+}
+
+func (c *SimClock) cancelAfter(ch <-chan time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queue.removeChan(ch)
+	c.update()
+}
+
+func (c *SimClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+func (c *SimClock) NewTicker(d time.Duration) *Ticker {
+	if d <= 0 {
+		panic("simsdk: non-positive interval for NewTicker")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &clockWaiter{at: c.now().Add(d), period: d, ch: make(chan time.Time, 1)}
+	c.queue.add(w)
+	c.update()
+	return &Ticker{C: w.ch, stop: func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.queue.remove(w)
+		c.update()
+	}}
+}
+
+// now returns the simulation time. Caller holds c.mu.
+func (c *SimClock) now() time.Time {
+	if c.paused {
+		return c.base
+	}
+	return c.base.Add(time.Duration(float64(time.Since(c.wallBase)) * c.scale))
+}
+
+// rebase moves the reference point to now so the scale or pause state can
+// change without a jump. Caller holds c.mu.
+func (c *SimClock) rebase() {
+	c.base = c.now()
+	c.wallBase = time.Now()
+}
+
+// update fires due waiters and arms a wall-clock timer for the next one.
+// Caller holds c.mu.
+func (c *SimClock) update() {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	now := c.now()
+	c.queue.fire(now)
+	next, ok := c.queue.next()
+	if !ok || c.paused {
+		return
+	}
+	wait := time.Duration(float64(next.Sub(now)) / c.scale)
+	c.timer = time.AfterFunc(wait, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.update()
+	})
+}
+
+// ManualClock is a Clock for tests. Time only moves when Set or Advance is
+// called, firing any waiters that come due.
+type ManualClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	queue timerQueue
+}
+
+var _ Clock = (*ManualClock)(nil)
+
+// NewManualClock returns a clock that reads start until it is advanced.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Advance moves the clock forward by d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.queue.fire(c.now)
+}
+
+// Set moves the clock to t. Moving backwards does not fire anything.
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+	c.queue.fire(c.now)
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &clockWaiter{at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	c.queue.add(w)
+	c.queue.fire(c.now)
+	return w.ch
+}
+
+func (c *ManualClock) cancelAfter(ch <-chan time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queue.removeChan(ch)
+}
+
+func (c *ManualClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+func (c *ManualClock) NewTicker(d time.Duration) *Ticker {
+	if d <= 0 {
+		panic("simsdk: non-positive interval for NewTicker")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &clockWaiter{at: c.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	c.queue.add(w)
+	return &Ticker{C: w.ch, stop: func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.queue.remove(w)
+	}}
+}
+
+func (c *ManualClock) Scale() float64 { return 1 }
+func (c *ManualClock) Paused() bool   { return false }
+
+// Waiters returns the number of pending timers, so tests can wait until code
+// under test is blocked on the clock before advancing it.
+func (c *ManualClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.queue.waiters)
+}
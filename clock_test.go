@@ -0,0 +1,153 @@
+package simsdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var epoch = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestManualClock_AfterAndTicker(t *testing.T) {
+	c := NewManualClock(epoch)
+	after := c.After(10 * time.Second)
+	ticker := c.NewTicker(4 * time.Second)
+	defer ticker.Stop()
+
+	c.Advance(5 * time.Second)
+	require.Equal(t, epoch.Add(5*time.Second), <-ticker.C)
+	select {
+	case <-after:
+		t.Fatal("After fired early")
+	default:
+	}
+
+	c.Advance(5 * time.Second)
+	require.Equal(t, epoch.Add(10*time.Second), <-after)
+	require.Equal(t, epoch.Add(10*time.Second), <-ticker.C)
+	require.Equal(t, 1, c.Waiters())
+
+	ticker.Stop()
+	require.Zero(t, c.Waiters())
+}
+
+func TestManualClock_SleepBlocksUntilAdvanced(t *testing.T) {
+	c := NewManualClock(epoch)
+	done := make(chan struct{})
+	go func() {
+		c.Sleep(time.Minute)
+		close(done)
+	}()
+	require.Eventually(t, func() bool { return c.Waiters() == 1 }, time.Second, time.Millisecond)
+	c.Advance(time.Minute)
+	<-done
+}
+
+func TestSimClock_ScaleAndPause(t *testing.T) {
+	c := NewSimClock(epoch)
+	c.Sync(epoch, time.Time{}, 100, false)
+	time.Sleep(20 * time.Millisecond)
+	require.GreaterOrEqual(t, c.Now().Sub(epoch), 2*time.Second)
+	require.Equal(t, 100.0, c.Scale())
+
+	c.Pause()
+	frozen := c.Now()
+	time.Sleep(5 * time.Millisecond)
+	require.Equal(t, frozen, c.Now())
+	require.True(t, c.Paused())
+
+	c.Resume()
+	require.False(t, c.Paused())
+	require.True(t, c.Now().After(frozen) || c.Now().Equal(frozen))
+}
+
+func TestSimClock_TimersFollowScale(t *testing.T) {
+	c := NewSimClock(epoch)
+	c.SetScale(1000)
+
+	start := time.Now()
+	c.Sleep(10 * time.Second) // 10ms of wall time at 1000x
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestSimClock_PausedTimersDoNotFire(t *testing.T) {
+	c := NewSimClock(epoch)
+	c.Pause()
+	after := c.After(time.Millisecond)
+	select {
+	case <-after:
+		t.Fatal("timer fired while paused")
+	case <-time.After(20 * time.Millisecond):
+	}
+	c.Resume()
+	select {
+	case <-after:
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire after resume")
+	}
+}
+
+func TestServeStream_ClockSyncAndTimestamps(t *testing.T) {
+	clock := NewSimClock(time.Now())
+	handler := &recordingHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		{Content: &simsdkrpc.PluginMessageEnvelope_ClockSync{ClockSync: &simsdkrpc.PluginClockSync{
+			SimTime: timestamppb.New(epoch),
+			Paused:  true,
+		}}},
+		simMessageEnvelope("m1", "a"),
+	}}
+
+	require.NoError(t, ServeStream(handler, stream, WithClock(clock)))
+	require.Equal(t, epoch, clock.Now())
+	require.True(t, clock.Paused())
+
+	var pushed *simsdkrpc.SimMessage
+	for _, env := range stream.sent {
+		if m := env.GetSimMessage(); m != nil && m.MessageId == "pushed-m1" {
+			pushed = m
+		}
+	}
+	require.NotNil(t, pushed)
+	require.Equal(t, epoch, pushed.SimTime.AsTime())
+	require.NotNil(t, pushed.WallTime)
+}
+
+func TestServeStream_NoSimTimeBeforeClockSync(t *testing.T) {
+	handler := &recordingHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		simMessageEnvelope("m1", "a"),
+	}}
+	require.NoError(t, ServeStream(handler, stream))
+
+	var pushed *simsdkrpc.SimMessage
+	for _, env := range stream.sent {
+		if m := env.GetSimMessage(); m != nil && m.MessageId == "pushed-m1" {
+			pushed = m
+		}
+	}
+	require.NotNil(t, pushed)
+	require.Nil(t, pushed.SimTime, "an unsynced clock only knows wall-clock time")
+	require.NotNil(t, pushed.WallTime)
+}
+
+func TestSimClock_Synced(t *testing.T) {
+	c := NewSimClock(epoch)
+	require.False(t, c.Synced())
+	c.Sync(epoch, time.Time{}, 1, false)
+	require.True(t, c.Synced())
+}
+
+func TestConverter_SimMessageTimes(t *testing.T) {
+	m := &SimMessage{MessageID: "m", SimTime: epoch, WallTime: epoch.Add(time.Hour)}
+	got := FromProtoSimMessage(ToProtoSimMessage(m))
+	require.Equal(t, m.SimTime, got.SimTime)
+	require.Equal(t, m.WallTime, got.WallTime)
+
+	require.True(t, FromProtoSimMessage(ToProtoSimMessage(&SimMessage{})).SimTime.IsZero())
+}
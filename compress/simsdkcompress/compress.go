@@ -0,0 +1,101 @@
+// Package simsdkcompress provides zstd and snappy payload codecs for the SDK's
+// stream compression. It is a separate module, so only plugins that want
+// these codecs depend on third-party compression packages:
+//
+//	simsdkcompress.Register()
+//	simsdk.NewGRPCAdapter(plugin, simsdk.WithStreamOptions(simsdk.WithCompression(simsdk.CompressionOptions{
+//		Codecs: []string{simsdkcompress.ZstdCodec, simsdkcompress.SnappyCodec, simsdk.GzipCodec},
+//	})))
+package simsdkcompress
+
+import (
+	"fmt"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/neurosimio/simsdk-go"
+)
+
+const (
+	// ZstdCodec is the name of the zstd codec.
+	ZstdCodec = "zstd"
+	// SnappyCodec is the name of the snappy codec, which uses the snappy
+	// block format.
+	SnappyCodec = "snappy"
+)
+
+// Register makes zstd, at its default level, and snappy available for
+// negotiation. Call it before serving.
+func Register() {
+	zc, err := NewZstdCompressor(zstd.SpeedDefault)
+	if err != nil {
+		panic(err) // the default level is always valid
+	}
+	simsdk.RegisterCompressor(zc)
+	simsdk.RegisterCompressor(NewSnappyCompressor())
+}
+
+// NewZstdCompressor returns the zstd codec at the given level. Register it
+// with simsdk.RegisterCompressor to trade ratio for speed.
+func NewZstdCompressor(level zstd.EncoderLevel) (simsdk.Compressor, error) {
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
+	if err != nil {
+		return nil, err
+	}
+	dec, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(simsdk.DefaultMaxDecompressedSize)))
+	if err != nil {
+		return nil, err
+	}
+	return &zstdCompressor{enc: enc, dec: dec}, nil
+}
+
+// zstdCompressor uses EncodeAll and DecodeAll, which are safe for concurrent use.
+type zstdCompressor struct {
+	enc *zstd.Encoder
+	dec *zstd.Decoder
+}
+
+func (c *zstdCompressor) Name() string { return ZstdCodec }
+
+func (c *zstdCompressor) Compress(src []byte) ([]byte, error) {
+	return c.enc.EncodeAll(src, nil), nil
+}
+
+func (c *zstdCompressor) Decompress(src []byte, limit int) ([]byte, error) {
+	// Frames written by EncodeAll declare their size, so most oversized
+	// payloads are rejected before they are decoded.
+	var h zstd.Header
+	if err := h.Decode(src); err == nil && h.HasFCS && h.FrameContentSize > uint64(limit) {
+		return nil, fmt.Errorf("decompressed payload exceeds %d bytes", limit)
+	}
+	out, err := c.dec.DecodeAll(src, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) > limit {
+		return nil, fmt.Errorf("decompressed payload exceeds %d bytes", limit)
+	}
+	return out, nil
+}
+
+// NewSnappyCompressor returns the snappy codec.
+func NewSnappyCompressor() simsdk.Compressor { return snappyCompressor{} }
+
+type snappyCompressor struct{}
+
+func (snappyCompressor) Name() string { return SnappyCodec }
+
+func (snappyCompressor) Compress(src []byte) ([]byte, error) {
+	return snappy.Encode(nil, src), nil
+}
+
+func (snappyCompressor) Decompress(src []byte, limit int) ([]byte, error) {
+	n, err := snappy.DecodedLen(src)
+	if err != nil {
+		return nil, err
+	}
+	if n > limit {
+		return nil, fmt.Errorf("decompressed payload exceeds %d bytes", limit)
+	}
+	return snappy.Decode(nil, src)
+}
@@ -0,0 +1,112 @@
+package simsdkcompress
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/neurosimio/simsdk-go"
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+var payload = bytes.Repeat([]byte(`{"sensor":"temp-001","value":21.25,"unit":"celsius"},`), 200)
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	Register()
+	for _, codec := range []string{ZstdCodec, SnappyCodec} {
+		t.Run(codec, func(t *testing.T) {
+			msg := &simsdk.SimMessage{Payload: payload}
+			require.NoError(t, simsdk.CompressPayload(msg, codec))
+			require.Less(t, len(msg.Payload), len(payload)/5)
+			require.Equal(t, codec, msg.Metadata[simsdk.ContentEncodingKey])
+
+			require.NoError(t, simsdk.DecompressPayload(msg))
+			require.Equal(t, payload, msg.Payload)
+			require.NotContains(t, msg.Metadata, simsdk.ContentEncodingKey)
+		})
+	}
+}
+
+func TestCodecs_EnforceLimit(t *testing.T) {
+	zc, err := NewZstdCompressor(zstd.SpeedFastest)
+	require.NoError(t, err)
+	for _, c := range []simsdk.Compressor{zc, NewSnappyCompressor()} {
+		packed, err := c.Compress(make([]byte, 1<<20))
+		require.NoError(t, err)
+		_, err = c.Decompress(packed, 1<<10)
+		require.ErrorContains(t, err, "exceeds", c.Name())
+		out, err := c.Decompress(packed, 1<<20)
+		require.NoError(t, err, c.Name())
+		require.Len(t, out, 1<<20)
+	}
+}
+
+// fakeStream plays the core: it replays in and records what the plugin sends.
+type fakeStream struct {
+	grpc.ServerStream
+	in   []*simsdkrpc.PluginMessageEnvelope
+	sent []*simsdkrpc.PluginMessageEnvelope
+}
+
+func (s *fakeStream) Context() context.Context { return context.Background() }
+
+func (s *fakeStream) Recv() (*simsdkrpc.PluginMessageEnvelope, error) {
+	if len(s.in) == 0 {
+		return nil, io.EOF
+	}
+	env := s.in[0]
+	s.in = s.in[1:]
+	return env, nil
+}
+
+func (s *fakeStream) Send(env *simsdkrpc.PluginMessageEnvelope) error {
+	s.sent = append(s.sent, env)
+	return nil
+}
+
+// echoHandler records inbound payloads and echoes each one back.
+type echoHandler struct{ payloads [][]byte }
+
+func (h *echoHandler) OnInit(*simsdkrpc.PluginInit) error { return nil }
+func (h *echoHandler) OnShutdown(string)                  {}
+func (h *echoHandler) OnSimMessage(msg *simsdk.SimMessage) ([]*simsdk.SimMessage, error) {
+	h.payloads = append(h.payloads, msg.Payload)
+	return []*simsdk.SimMessage{{MessageID: "echo-" + msg.MessageID, Payload: msg.Payload}}, nil
+}
+
+func TestServeStream_NegotiatesRegisteredCodecs(t *testing.T) {
+	Register()
+	for _, codec := range []string{ZstdCodec, SnappyCodec} {
+		t.Run(codec, func(t *testing.T) {
+			inbound := &simsdk.SimMessage{MessageID: "in", ComponentID: "a", Payload: payload}
+			require.NoError(t, simsdk.CompressPayload(inbound, codec))
+
+			handler := &echoHandler{}
+			stream := &fakeStream{in: []*simsdkrpc.PluginMessageEnvelope{
+				{Content: &simsdkrpc.PluginMessageEnvelope_Init{Init: &simsdkrpc.PluginInit{ComponentId: "a"}}},
+				{Content: &simsdkrpc.PluginMessageEnvelope_Compression{Compression: &simsdkrpc.PluginCompression{Accepted: []string{codec, simsdk.GzipCodec}}}},
+				{Content: &simsdkrpc.PluginMessageEnvelope_SimMessage{SimMessage: simsdk.ToProtoSimMessage(inbound)}},
+			}}
+			opts := simsdk.CompressionOptions{Codecs: []string{ZstdCodec, SnappyCodec, simsdk.GzipCodec}}
+			require.NoError(t, simsdk.ServeStream(handler, stream, simsdk.WithCompression(opts)))
+			require.Equal(t, [][]byte{payload}, handler.payloads, "the handler sees the decoded payload")
+
+			require.Equal(t, opts.Codecs, stream.sent[0].GetCompression().GetAccepted(), "the offer lists both codecs")
+			var echo *simsdkrpc.SimMessage
+			for _, env := range stream.sent {
+				if sm := env.GetSimMessage(); sm != nil {
+					echo = sm
+				}
+			}
+			require.NotNil(t, echo)
+			require.Equal(t, codec, echo.Metadata[simsdk.ContentEncodingKey], "the core's preferred codec is used")
+			decoded := simsdk.FromProtoSimMessage(echo)
+			require.NoError(t, simsdk.DecompressPayload(decoded))
+			require.Equal(t, payload, decoded.Payload)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+module github.com/neurosimio/simsdk-go/compress/simsdkcompress
+
+go 1.24.5
+
+require (
+	github.com/golang/snappy v1.0.0
+	github.com/klauspost/compress v1.18.0
+	github.com/neurosimio/simsdk-go v0.0.0
+	github.com/stretchr/testify v1.10.0
+	google.golang.org/grpc v1.74.2
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	golang.org/x/net v0.40.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.25.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250528174236-200df99c418a // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The codecs are developed against the SDK in this repository.
+replace github.com/neurosimio/simsdk-go => ../..
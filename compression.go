@@ -0,0 +1,279 @@
+package simsdk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+)
+
+// ContentEncodingKey is the SimMessage metadata key naming the codec the
+// payload was compressed with. Messages without it are uncompressed.
+const ContentEncodingKey = "simsdk-content-encoding"
+
+const (
+	// DefaultCompressionThreshold is the smallest payload worth compressing.
+	DefaultCompressionThreshold = 1024
+	// DefaultMaxDecompressedSize caps decompressed payloads, so a small
+	// message cannot expand without bound.
+	DefaultMaxDecompressedSize = DefaultMaxChunkedMessageSize
+	// GzipCodec is the name of the built-in gzip codec.
+	GzipCodec = "gzip"
+)
+
+// Compressor is a payload codec. Implementations must be safe for concurrent use.
+type Compressor interface {
+	Name() string
+	Compress(src []byte) ([]byte, error)
+	// Decompress fails if the result would exceed limit bytes.
+	Decompress(src []byte, limit int) ([]byte, error)
+}
+
+var (
+	compressorsMu sync.RWMutex
+	compressors   = map[string]Compressor{GzipCodec: NewGzipCompressor(gzip.DefaultCompression)}
+)
+
+// RegisterCompressor makes a codec available for negotiation, replacing any
+// codec with the same name. The SDK only builds in gzip; the optional
+// compress/simsdkcompress module registers zstd and snappy this way.
+func RegisterCompressor(c Compressor) {
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+	compressors[c.Name()] = c
+}
+
+func compressorFor(name string) (Compressor, bool) {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+	c, ok := compressors[name]
+	return c, ok
+}
+
+// NewGzipCompressor returns the gzip codec at the given compression level.
+// Register it to trade ratio for speed, e.g. with gzip.BestSpeed.
+func NewGzipCompressor(level int) Compressor {
+	c := &gzipCompressor{}
+	c.writers.New = func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, level)
+		return w
+	}
+	return c
+}
+
+type gzipCompressor struct {
+	writers sync.Pool
+	readers sync.Pool
+}
+
+func (c *gzipCompressor) Name() string { return GzipCodec }
+
+func (c *gzipCompressor) Compress(src []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := c.writers.Get().(*gzip.Writer)
+	defer c.writers.Put(w)
+	w.Reset(&buf)
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *gzipCompressor) Decompress(src []byte, limit int) ([]byte, error) {
+	r, ok := c.readers.Get().(*gzip.Reader)
+	if ok {
+		if err := r.Reset(bytes.NewReader(src)); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if r, err = gzip.NewReader(bytes.NewReader(src)); err != nil {
+			return nil, err
+		}
+	}
+	defer c.readers.Put(r)
+	out, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(out) > limit {
+		return nil, fmt.Errorf("decompressed payload exceeds %d bytes", limit)
+	}
+	return out, nil
+}
+
+// CompressionOptions configures payload compression on a stream.
+type CompressionOptions struct {
+	Codecs              []string // Codecs to offer, most preferred first; defaults to gzip
+	Threshold           int      // Payloads smaller than this are sent as is
+	MaxDecompressedSize int      // Largest payload accepted after decompression
+}
+
+func (o CompressionOptions) withDefaults() CompressionOptions {
+	if len(o.Codecs) == 0 {
+		o.Codecs = []string{GzipCodec}
+	}
+	if o.Threshold <= 0 {
+		o.Threshold = DefaultCompressionThreshold
+	}
+	if o.MaxDecompressedSize <= 0 {
+		o.MaxDecompressedSize = DefaultMaxDecompressedSize
+	}
+	return o
+}
+
+// WithCompression offers the configured codecs to the core when the stream
+// opens and compresses outbound payloads with the first codec both sides
+// support. Compressed inbound payloads are decoded whether or not it is set.
+func WithCompression(opts CompressionOptions) StreamOption {
+	return func(o *streamOptions) { o.compression = &opts }
+}
+
+// CompressPayload compresses msg's payload with the named codec and records
+// the codec in its metadata. The metadata map is copied, not modified.
+func CompressPayload(msg *SimMessage, codec string) error {
+	c, ok := compressorFor(codec)
+	if !ok {
+		return NewError(ErrInvalidArgument, msg.ComponentID, "unknown compression codec %q", codec)
+	}
+	payload, err := c.Compress(msg.Payload)
+	if err != nil {
+		return err
+	}
+	msg.Payload, msg.Metadata = payload, withEncoding(msg.Metadata, codec)
+	return nil
+}
+
+// DecompressPayload decodes a payload compressed by CompressPayload or by a
+// stream, and removes the codec from msg's metadata. Uncompressed messages
+// are left alone.
+func DecompressPayload(msg *SimMessage) error {
+	payload, metadata, err := decompress(msg.Payload, msg.Metadata, DefaultMaxDecompressedSize)
+	if err != nil {
+		return WrapError(ErrInvalidArgument, msg.ComponentID, err)
+	}
+	msg.Payload, msg.Metadata = payload, metadata
+	return nil
+}
+
+func withEncoding(metadata map[string]string, codec string) map[string]string {
+	out := make(map[string]string, len(metadata)+1)
+	maps.Copy(out, metadata)
+	out[ContentEncodingKey] = codec
+	return out
+}
+
+func decompress(payload []byte, metadata map[string]string, limit int) ([]byte, map[string]string, error) {
+	codec, ok := metadata[ContentEncodingKey]
+	if !ok {
+		return payload, metadata, nil
+	}
+	c, ok := compressorFor(codec)
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown compression codec %q", codec)
+	}
+	out, err := c.Decompress(payload, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s payload: %w", codec, err)
+	}
+	metadata = maps.Clone(metadata)
+	delete(metadata, ContentEncodingKey)
+	return out, metadata, nil
+}
+
+// payloadCodec negotiates compression on one stream and applies it.
+type payloadCodec struct {
+	opts    *CompressionOptions // nil unless compression was enabled
+	maxSize int
+
+	mu     sync.RWMutex
+	chosen Compressor // codec the core accepts; nil until negotiated
+}
+
+func newPayloadCodec(opts *CompressionOptions) *payloadCodec {
+	c := &payloadCodec{maxSize: DefaultMaxDecompressedSize}
+	if opts != nil {
+		o := opts.withDefaults()
+		c.opts, c.maxSize = &o, o.MaxDecompressedSize
+	}
+	return c
+}
+
+// offer returns the announcement sent when the stream opens, or nil when
+// compression is disabled.
+func (c *payloadCodec) offer() *simsdkrpc.PluginMessageEnvelope {
+	if c.opts == nil {
+		return nil
+	}
+	var accepted []string
+	for _, name := range c.opts.Codecs {
+		if _, ok := compressorFor(name); ok {
+			accepted = append(accepted, name)
+		}
+	}
+	return &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_Compression{
+			Compression: &simsdkrpc.PluginCompression{Accepted: accepted},
+		},
+	}
+}
+
+// negotiate picks the core's most preferred codec among those we offer.
+func (c *payloadCodec) negotiate(peer *simsdkrpc.PluginCompression) {
+	if c.opts == nil {
+		return
+	}
+	var chosen Compressor
+	for _, name := range peer.GetAccepted() {
+		if slices.Contains(c.opts.Codecs, name) {
+			if comp, ok := compressorFor(name); ok {
+				chosen = comp
+				break
+			}
+		}
+	}
+	c.mu.Lock()
+	c.chosen = chosen
+	c.mu.Unlock()
+}
+
+// encode compresses an outbound payload once a codec has been negotiated.
+// Payloads under the threshold, already encoded, or that do not shrink are
+// sent as is.
+func (c *payloadCodec) encode(m *simsdkrpc.SimMessage) {
+	if c == nil {
+		return
+	}
+	c.mu.RLock()
+	comp := c.chosen
+	c.mu.RUnlock()
+	if comp == nil || len(m.Payload) < c.opts.Threshold {
+		return
+	}
+	if _, done := m.Metadata[ContentEncodingKey]; done {
+		return
+	}
+	payload, err := comp.Compress(m.Payload)
+	if err != nil || len(payload) >= len(m.Payload) {
+		return
+	}
+	m.Payload, m.Metadata = payload, withEncoding(m.Metadata, comp.Name())
+}
+
+// decode decompresses an inbound payload marked with a codec.
+func (c *payloadCodec) decode(m *simsdkrpc.SimMessage) error {
+	payload, metadata, err := decompress(m.Payload, m.Metadata, c.maxSize)
+	if err != nil {
+		return err
+	}
+	m.Payload, m.Metadata = payload, metadata
+	return nil
+}
@@ -0,0 +1,175 @@
+package simsdk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonPayload returns roughly n bytes of repetitive JSON, like sensor updates.
+func jsonPayload(n int) []byte {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; b.Len() < n; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"sensor":"temp-%03d","value":%d.25,"unit":"celsius","status":"ok"}`, i%100, 20+i%7)
+	}
+	b.WriteString("]")
+	return []byte(b.String())
+}
+
+func TestCompressPayload_RoundTrip(t *testing.T) {
+	payload := jsonPayload(4096)
+	meta := map[string]string{"k": "v"}
+	msg := &SimMessage{Payload: payload, Metadata: meta}
+
+	require.NoError(t, CompressPayload(msg, GzipCodec))
+	require.Less(t, len(msg.Payload), len(payload)/5)
+	require.Equal(t, GzipCodec, msg.Metadata[ContentEncodingKey])
+	require.NotContains(t, meta, ContentEncodingKey, "the caller's metadata is not modified")
+
+	require.NoError(t, DecompressPayload(msg))
+	require.Equal(t, payload, msg.Payload)
+	require.Equal(t, meta, msg.Metadata)
+
+	require.ErrorIs(t, CompressPayload(&SimMessage{}, "lz4"), ErrInvalidArgument)
+	require.ErrorIs(t, DecompressPayload(&SimMessage{Metadata: map[string]string{ContentEncodingKey: "lz4"}}), ErrInvalidArgument)
+}
+
+func TestGzipCompressor_EnforcesLimit(t *testing.T) {
+	c := NewGzipCompressor(gzip.BestSpeed)
+	packed, err := c.Compress(make([]byte, 1<<20))
+	require.NoError(t, err)
+	_, err = c.Decompress(packed, 1<<10)
+	require.ErrorContains(t, err, "exceeds")
+}
+
+func compressionEnvelope(codecs ...string) *simsdkrpc.PluginMessageEnvelope {
+	return &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_Compression{Compression: &simsdkrpc.PluginCompression{Accepted: codecs}},
+	}
+}
+
+func TestServeStream_NegotiatesCompression(t *testing.T) {
+	payload := jsonPayload(8192)
+	inbound := &SimMessage{MessageID: "in", ComponentID: "a", Payload: payload}
+	require.NoError(t, CompressPayload(inbound, GzipCodec))
+
+	handler := &echoHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		compressionEnvelope("zstd", GzipCodec),
+		{Content: &simsdkrpc.PluginMessageEnvelope_SimMessage{SimMessage: ToProtoSimMessage(inbound)}},
+		{Content: &simsdkrpc.PluginMessageEnvelope_SimMessage{SimMessage: &simsdkrpc.SimMessage{MessageId: "small", ComponentId: "a", Payload: []byte("{}")}}},
+	}}
+
+	require.NoError(t, ServeStream(handler, stream, WithCompression(CompressionOptions{})))
+	require.Equal(t, [][]byte{payload, []byte("{}")}, handler.payloads, "handlers see decoded payloads")
+
+	require.Equal(t, []string{GzipCodec}, stream.sent[0].GetCompression().GetAccepted(), "the offer is sent first")
+	var echoes []*simsdkrpc.SimMessage
+	for _, env := range stream.sent {
+		if sm := env.GetSimMessage(); sm != nil {
+			echoes = append(echoes, sm)
+		}
+	}
+	require.Len(t, echoes, 2)
+	require.Equal(t, GzipCodec, echoes[0].Metadata[ContentEncodingKey])
+	decoded := FromProtoSimMessage(echoes[0])
+	require.NoError(t, DecompressPayload(decoded))
+	require.Equal(t, payload, decoded.Payload)
+	require.NotContains(t, echoes[1].Metadata, ContentEncodingKey, "payloads under the threshold are sent as is")
+}
+
+func TestServeStream_NoCommonCodecSendsUncompressed(t *testing.T) {
+	handler := &echoHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		compressionEnvelope("zstd"),
+		{Content: &simsdkrpc.PluginMessageEnvelope_SimMessage{SimMessage: &simsdkrpc.SimMessage{MessageId: "m", ComponentId: "a", Payload: jsonPayload(4096)}}},
+	}}
+	require.NoError(t, ServeStream(handler, stream, WithCompression(CompressionOptions{})))
+	for _, env := range stream.sent {
+		if sm := env.GetSimMessage(); sm != nil {
+			require.NotContains(t, sm.Metadata, ContentEncodingKey)
+		}
+	}
+}
+
+// BenchmarkCompression compares codecs on JSON payloads of several sizes.
+// MB/s is uncompressed throughput; ratio is original/compressed size.
+func BenchmarkCompression(b *testing.B) {
+	codecs := []Compressor{
+		NewGzipCompressor(gzip.BestSpeed),
+		NewGzipCompressor(gzip.DefaultCompression),
+		NewGzipCompressor(gzip.BestCompression),
+	}
+	levels := []string{"gzip-fast", "gzip-default", "gzip-best"}
+	for _, size := range []int{1 << 10, 16 << 10, 256 << 10} {
+		payload := jsonPayload(size)
+		for i, c := range codecs {
+			packed, err := c.Compress(payload)
+			if err != nil {
+				b.Fatal(err)
+			}
+			ratio := float64(len(payload)) / float64(len(packed))
+
+			b.Run(fmt.Sprintf("%s/compress/%dKiB", levels[i], size>>10), func(b *testing.B) {
+				b.SetBytes(int64(len(payload)))
+				for b.Loop() {
+					if _, err := c.Compress(payload); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(ratio, "ratio")
+			})
+			b.Run(fmt.Sprintf("%s/decompress/%dKiB", levels[i], size>>10), func(b *testing.B) {
+				b.SetBytes(int64(len(payload)))
+				for b.Loop() {
+					if _, err := c.Decompress(packed, len(payload)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkStreamCompression measures a stream's end-to-end cost per message
+// with and without compression, including serialization of the envelope.
+func BenchmarkStreamCompression(b *testing.B) {
+	payload := jsonPayload(64 << 10)
+	for _, tc := range []struct {
+		name string
+		opts *CompressionOptions
+	}{
+		{"none", nil},
+		{"gzip", &CompressionOptions{}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			codec := newPayloadCodec(tc.opts)
+			codec.negotiate(&simsdkrpc.PluginCompression{Accepted: []string{GzipCodec}})
+			var wire int
+			b.SetBytes(int64(len(payload)))
+			for b.Loop() {
+				m := &simsdkrpc.SimMessage{MessageId: "m", Payload: payload}
+				codec.encode(m)
+				wire = len(m.Payload)
+				if err := codec.decode(m); err != nil {
+					b.Fatal(err)
+				}
+				if !bytes.Equal(m.Payload, payload) {
+					b.Fatal("payload mismatch")
+				}
+			}
+			b.ReportMetric(float64(wire), "wire-bytes/msg")
+		})
+	}
+}
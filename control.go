@@ -0,0 +1,33 @@
+package simsdk
+
+// ControlFunctionRequest asks a plugin to execute one of the control functions
+// declared in its manifest.
+type ControlFunctionRequest struct {
+	FunctionID  string            `json:"functionId"`           // Corresponds to ControlFunctionType.ID from manifest
+	ComponentID string            `json:"componentId"`          // Target component instance, if any
+	Parameters  map[string]string `json:"parameters,omitempty"` // Validated against ControlFunctionType.Fields
+	Payload     []byte            `json:"payload,omitempty"`    // Optional opaque input
+}
+
+// ControlFunctionResult is returned from a control function invocation.
+type ControlFunctionResult struct {
+	Result   []byte            `json:"result,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// ControlFunctionHandler is implemented by plugins that can execute control
+// functions at runtime. Parameters have already been validated against the
+// function's declared Fields when InvokeControlFunction is called.
+type ControlFunctionHandler interface {
+	InvokeControlFunction(req ControlFunctionRequest) (ControlFunctionResult, error)
+}
+
+// FindControlFunction returns the control function with the given ID from the manifest.
+func (m Manifest) FindControlFunction(id string) (ControlFunctionType, bool) {
+	for _, cf := range m.ControlFunctionTypes {
+		if cf.ID == id {
+			return cf, true
+		}
+	}
+	return ControlFunctionType{}, false
+}
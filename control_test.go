@@ -0,0 +1,132 @@
+package simsdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type controlPlugin struct {
+	dummyPlugin
+	last ControlFunctionRequest
+	err  error
+}
+
+func (p *controlPlugin) GetManifest() Manifest {
+	return Manifest{
+		Name: "control",
+		ControlFunctionTypes: []ControlFunctionType{
+			{ID: "set-speed", Fields: []FieldSpec{{Name: "speed", Type: FieldFloat, Required: true}}},
+		},
+	}
+}
+
+func (p *controlPlugin) InvokeControlFunction(req ControlFunctionRequest) (ControlFunctionResult, error) {
+	p.last = req
+	if p.err != nil {
+		return ControlFunctionResult{}, p.err
+	}
+	return ControlFunctionResult{
+		Result:   []byte("ok:" + req.Parameters["speed"]),
+		Metadata: map[string]string{"component": req.ComponentID},
+	}, nil
+}
+
+func TestGRPCAdapter_InvokeControlFunction(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   PluginWithHandlers
+		req      *simsdkrpc.ControlFunctionRequest
+		wantCode codes.Code
+	}{
+		{
+			name:   "success",
+			plugin: &controlPlugin{},
+			req: &simsdkrpc.ControlFunctionRequest{
+				FunctionId:  "set-speed",
+				ComponentId: "loco-1",
+				Parameters:  map[string]string{"speed": "40"},
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "plugin without handler",
+			plugin:   &dummyPlugin{},
+			req:      &simsdkrpc.ControlFunctionRequest{FunctionId: "set-speed"},
+			wantCode: codes.Unimplemented,
+		},
+		{
+			name:     "undeclared function",
+			plugin:   &controlPlugin{},
+			req:      &simsdkrpc.ControlFunctionRequest{FunctionId: "derail"},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:   "invalid parameters",
+			plugin: &controlPlugin{},
+			req: &simsdkrpc.ControlFunctionRequest{
+				FunctionId: "set-speed",
+				Parameters: map[string]string{"speed": "fast"},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:   "handler error",
+			plugin: &controlPlugin{err: NewError(ErrFailedPrecondition, "loco-1", "not running")},
+			req: &simsdkrpc.ControlFunctionRequest{
+				FunctionId: "set-speed",
+				Parameters: map[string]string{"speed": "1"},
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewGRPCAdapter(tt.plugin).InvokeControlFunction(context.Background(), tt.req)
+			require.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode != codes.OK {
+				return
+			}
+			require.Equal(t, []byte("ok:40"), resp.Result)
+			require.Equal(t, "loco-1", resp.Metadata["component"])
+		})
+	}
+}
+
+func TestGRPCAdapter_ControlFunctionsPassThroughMiddleware(t *testing.T) {
+	plugin := &controlPlugin{}
+	var seen *Call
+	adapter := NewGRPCAdapter(plugin, WithMiddleware(Authorize(func(_ context.Context, call *Call) error {
+		seen = call
+		return errors.New("operators only")
+	})))
+
+	_, err := adapter.InvokeControlFunction(context.Background(), &simsdkrpc.ControlFunctionRequest{
+		FunctionId:  "set-speed",
+		ComponentId: "loco-1",
+		Parameters:  map[string]string{"speed": "40"},
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.Empty(t, plugin.last.FunctionID, "the plugin was not called")
+	require.Equal(t, LifecycleCall, seen.Kind)
+	require.Equal(t, "InvokeControlFunction", seen.Method)
+	require.Equal(t, "loco-1", seen.ComponentID)
+	require.Equal(t, "set-speed", seen.Request.(ControlFunctionRequest).FunctionID)
+}
+
+func TestManifest_FindControlFunction(t *testing.T) {
+	m := (&controlPlugin{}).GetManifest()
+
+	cf, ok := m.FindControlFunction("set-speed")
+	require.True(t, ok)
+	require.Len(t, cf.Fields, 1)
+
+	_, ok = m.FindControlFunction("missing")
+	require.False(t, ok)
+}
@@ -1,7 +1,10 @@
 package simsdk
 
 import (
+	"time"
+
 	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func ToProtoManifest(m Manifest) *simsdkrpc.Manifest {
@@ -234,6 +237,8 @@ func ToProtoSimMessage(m *SimMessage) *simsdkrpc.SimMessage {
 		ComponentId: m.ComponentID,
 		Payload:     m.Payload,
 		Metadata:    m.Metadata,
+		SimTime:     toProtoTime(m.SimTime),
+		WallTime:    toProtoTime(m.WallTime),
 	}
 }
 
@@ -247,5 +252,85 @@ func FromProtoSimMessage(p *simsdkrpc.SimMessage) *SimMessage {
 		ComponentID: p.ComponentId,
 		Payload:     p.Payload,
 		Metadata:    p.Metadata,
+		SimTime:     fromProtoTime(p.SimTime),
+		WallTime:    fromProtoTime(p.WallTime),
+	}
+}
+
+// toProtoTime maps the zero time to an unset timestamp.
+func toProtoTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// fromProtoTime maps an unset timestamp to the zero time.
+func fromProtoTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func ToProtoComponentStatus(cs ComponentStatus) *simsdkrpc.ComponentStatus {
+	p := &simsdkrpc.ComponentStatus{
+		ComponentId:      cs.ComponentID,
+		ComponentType:    cs.ComponentType,
+		State:            toProtoComponentState(cs.State),
+		MessagesReceived: cs.MessagesReceived,
+		MessagesSent:     cs.MessagesSent,
+		LastError:        cs.LastError,
+		Fields:           cs.Fields,
+	}
+	if !cs.StartTime.IsZero() {
+		p.StartTime = timestamppb.New(cs.StartTime)
+	}
+	return p
+}
+
+func FromProtoComponentStatus(p *simsdkrpc.ComponentStatus) ComponentStatus {
+	cs := ComponentStatus{
+		ComponentID:      p.GetComponentId(),
+		ComponentType:    p.GetComponentType(),
+		State:            fromProtoComponentState(p.GetState()),
+		MessagesReceived: p.GetMessagesReceived(),
+		MessagesSent:     p.GetMessagesSent(),
+		LastError:        p.GetLastError(),
+		Fields:           p.GetFields(),
+	}
+	if p.GetStartTime() != nil {
+		cs.StartTime = p.GetStartTime().AsTime()
+	}
+	return cs
+}
+
+func toProtoComponentState(s ComponentState) simsdkrpc.ComponentState {
+	switch s {
+	case ComponentCreated:
+		return simsdkrpc.ComponentState_COMPONENT_STATE_CREATED
+	case ComponentRunning:
+		return simsdkrpc.ComponentState_COMPONENT_STATE_RUNNING
+	case ComponentStopped:
+		return simsdkrpc.ComponentState_COMPONENT_STATE_STOPPED
+	case ComponentPaused:
+		return simsdkrpc.ComponentState_COMPONENT_STATE_PAUSED
+	default:
+		return simsdkrpc.ComponentState_COMPONENT_STATE_UNSPECIFIED
+	}
+}
+
+func fromProtoComponentState(s simsdkrpc.ComponentState) ComponentState {
+	switch s {
+	case simsdkrpc.ComponentState_COMPONENT_STATE_CREATED:
+		return ComponentCreated
+	case simsdkrpc.ComponentState_COMPONENT_STATE_RUNNING:
+		return ComponentRunning
+	case simsdkrpc.ComponentState_COMPONENT_STATE_STOPPED:
+		return ComponentStopped
+	case simsdkrpc.ComponentState_COMPONENT_STATE_PAUSED:
+		return ComponentPaused
+	default:
+		return ComponentStateUnspecified
 	}
 }
@@ -0,0 +1,132 @@
+package simsdk
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metadata keys linking a response to the message that triggered it.
+const (
+	CorrelationIDKey   = "correlation-id"      // ID of the first message in the exchange
+	CausationIDKey     = "causation-id"        // ID of the message this one responds to
+	OriginComponentKey = "origin-component-id" // Component that received the triggering message
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var messageIDs = &idGenerator{now: time.Now}
+
+// NewMessageID returns a unique, ULID-like message ID: 26 Crockford base32
+// characters holding a millisecond timestamp followed by 80 random bits. IDs
+// sort in creation order, also within one millisecond in one process.
+func NewMessageID() string {
+	return messageIDs.next()
+}
+
+// idGenerator makes IDs monotonic by incrementing the random part of the
+// previous ID while the clock has not moved on.
+type idGenerator struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	lastMS  uint64
+	entropy [10]byte
+}
+
+func (g *idGenerator) next() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ms := uint64(g.now().UnixMilli())
+	if ms > g.lastMS || !increment(g.entropy[:]) {
+		g.lastMS = max(ms, g.lastMS)
+		_, _ = rand.Read(g.entropy[:])
+	}
+
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], g.lastMS<<16)
+	copy(id[6:], g.entropy[:])
+	return encodeULID(id)
+}
+
+// isMessageID reports whether id has the format of NewMessageID.
+func isMessageID(id string) bool {
+	if len(id) != 26 || id[0] > '7' {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if strings.IndexByte(crockford, id[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// increment adds one to b as a big-endian number. It reports false on overflow.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeULID encodes 128 bits as 26 base32 characters, 5 bits at a time from
+// the most significant end, after two leading zero bits.
+func encodeULID(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// CorrelationID returns the correlation ID of the exchange msg belongs to:
+// its correlation-id metadata, or its own MessageID when it starts one.
+func CorrelationID(msg *SimMessage) string {
+	if id := msg.Metadata[CorrelationIDKey]; id != "" {
+		return id
+	}
+	return msg.MessageID
+}
+
+// NewReply returns a response to in, addressed to in's component, with a new
+// message ID and the correlation metadata linking it to in.
+func NewReply(in *SimMessage, messageType string, payload []byte) *SimMessage {
+	return stampResponse(in, &SimMessage{
+		MessageType: messageType,
+		ComponentID: in.ComponentID,
+		Payload:     payload,
+	})
+}
+
+// stampResponse returns a copy of out with a message ID when it has none and
+// the correlation, causation and origin metadata of a response to in. Values
+// the plugin set itself are kept.
+func stampResponse(in, out *SimMessage) *SimMessage {
+	resp := *out
+	if resp.MessageID == "" {
+		resp.MessageID = NewMessageID()
+	}
+	resp.Metadata = make(map[string]string, len(out.Metadata)+3)
+	maps.Copy(resp.Metadata, out.Metadata)
+	setDefault(resp.Metadata, CorrelationIDKey, CorrelationID(in))
+	setDefault(resp.Metadata, CausationIDKey, in.MessageID)
+	setDefault(resp.Metadata, OriginComponentKey, in.ComponentID)
+	return &resp
+}
+
+func setDefault(md map[string]string, key, value string) {
+	if _, ok := md[key]; !ok && value != "" {
+		md[key] = value
+	}
+}
@@ -0,0 +1,100 @@
+package simsdk
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMessageID_SortsInCreationOrder(t *testing.T) {
+	// The ULID spec's example timestamp.
+	g := &idGenerator{now: func() time.Time { return time.UnixMilli(1469918176385) }}
+	first := g.next()
+	require.Len(t, first, 26)
+	require.Equal(t, "01ARYZ6S41", first[:10])
+
+	ids := []string{first}
+	for range 1000 {
+		ids = append(ids, g.next())
+	}
+	require.True(t, sort.StringsAreSorted(ids), "IDs within one millisecond stay ordered")
+	seen := map[string]bool{}
+	for _, id := range ids {
+		require.False(t, seen[id], id)
+		seen[id] = true
+	}
+
+	require.Less(t, NewMessageID(), NewMessageID())
+}
+
+// replyingHandler answers with a NewReply, a response carrying its own
+// correlation metadata, and a pushed message without an ID.
+type replyingHandler struct{ recordingHandler }
+
+func (h *replyingHandler) OnSimMessage(msg *SimMessage) ([]*SimMessage, error) {
+	if err := h.sender.Send(&SimMessage{MessageType: "Pushed"}); err != nil {
+		return nil, err
+	}
+	return []*SimMessage{
+		NewReply(msg, "Reply", []byte("ok")),
+		{MessageID: "own", MessageType: "Own", Metadata: map[string]string{CorrelationIDKey: "kept"}},
+	}, nil
+}
+
+func TestServeStream_StampsResponses(t *testing.T) {
+	in := simMessageEnvelope("m1", "a")
+	in.GetSimMessage().Metadata = map[string]string{CorrelationIDKey: "conv-1"}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{initEnvelope("a"), in}}
+	require.NoError(t, ServeStream(&replyingHandler{}, stream))
+
+	sent := map[string]*simsdkrpc.SimMessage{}
+	for _, env := range stream.sent {
+		if sm := env.GetSimMessage(); sm != nil {
+			sent[sm.MessageType] = sm
+		}
+	}
+	reply := sent["Reply"]
+	require.Len(t, reply.MessageId, 26)
+	require.Equal(t, "a", reply.ComponentId)
+	require.Equal(t, "conv-1", reply.Metadata[CorrelationIDKey])
+	require.Equal(t, "m1", reply.Metadata[CausationIDKey])
+	require.Equal(t, "a", reply.Metadata[OriginComponentKey])
+
+	own := sent["Own"]
+	require.Equal(t, "own", own.MessageId)
+	require.Equal(t, "kept", own.Metadata[CorrelationIDKey], "metadata set by the plugin is kept")
+	require.Equal(t, "m1", own.Metadata[CausationIDKey])
+
+	require.Len(t, sent["Pushed"].MessageId, 26, "pushed messages get an ID too")
+	require.NotContains(t, sent["Pushed"].Metadata, CausationIDKey, "pushed messages are not responses")
+}
+
+func TestGRPCAdapter_StampsBatchHandlerResponses(t *testing.T) {
+	resp, err := NewGRPCAdapter(&batchPlugin{}).HandleMessages(context.Background(), batchOf("m1", "m2"))
+	require.NoError(t, err)
+	out := resp.Results[0].OutboundMessages[0]
+	require.Equal(t, "out-m1", out.MessageId)
+	require.Equal(t, "m1", out.Metadata[CorrelationIDKey])
+	require.Equal(t, "m1", out.Metadata[CausationIDKey])
+	require.Equal(t, "a", out.Metadata[OriginComponentKey])
+}
+
+func TestServeStream_StampsStreamBatchResponses(t *testing.T) {
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{initEnvelope("a"), batchEnvelope(batchOf("m1", "m2"))}}
+	require.NoError(t, ServeStream(&batchingHandler{}, stream))
+	require.Equal(t, "m2", responseMetadata(t, stream, "re-m2")[CausationIDKey])
+}
+
+func responseMetadata(t *testing.T, stream *mockStream, id string) map[string]string {
+	for _, env := range stream.sent {
+		if sm := env.GetSimMessage(); sm != nil && sm.MessageId == id {
+			return sm.Metadata
+		}
+	}
+	t.Fatalf("no response %s", id)
+	return nil
+}
@@ -0,0 +1,202 @@
+package simsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain identifies simsdk errors in gRPC ErrorInfo details.
+const ErrorDomain = "simsdk.neurosim.io"
+
+// Sentinel errors plugins return (directly or wrapped) so the core can tell
+// failures apart. Test for them with errors.Is.
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrInvalidArgument    = errors.New("invalid argument")
+	ErrUnavailable        = errors.New("unavailable")
+	ErrDeadlineExceeded   = errors.New("deadline exceeded")
+	ErrFailedPrecondition = errors.New("failed precondition")
+	ErrUnimplemented      = errors.New("unimplemented")
+	ErrPermissionDenied   = errors.New("permission denied")
+)
+
+// Error is a structured SDK error. Kind is one of the sentinel errors above
+// and Err is the optional underlying cause; both are visible to errors.Is.
+type Error struct {
+	Kind        error
+	ComponentID string
+	Message     string
+	Err         error
+
+	status *status.Status // set when decoded from a gRPC status
+}
+
+// NewError returns an *Error of the given kind for a component.
+func NewError(kind error, componentID, format string, args ...any) *Error {
+	return &Error{
+		Kind:        kind,
+		ComponentID: componentID,
+		Message:     fmt.Sprintf(format, args...),
+	}
+}
+
+// WrapError returns an *Error of the given kind that wraps cause.
+func WrapError(kind error, componentID string, cause error) *Error {
+	return &Error{Kind: kind, ComponentID: componentID, Err: cause}
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Message != "" && e.Err != nil:
+		return e.Message + ": " + e.Err.Error()
+	case e.Message != "":
+		return e.Message
+	case e.Err != nil:
+		return e.Err.Error()
+	case e.Kind != nil:
+		return e.Kind.Error()
+	default:
+		return "unknown error"
+	}
+}
+
+// GRPCStatus returns the original status for errors decoded by FromGRPCError.
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// errorKinds maps each sentinel to its gRPC code and ErrorInfo reason.
+var errorKinds = []struct {
+	kind   error
+	code   codes.Code
+	reason string
+}{
+	{ErrNotFound, codes.NotFound, "NOT_FOUND"},
+	{ErrAlreadyExists, codes.AlreadyExists, "ALREADY_EXISTS"},
+	{ErrInvalidArgument, codes.InvalidArgument, "INVALID_ARGUMENT"},
+	{ErrUnavailable, codes.Unavailable, "UNAVAILABLE"},
+	{ErrDeadlineExceeded, codes.DeadlineExceeded, "DEADLINE_EXCEEDED"},
+	{ErrFailedPrecondition, codes.FailedPrecondition, "FAILED_PRECONDITION"},
+	{ErrUnimplemented, codes.Unimplemented, "UNIMPLEMENTED"},
+	{ErrPermissionDenied, codes.PermissionDenied, "PERMISSION_DENIED"},
+}
+
+// ToGRPCError converts an SDK error into a gRPC status error. Sentinel kinds map
+// to their matching codes with an ErrorInfo detail carrying the reason and
+// component ID. Recovered panics become codes.Internal carrying the panic
+// reference. Errors that are already gRPC statuses pass through unchanged;
+// anything else becomes codes.Unknown.
+func ToGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var perr *PanicError
+	if errors.As(err, &perr) {
+		return panicStatus(perr)
+	}
+
+	code, reason := codes.Unknown, ""
+	for _, k := range errorKinds {
+		if errors.Is(err, k.kind) {
+			code, reason = k.code, k.reason
+			break
+		}
+	}
+	if reason == "" {
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			code, reason = codes.DeadlineExceeded, "DEADLINE_EXCEEDED"
+		case errors.Is(err, context.Canceled):
+			return status.Error(codes.Canceled, err.Error())
+		default:
+			return status.Error(code, err.Error())
+		}
+	}
+
+	info := &errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain}
+	var sdkErr *Error
+	if errors.As(err, &sdkErr) && sdkErr.ComponentID != "" {
+		info.Metadata = map[string]string{"component_id": sdkErr.ComponentID}
+	}
+	st, detailErr := status.New(code, err.Error()).WithDetails(info)
+	if detailErr != nil {
+		return status.Error(code, err.Error())
+	}
+	return st.Err()
+}
+
+// panicStatus reports a recovered panic as codes.Internal. The stack stays in
+// the plugin's log; the reference in the ErrorInfo points to it.
+func panicStatus(perr *PanicError) error {
+	info := &errdetails.ErrorInfo{Reason: "HANDLER_PANIC", Domain: ErrorDomain, Metadata: map[string]string{"panic_ref": perr.Ref}}
+	if perr.ComponentID != "" {
+		info.Metadata["component_id"] = perr.ComponentID
+	}
+	st, detailErr := status.New(codes.Internal, perr.Error()).WithDetails(info)
+	if detailErr != nil {
+		return status.Error(codes.Internal, perr.Error())
+	}
+	return st.Err()
+}
+
+// FromGRPCError converts a gRPC status error returned by a plugin back into an
+// *Error whose Kind matches the status code, so callers can use errors.Is with
+// the SDK sentinels. Codes without a matching sentinel are returned unchanged.
+func FromGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	var kind error
+	for _, k := range errorKinds {
+		if st.Code() == k.code {
+			kind = k.kind
+			break
+		}
+	}
+	if kind == nil {
+		return err
+	}
+
+	out := &Error{Kind: kind, Message: st.Message(), status: st}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorDomain {
+			out.ComponentID = info.GetMetadata()["component_id"]
+		}
+	}
+	return out
+}
+
+// UnaryClientErrorInterceptor decodes plugin errors with FromGRPCError so that
+// callers of a PluginServiceClient can match them against the SDK sentinels.
+func UnaryClientErrorInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return FromGRPCError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
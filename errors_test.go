@@ -0,0 +1,118 @@
+package simsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestToGRPCError_Codes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"nil", nil, codes.OK},
+		{"not found sentinel", ErrNotFound, codes.NotFound},
+		{"wrapped already exists", fmt.Errorf("create x: %w", ErrAlreadyExists), codes.AlreadyExists},
+		{"typed invalid argument", NewError(ErrInvalidArgument, "c1", "bad speed"), codes.InvalidArgument},
+		{"unavailable", WrapError(ErrUnavailable, "c1", errors.New("amqp down")), codes.Unavailable},
+		{"deadline sentinel", ErrDeadlineExceeded, codes.DeadlineExceeded},
+		{"context deadline", fmt.Errorf("send: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{"context canceled", context.Canceled, codes.Canceled},
+		{"precondition", ErrFailedPrecondition, codes.FailedPrecondition},
+		{"plain error", errors.New("boom"), codes.Unknown},
+		{"existing status", status.Error(codes.ResourceExhausted, "full"), codes.ResourceExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToGRPCError(tt.err)
+			require.Equal(t, tt.code, status.Code(got))
+		})
+	}
+}
+
+func TestToGRPCError_Details(t *testing.T) {
+	err := ToGRPCError(NewError(ErrNotFound, "loco-1", "no such component"))
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, "no such component", st.Message())
+	require.Len(t, st.Details(), 1)
+
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	require.Equal(t, "NOT_FOUND", info.Reason)
+	require.Equal(t, ErrorDomain, info.Domain)
+	require.Equal(t, "loco-1", info.Metadata["component_id"])
+}
+
+func TestFromGRPCError_RoundTrip(t *testing.T) {
+	for _, kind := range []error{
+		ErrNotFound, ErrAlreadyExists, ErrInvalidArgument,
+		ErrUnavailable, ErrDeadlineExceeded, ErrFailedPrecondition, ErrUnimplemented,
+		ErrPermissionDenied,
+	} {
+		t.Run(kind.Error(), func(t *testing.T) {
+			got := FromGRPCError(ToGRPCError(NewError(kind, "c1", "failed")))
+			require.ErrorIs(t, got, kind)
+
+			var sdkErr *Error
+			require.True(t, errors.As(got, &sdkErr))
+			require.Equal(t, "c1", sdkErr.ComponentID)
+			require.Equal(t, "failed", sdkErr.Error())
+		})
+	}
+
+	plain := status.Error(codes.Internal, "oops")
+	require.Equal(t, plain, FromGRPCError(plain))
+	require.Nil(t, FromGRPCError(nil))
+}
+
+type errPlugin struct {
+	dummyPlugin
+	err error
+}
+
+func (p *errPlugin) HandleMessage(SimMessage) ([]SimMessage, error) { return nil, p.err }
+
+func TestUnaryClientErrorInterceptor(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	plugin := &errPlugin{err: NewError(ErrNotFound, "ghost", "no sender instance for %q", "ghost")}
+	simsdkrpc.RegisterPluginServiceServer(srv, NewGRPCAdapter(plugin))
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientErrorInterceptor()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err = simsdkrpc.NewPluginServiceClient(conn).HandleMessage(ctx, &simsdkrpc.SimMessage{ComponentId: "ghost"})
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	var sdkErr *Error
+	require.True(t, errors.As(err, &sdkErr))
+	require.Equal(t, "ghost", sdkErr.ComponentID)
+}
@@ -1,8 +1,13 @@
 package simsdk
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type FieldType string
@@ -74,3 +79,128 @@ func MustParseFieldType(s string) FieldType {
 	}
 	return ft
 }
+
+// ValidateParameters checks string parameters against the declared field specs.
+// Required fields must be present, unknown keys are rejected, and each value must
+// parse as its declared type. Repeated fields are JSON arrays and object fields are
+// JSON objects validated against ObjectFields. The returned error wraps
+// ErrInvalidArgument.
+func ValidateParameters(specs []FieldSpec, params map[string]string) error {
+	var problems []error
+	validateParameters("", specs, params, &problems)
+	if len(problems) == 0 {
+		return nil
+	}
+	return &Error{Kind: ErrInvalidArgument, Err: errors.Join(problems...)}
+}
+
+func validateParameters(prefix string, specs []FieldSpec, params map[string]string, problems *[]error) {
+	known := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		known[spec.Name] = true
+		v, ok := params[spec.Name]
+		if !ok {
+			if spec.Required {
+				*problems = append(*problems, fmt.Errorf("%s%s: required", prefix, spec.Name))
+			}
+			continue
+		}
+		validateValue(prefix+spec.Name, spec, v, problems)
+	}
+
+	var unknown []string
+	for name := range params {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		*problems = append(*problems, fmt.Errorf("%s%s: unknown parameter", prefix, name))
+	}
+}
+
+func validateValue(path string, spec FieldSpec, v string, problems *[]error) {
+	if spec.Repeated || spec.Type == FieldRepeated {
+		var items []json.RawMessage
+		if err := json.Unmarshal([]byte(v), &items); err != nil {
+			*problems = append(*problems, fmt.Errorf("%s: expected JSON array", path))
+			return
+		}
+		elem := spec
+		elem.Repeated = false
+		if spec.Type == FieldRepeated {
+			elem.Type = FieldString
+			if spec.Subtype != nil {
+				elem.Type = *spec.Subtype
+			}
+		}
+		for i, item := range items {
+			validateValue(fmt.Sprintf("%s[%d]", path, i), elem, rawString(item), problems)
+		}
+		return
+	}
+
+	var err error
+	switch spec.Type {
+	case FieldString:
+	case FieldInt:
+		_, err = strconv.ParseInt(v, 10, 64)
+	case FieldUint:
+		_, err = strconv.ParseUint(v, 10, 64)
+	case FieldFloat:
+		_, err = strconv.ParseFloat(v, 64)
+	case FieldBool:
+		_, err = strconv.ParseBool(v)
+	case FieldTimestamp:
+		_, err = time.Parse(time.RFC3339, v)
+	case FieldEnum:
+		if len(spec.EnumValues) > 0 && !contains(spec.EnumValues, v) {
+			err = fmt.Errorf("must be one of %s", strings.Join(spec.EnumValues, ", "))
+		}
+	case FieldObject:
+		var obj map[string]json.RawMessage
+		if jerr := json.Unmarshal([]byte(v), &obj); jerr != nil {
+			err = errors.New("expected JSON object")
+			break
+		}
+		if len(spec.ObjectFields) > 0 {
+			nested := make(map[string]string, len(obj))
+			for k, raw := range obj {
+				nested[k] = rawString(raw)
+			}
+			validateParameters(path+".", spec.ObjectFields, nested, problems)
+		}
+	default:
+		err = fmt.Errorf("unsupported field type %q", spec.Type)
+	}
+	if err != nil {
+		*problems = append(*problems, fmt.Errorf("%s: invalid %s value %q: %w", path, spec.Type, v, unwrapNumError(err)))
+	}
+}
+
+// rawString returns a JSON string's contents, or the raw JSON text otherwise.
+func rawString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+func unwrapNumError(err error) error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return numErr.Err
+	}
+	return err
+}
+
+func contains(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
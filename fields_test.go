@@ -0,0 +1,71 @@
+package simsdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateParameters(t *testing.T) {
+	specs := []FieldSpec{
+		{Name: "speed", Type: FieldFloat, Required: true},
+		{Name: "count", Type: FieldUint},
+		{Name: "offset", Type: FieldInt},
+		{Name: "enabled", Type: FieldBool},
+		{Name: "mode", Type: FieldEnum, EnumValues: []string{"AUTO", "MANUAL"}},
+		{Name: "at", Type: FieldTimestamp},
+		{Name: "tags", Type: FieldString, Repeated: true},
+		{Name: "ids", Type: FieldRepeated, Subtype: PtrFieldType(FieldInt)},
+		{Name: "location", Type: FieldObject, ObjectFields: []FieldSpec{
+			{Name: "lat", Type: FieldFloat, Required: true},
+			{Name: "name", Type: FieldString},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		params  map[string]string
+		wantErr string
+	}{
+		{
+			name:   "minimal valid",
+			params: map[string]string{"speed": "12.5"},
+		},
+		{
+			name: "all fields valid",
+			params: map[string]string{
+				"speed":    "1",
+				"count":    "3",
+				"offset":   "-2",
+				"enabled":  "true",
+				"mode":     "AUTO",
+				"at":       "2025-01-02T03:04:05Z",
+				"tags":     `["a","b"]`,
+				"ids":      `[1, 2, 3]`,
+				"location": `{"lat": 51.5, "name": "yard"}`,
+			},
+		},
+		{name: "missing required", params: map[string]string{}, wantErr: "speed: required"},
+		{name: "bad float", params: map[string]string{"speed": "fast"}, wantErr: `speed: invalid float value "fast"`},
+		{name: "negative uint", params: map[string]string{"speed": "1", "count": "-1"}, wantErr: "count: invalid uint"},
+		{name: "bad enum", params: map[string]string{"speed": "1", "mode": "OFF"}, wantErr: "must be one of AUTO, MANUAL"},
+		{name: "bad timestamp", params: map[string]string{"speed": "1", "at": "yesterday"}, wantErr: "at: invalid timestamp"},
+		{name: "repeated not array", params: map[string]string{"speed": "1", "tags": "a"}, wantErr: "tags: expected JSON array"},
+		{name: "repeated bad element", params: map[string]string{"speed": "1", "ids": `[1, "x"]`}, wantErr: `ids[1]: invalid int value "x"`},
+		{name: "object not json", params: map[string]string{"speed": "1", "location": "here"}, wantErr: "expected JSON object"},
+		{name: "object missing nested", params: map[string]string{"speed": "1", "location": `{}`}, wantErr: "location.lat: required"},
+		{name: "unknown parameter", params: map[string]string{"speed": "1", "sped": "2"}, wantErr: "sped: unknown parameter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateParameters(specs, tt.params)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidArgument)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package simsdk
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+)
+
+// DefaultCreditWindow is the number of inbound SimMessages the SDK lets the
+// core send ahead of the plugin's handlers when flow control is enabled.
+const DefaultCreditWindow = 128
+
+// noCredits naks inbound SimMessages the core sent beyond its credits.
+const noCredits = "flow control: message sent without credits"
+
+// FlowControlOptions configures credit-based flow control on a MessageStream.
+type FlowControlOptions struct {
+	// Window is the number of inbound SimMessages granted to the core at a
+	// time. Credits are replenished as handlers finish with messages.
+	Window int
+}
+
+// WithFlowControl enables credit-based flow control. The SDK advertises its
+// inbound window with a PluginCredit when the stream starts and enforces it:
+// SimMessages the core sends beyond its credits are nak'd without reaching a
+// handler. Outbound sends through a StreamSender are limited by the core's
+// grants from the first PluginCredit it sends; until then they are not
+// credit-limited, so older cores keep working. A send that fails, or a message
+// dropped by the overflow policy, returns its credit. Responses returned from
+// OnSimMessage never wait for credits: they are bounded by the inbound window
+// instead.
+func WithFlowControl(opts FlowControlOptions) StreamOption {
+	return func(o *streamOptions) {
+		if opts.Window <= 0 {
+			opts.Window = DefaultCreditWindow
+		}
+		o.flow = &opts
+	}
+}
+
+// FlowStats reports flow-control metrics for a stream.
+type FlowStats struct {
+	InboundWindow   int           `json:"inboundWindow"`
+	InboundReceived uint64        `json:"inboundReceived"`
+	ReceiveRate     float64       `json:"receiveRate"`     // Inbound SimMessages per second since the stream started
+	OutboundCredits int64         `json:"outboundCredits"` // -1 until the core sends its first grant
+	OutboundSent    uint64        `json:"outboundSent"`
+	SendRate        float64       `json:"sendRate"`    // Outbound SimMessages per second since the stream started
+	BlockedTime     time.Duration `json:"blockedTime"` // Total time senders waited for credits
+}
+
+// FlowControlledStreamSender is implemented by the SDK's stream senders and
+// reports the flow-control metrics of the underlying stream.
+type FlowControlledStreamSender interface {
+	StreamSender
+	FlowStats() FlowStats
+}
+
+// flowController tracks credits in both directions of a stream.
+type flowController struct {
+	window int
+	start  time.Time
+
+	mu       sync.Mutex
+	limited  bool          // the peer has sent at least one grant
+	credits  int64         // outbound credits left
+	granted  chan struct{} // closed and replaced on every grant
+	closed   bool
+	blocked  time.Duration
+	sent     uint64
+	received uint64
+	consumed int // inbound messages handled since the last grant
+	admitted int // inbound messages not yet granted back to the peer
+}
+
+func newFlowController(window int) *flowController {
+	return &flowController{
+		window:  window,
+		start:   time.Now(),
+		granted: make(chan struct{}),
+	}
+}
+
+// acquire takes one outbound credit, waiting for a grant if none are left. It
+// reports whether the credit counted against the peer's grants, for release.
+func (f *flowController) acquire(ctx context.Context) (limited bool, err error) {
+	var waitStart time.Time
+	for {
+		f.mu.Lock()
+		if f.closed {
+			f.mu.Unlock()
+			return false, ErrStreamClosed
+		}
+		if !f.limited || f.credits > 0 {
+			if f.limited {
+				f.credits--
+			}
+			f.sent++
+			if !waitStart.IsZero() {
+				f.blocked += time.Since(waitStart)
+			}
+			f.mu.Unlock()
+			return f.limited, nil
+		}
+		granted := f.granted
+		f.mu.Unlock()
+
+		if waitStart.IsZero() {
+			waitStart = time.Now()
+		}
+		select {
+		case <-granted:
+		case <-ctx.Done():
+			f.mu.Lock()
+			f.blocked += time.Since(waitStart)
+			f.mu.Unlock()
+			return false, ctx.Err()
+		}
+	}
+}
+
+// grant adds outbound credits received from the peer.
+func (f *flowController) grant(n uint32) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.limited = true
+	f.credits += int64(n)
+	close(f.granted)
+	f.granted = make(chan struct{})
+}
+
+// release returns n credits taken by acquire for messages that were never sent.
+// Credits taken before the peer's first grant were not counted and are not
+// returned.
+func (f *flowController) release(n int, limited bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent -= uint64(n)
+	if !limited || !f.limited {
+		return
+	}
+	f.credits += int64(n)
+	close(f.granted)
+	f.granted = make(chan struct{})
+}
+
+// admit takes inbound credits for up to n messages from the peer and returns
+// how many fit in the window; the rest were sent without credits.
+func (f *flowController) admit(n int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n = max(0, min(n, f.window-f.admitted))
+	f.admitted += n
+	return n
+}
+
+// consume records a handled inbound message and returns the number of credits
+// to grant back to the peer, if any. Credits are returned in batches of half
+// the window to limit chatter.
+func (f *flowController) consume() uint32 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.received++
+	f.consumed++
+	if f.consumed < (f.window+1)/2 {
+		return 0
+	}
+	n := f.consumed
+	f.consumed = 0
+	f.admitted -= n
+	return uint32(n)
+}
+
+// close releases senders waiting for credits.
+func (f *flowController) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	close(f.granted)
+	f.granted = make(chan struct{})
+}
+
+func (f *flowController) stats() FlowStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st := FlowStats{
+		InboundWindow:   f.window,
+		InboundReceived: f.received,
+		OutboundSent:    f.sent,
+		OutboundCredits: -1,
+		BlockedTime:     f.blocked,
+	}
+	if f.limited {
+		st.OutboundCredits = f.credits
+	}
+	if elapsed := time.Since(f.start).Seconds(); elapsed > 0 {
+		st.ReceiveRate = float64(f.received) / elapsed
+		st.SendRate = float64(f.sent) / elapsed
+	}
+	return st
+}
+
+func creditEnvelope(n uint32) *simsdkrpc.PluginMessageEnvelope {
+	return &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_Credit{
+			Credit: &simsdkrpc.PluginCredit{Credits: n},
+		},
+	}
+}
@@ -0,0 +1,175 @@
+package simsdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlowController_UnlimitedUntilFirstGrant(t *testing.T) {
+	f := newFlowController(4)
+	for i := 0; i < 10; i++ {
+		limited, err := f.acquire(context.Background())
+		require.NoError(t, err)
+		require.False(t, limited)
+	}
+	st := f.stats()
+	require.Equal(t, uint64(10), st.OutboundSent)
+	require.Equal(t, int64(-1), st.OutboundCredits)
+}
+
+func TestFlowController_BlocksWithoutCredits(t *testing.T) {
+	f := newFlowController(4)
+	f.grant(1)
+	limited, err := f.acquire(context.Background())
+	require.NoError(t, err)
+	require.True(t, limited)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = f.acquire(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	done := make(chan error, 1)
+	go func() { _, err := f.acquire(context.Background()); done <- err }()
+	time.Sleep(10 * time.Millisecond)
+	f.grant(2)
+	require.NoError(t, <-done)
+
+	st := f.stats()
+	require.Equal(t, int64(1), st.OutboundCredits)
+	require.Equal(t, uint64(2), st.OutboundSent)
+	require.GreaterOrEqual(t, st.BlockedTime, 20*time.Millisecond)
+	require.Greater(t, st.SendRate, 0.0)
+}
+
+func TestFlowController_CloseReleasesWaiters(t *testing.T) {
+	f := newFlowController(4)
+	f.grant(0)
+
+	done := make(chan error, 1)
+	go func() { _, err := f.acquire(context.Background()); done <- err }()
+	f.close()
+	require.ErrorIs(t, <-done, ErrStreamClosed)
+}
+
+func TestFlowController_ConsumeReplenishesInBatches(t *testing.T) {
+	f := newFlowController(4)
+	require.Zero(t, f.consume())
+	require.Equal(t, uint32(2), f.consume())
+	require.Zero(t, f.consume())
+	require.Equal(t, uint32(2), f.consume())
+	require.Equal(t, uint64(4), f.stats().InboundReceived)
+}
+
+func TestServeStream_FlowControlAdvertisesAndReplenishesCredits(t *testing.T) {
+	creditEnv := &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_Credit{Credit: &simsdkrpc.PluginCredit{Credits: 5}},
+	}
+	handler := &recordingHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		creditEnv,
+		simMessageEnvelope("m1", "a"),
+		simMessageEnvelope("m2", "a"),
+	}}
+
+	require.NoError(t, ServeStream(handler, stream, WithFlowControl(FlowControlOptions{Window: 2})))
+
+	var credits []uint32
+	for _, env := range stream.sent {
+		if c := env.GetCredit(); c != nil {
+			credits = append(credits, c.Credits)
+		}
+	}
+	// initial window, then one grant per handled message (batch of window/2)
+	require.Equal(t, []uint32{2, 1, 1}, credits)
+
+	st := handler.sender.(FlowControlledStreamSender).FlowStats()
+	require.Equal(t, uint64(2), st.InboundReceived)
+	require.Equal(t, uint64(2), st.OutboundSent)
+	require.Equal(t, int64(3), st.OutboundCredits)
+}
+
+func TestFlowController_AdmitEnforcesInboundWindow(t *testing.T) {
+	f := newFlowController(2)
+	require.Equal(t, 1, f.admit(1))
+	require.Equal(t, 1, f.admit(3), "only the rest of the window is admitted")
+	require.Zero(t, f.admit(1))
+
+	require.Equal(t, uint32(1), f.consume())
+	require.Equal(t, 1, f.admit(2), "granted credits can be used again")
+}
+
+func TestGRPCStreamSender_FailedSendsReturnCredits(t *testing.T) {
+	out := &gatedSender{gate: make(chan struct{})}
+	w := newStreamWriter(out, 1, OverflowError)
+	defer close(out.gate)
+	f := newFlowController(4)
+	f.grant(3)
+	sender := &grpcStreamSender{writer: w, flow: f}
+
+	require.NoError(t, sender.Send(&SimMessage{MessageID: "in-flight"}))
+	require.Eventually(t, func() bool { return sender.QueueStats().Depth == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, sender.Send(&SimMessage{MessageID: "queued"}))
+	require.ErrorIs(t, sender.Send(&SimMessage{MessageID: "full"}), ErrSendQueueFull)
+	require.Equal(t, int64(1), f.stats().OutboundCredits)
+
+	// the batch takes the last credit, then times out waiting for another
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := sender.SendBatch(ctx, []*SimMessage{{MessageID: "b1"}, {MessageID: "b2"}})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	st := f.stats()
+	require.Equal(t, int64(1), st.OutboundCredits)
+	require.Equal(t, uint64(2), st.OutboundSent)
+}
+
+func TestStreamSession_DroppedMessagesReturnCredits(t *testing.T) {
+	out := &gatedSender{gate: make(chan struct{}, 16)}
+	w := newStreamWriter(out, 1, OverflowDropOldest)
+	f := newFlowController(4)
+	f.grant(3)
+	w.onDrop = (&streamSession{flow: f}).releaseDropped
+	sender := &grpcStreamSender{writer: w, flow: f}
+
+	require.NoError(t, sender.Send(&SimMessage{MessageID: "in-flight"}))
+	require.Eventually(t, func() bool { return sender.QueueStats().Depth == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, sender.Send(&SimMessage{MessageID: "dropped"}))
+	require.NoError(t, sender.Send(&SimMessage{MessageID: "kept"}))
+
+	st := f.stats()
+	require.Equal(t, int64(1), st.OutboundCredits)
+	require.Equal(t, uint64(2), st.OutboundSent)
+
+	for range 16 {
+		out.gate <- struct{}{}
+	}
+	require.NoError(t, w.close(context.Background()))
+}
+
+func TestServeStream_FlowControlNaksMessagesBeyondTheWindow(t *testing.T) {
+	handler := &recordingHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		batchEnvelope(batchOf("m1", "m2", "m3")),
+	}}
+	require.NoError(t, ServeStream(handler, stream, WithFlowControl(FlowControlOptions{Window: 2})))
+
+	var result *simsdkrpc.MessageBatchResponse
+	for _, env := range stream.sent {
+		if r := env.GetBatchResult(); r != nil {
+			result = r
+		}
+	}
+	require.NotNil(t, result)
+	require.Len(t, result.Results, 3)
+	require.NotNil(t, result.Results[0].GetAck())
+	require.NotNil(t, result.Results[1].GetAck())
+	require.Equal(t, noCredits, result.Results[2].GetNak().GetErrorMessage())
+	require.Equal(t, uint64(2), handler.sender.(FlowControlledStreamSender).FlowStats().InboundReceived)
+}
@@ -0,0 +1,164 @@
+package simsdk
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// PluginHealthService is the health service name reported for the plugin as a whole.
+var PluginHealthService = simsdkrpc.PluginService_ServiceDesc.ServiceName
+
+// ComponentHealthService returns the health service name for a live component.
+func ComponentHealthService(componentID string) string {
+	return PluginHealthService + "/component/" + componentID
+}
+
+// ReadinessChecker is implemented by plugins (or transports) that can tell
+// whether they are ready to serve, e.g. by checking a broker connection.
+type ReadinessChecker interface {
+	CheckReadiness(ctx context.Context) error
+}
+
+// ComponentReadinessChecker is implemented by plugins that can check the
+// readiness of an individual component instance.
+type ComponentReadinessChecker interface {
+	CheckComponentReadiness(ctx context.Context, componentID string) error
+}
+
+// ReadinessCheck is a named readiness probe contributed by plugin code.
+type ReadinessCheck func(ctx context.Context) error
+
+// HealthMonitor drives a standard grpc.health.v1 server for a plugin. The plugin
+// reports NOT_SERVING until SetReady(true) is called and again after Shutdown.
+// Each Update re-evaluates the plugin's readiness checks and publishes one
+// health service per live component when the plugin is a ComponentStatusProvider.
+type HealthMonitor struct {
+	plugin PluginWithHandlers
+	server *health.Server
+
+	mu         sync.Mutex
+	ready      bool
+	checks     map[string]ReadinessCheck
+	components map[string]bool
+}
+
+// NewHealthMonitor returns a monitor for the plugin with every service NOT_SERVING.
+func NewHealthMonitor(p PluginWithHandlers) *HealthMonitor {
+	h := &HealthMonitor{
+		plugin:     p,
+		server:     health.NewServer(),
+		checks:     make(map[string]ReadinessCheck),
+		components: make(map[string]bool),
+	}
+	h.server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	h.server.SetServingStatus(PluginHealthService, healthpb.HealthCheckResponse_NOT_SERVING)
+	return h
+}
+
+// Server returns the health server to register with healthpb.RegisterHealthServer.
+func (h *HealthMonitor) Server() *health.Server {
+	return h.server
+}
+
+// AddCheck registers a readiness check. All checks must pass for the plugin to be SERVING.
+func (h *HealthMonitor) AddCheck(name string, check ReadinessCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// SetReady marks startup as finished (or not). The plugin never reports SERVING
+// while it is not ready.
+func (h *HealthMonitor) SetReady(ready bool) {
+	h.mu.Lock()
+	h.ready = ready
+	h.mu.Unlock()
+	h.Update(context.Background())
+}
+
+// Shutdown reports NOT_SERVING for every service and ignores later updates.
+// Call it before draining the gRPC server.
+func (h *HealthMonitor) Shutdown() {
+	h.server.Shutdown()
+}
+
+// Run calls Update every interval until ctx is cancelled.
+func (h *HealthMonitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.Update(ctx)
+		}
+	}
+}
+
+// Update evaluates the readiness checks and component states once.
+func (h *HealthMonitor) Update(ctx context.Context) {
+	h.mu.Lock()
+	ready := h.ready
+	checks := make([]ReadinessCheck, 0, len(h.checks)+1)
+	for _, c := range h.checks {
+		checks = append(checks, c)
+	}
+	h.mu.Unlock()
+
+	if rc, ok := h.plugin.(ReadinessChecker); ok {
+		checks = append(checks, rc.CheckReadiness)
+	}
+	if ready {
+		for _, check := range checks {
+			if err := check(ctx); err != nil {
+				ready = false
+				break
+			}
+		}
+	}
+	status := servingStatus(ready)
+	h.server.SetServingStatus("", status)
+	h.server.SetServingStatus(PluginHealthService, status)
+
+	h.updateComponents(ctx, ready)
+}
+
+func (h *HealthMonitor) updateComponents(ctx context.Context, pluginReady bool) {
+	provider, ok := h.plugin.(ComponentStatusProvider)
+	if !ok {
+		return
+	}
+	checker, _ := h.plugin.(ComponentReadinessChecker)
+
+	seen := make(map[string]bool)
+	for _, cs := range provider.ListComponents() {
+		seen[cs.ComponentID] = true
+		ok := pluginReady && cs.State == ComponentRunning
+		if ok && checker != nil {
+			ok = checker.CheckComponentReadiness(ctx, cs.ComponentID) == nil
+		}
+		h.server.SetServingStatus(ComponentHealthService(cs.ComponentID), servingStatus(ok))
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for id := range h.components {
+		if !seen[id] {
+			h.server.SetServingStatus(ComponentHealthService(id), healthpb.HealthCheckResponse_SERVICE_UNKNOWN)
+		}
+	}
+	h.components = seen
+}
+
+func servingStatus(ok bool) healthpb.HealthCheckResponse_ServingStatus {
+	if ok {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,86 @@
+package simsdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type readinessPlugin struct {
+	statusPlugin
+	pluginErr    error
+	componentErr map[string]error
+}
+
+func (p *readinessPlugin) CheckReadiness(context.Context) error { return p.pluginErr }
+
+func (p *readinessPlugin) CheckComponentReadiness(_ context.Context, id string) error {
+	return p.componentErr[id]
+}
+
+func healthStatus(t *testing.T, h *HealthMonitor, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := h.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.Status
+}
+
+func TestHealthMonitor_StartupReadinessAndShutdown(t *testing.T) {
+	plugin := &readinessPlugin{}
+	h := NewHealthMonitor(plugin)
+
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, h, ""))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, h, PluginHealthService))
+
+	h.SetReady(true)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, healthStatus(t, h, ""))
+
+	plugin.pluginErr = errors.New("broker down")
+	h.Update(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, h, PluginHealthService))
+
+	plugin.pluginErr = nil
+	var failing bool
+	h.AddCheck("custom", func(context.Context) error {
+		if failing {
+			return errors.New("custom failed")
+		}
+		return nil
+	})
+	h.Update(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, healthStatus(t, h, ""))
+	failing = true
+	h.Update(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, h, ""))
+
+	failing = false
+	h.Update(context.Background())
+	h.Shutdown()
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, h, ""))
+	h.Update(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, h, ""), "updates are ignored after shutdown")
+}
+
+func TestHealthMonitor_ComponentServices(t *testing.T) {
+	plugin := &readinessPlugin{
+		statusPlugin: statusPlugin{components: []ComponentStatus{
+			{ComponentID: "a", State: ComponentRunning},
+			{ComponentID: "b", State: ComponentPaused},
+			{ComponentID: "c", State: ComponentRunning},
+		}},
+		componentErr: map[string]error{"c": errors.New("disconnected")},
+	}
+	h := NewHealthMonitor(plugin)
+	h.SetReady(true)
+
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, healthStatus(t, h, ComponentHealthService("a")))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, h, ComponentHealthService("b")))
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, healthStatus(t, h, ComponentHealthService("c")))
+
+	plugin.components = plugin.components[:1]
+	h.Update(context.Background())
+	require.Equal(t, healthpb.HealthCheckResponse_SERVICE_UNKNOWN, healthStatus(t, h, ComponentHealthService("b")))
+}
@@ -0,0 +1,176 @@
+package simsdk
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultHeartbeatInterval is how often heartbeats are sent when
+// HeartbeatOptions.Interval is not set.
+const DefaultHeartbeatInterval = 5 * time.Second
+
+// ErrHeartbeatTimeout is returned by ServeStream when the core stops sending
+// anything for longer than the heartbeat timeout.
+var ErrHeartbeatTimeout = fmt.Errorf("%w: heartbeat timeout", ErrUnavailable)
+
+// HeartbeatOptions configures heartbeats on a MessageStream.
+type HeartbeatOptions struct {
+	Interval time.Duration // How often to send heartbeats (default 5s)
+	Timeout  time.Duration // Silence after which the core is considered dead (default 3*Interval)
+}
+
+// WithHeartbeat sends a PluginHeartbeat carrying the stream's load every
+// interval and ends the stream when nothing arrives from the core for longer
+// than the timeout. Live components are shut down with "heartbeat timeout"
+// and ServeStream returns ErrHeartbeatTimeout.
+func WithHeartbeat(opts HeartbeatOptions) StreamOption {
+	return func(o *streamOptions) {
+		if opts.Interval <= 0 {
+			opts.Interval = DefaultHeartbeatInterval
+		}
+		if opts.Timeout <= 0 {
+			opts.Timeout = 3 * opts.Interval
+		}
+		o.heartbeat = &opts
+	}
+}
+
+// StreamLoad is the load a plugin reports in its heartbeats.
+type StreamLoad struct {
+	QueueDepth     int           `json:"queueDepth"`
+	InFlight       int           `json:"inFlight"`
+	Pending        int           `json:"pending"`
+	Components     int           `json:"components"`
+	OldestInFlight time.Duration `json:"oldestInFlight"`
+}
+
+func (l StreamLoad) toProto() *simsdkrpc.PluginLoad {
+	return &simsdkrpc.PluginLoad{
+		QueueDepth:       uint32(l.QueueDepth),
+		InFlight:         uint32(l.InFlight),
+		Pending:          uint32(l.Pending),
+		Components:       uint32(l.Components),
+		OldestInFlightMs: uint64(l.OldestInFlight / time.Millisecond),
+	}
+}
+
+// inflightTracker records when each inbound message started being handled.
+type inflightTracker struct {
+	mu      sync.Mutex
+	next    uint64
+	started map[uint64]time.Time
+}
+
+func newInflightTracker() *inflightTracker {
+	return &inflightTracker{started: make(map[uint64]time.Time)}
+}
+
+func (t *inflightTracker) begin() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.next++
+	t.started[t.next] = time.Now()
+	return t.next
+}
+
+func (t *inflightTracker) end(id uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.started, id)
+}
+
+// snapshot returns the number of messages in flight and the age of the oldest.
+func (t *inflightTracker) snapshot() (int, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var oldest time.Duration
+	for _, start := range t.started {
+		if age := time.Since(start); age > oldest {
+			oldest = age
+		}
+	}
+	return len(t.started), oldest
+}
+
+// heartbeatMonitor sends heartbeats and watches for a silent peer.
+type heartbeatMonitor struct {
+	opts     HeartbeatOptions
+	lastSeen chan struct{} // signalled on every inbound envelope
+	dead     chan struct{} // closed once the peer times out
+	busy     atomic.Bool   // set while an envelope waits for the serve loop
+}
+
+func newHeartbeatMonitor(opts HeartbeatOptions) *heartbeatMonitor {
+	return &heartbeatMonitor{
+		opts:     opts,
+		lastSeen: make(chan struct{}, 1),
+		dead:     make(chan struct{}),
+	}
+}
+
+// seen records activity from the peer.
+func (h *heartbeatMonitor) seen() {
+	select {
+	case h.lastSeen <- struct{}{}:
+	default:
+	}
+}
+
+// received records an envelope from the peer that now waits for the serve loop.
+func (h *heartbeatMonitor) received() {
+	h.seen()
+	h.busy.Store(true)
+}
+
+// delivered records that the serve loop took the envelope, so the stream is
+// being read again.
+func (h *heartbeatMonitor) delivered() {
+	h.busy.Store(false)
+	h.seen()
+}
+
+// run sends a heartbeat built by beat every interval until stop is closed, and
+// closes dead if the peer is silent for longer than the timeout. send must not
+// block, or a peer that stops reading would also stop the deadline.
+func (h *heartbeatMonitor) run(stop <-chan struct{}, beat func() *simsdkrpc.PluginMessageEnvelope, send func(*simsdkrpc.PluginMessageEnvelope)) {
+	ticker := time.NewTicker(h.opts.Interval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(h.opts.Timeout)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-h.lastSeen:
+			deadline.Reset(h.opts.Timeout)
+		case <-ticker.C:
+			send(beat())
+		case <-deadline.C:
+			// While the serve loop is busy with an envelope the stream is
+			// not being read, so the peer's silence proves nothing.
+			if h.busy.Load() {
+				deadline.Reset(h.opts.Timeout)
+				continue
+			}
+			close(h.dead)
+			return
+		}
+	}
+}
+
+func heartbeatEnvelope(load StreamLoad) *simsdkrpc.PluginMessageEnvelope {
+	return &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_Heartbeat{
+			Heartbeat: &simsdkrpc.PluginHeartbeat{
+				SentAt: timestamppb.Now(),
+				Load:   load.toProto(),
+			},
+		},
+	}
+}
@@ -0,0 +1,189 @@
+package simsdk
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// chanStream is a MessageStream fed from a channel; Recv blocks until an
+// envelope arrives or the channel is closed.
+type chanStream struct {
+	grpc.ServerStream
+	in chan *simsdkrpc.PluginMessageEnvelope
+
+	mu   sync.Mutex
+	sent []*simsdkrpc.PluginMessageEnvelope
+}
+
+func newChanStream() *chanStream {
+	return &chanStream{in: make(chan *simsdkrpc.PluginMessageEnvelope, 16)}
+}
+
+func (c *chanStream) Context() context.Context { return context.Background() }
+
+func (c *chanStream) Recv() (*simsdkrpc.PluginMessageEnvelope, error) {
+	env, ok := <-c.in
+	if !ok {
+		return nil, io.EOF
+	}
+	return env, nil
+}
+
+func (c *chanStream) Send(env *simsdkrpc.PluginMessageEnvelope) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, env)
+	return nil
+}
+
+func (c *chanStream) heartbeats() []*simsdkrpc.PluginHeartbeat {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []*simsdkrpc.PluginHeartbeat
+	for _, env := range c.sent {
+		if hb := env.GetHeartbeat(); hb != nil {
+			out = append(out, hb)
+		}
+	}
+	return out
+}
+
+func TestServeStream_HeartbeatTimeoutShutsDownComponents(t *testing.T) {
+	handler := &recordingHandler{}
+	stream := newChanStream()
+	stream.in <- initEnvelope("a")
+
+	err := ServeStreamMux(func() StreamHandler { return handler }, stream,
+		WithHeartbeat(HeartbeatOptions{Interval: 5 * time.Millisecond, Timeout: 30 * time.Millisecond}))
+
+	require.ErrorIs(t, err, ErrHeartbeatTimeout)
+	require.ErrorIs(t, err, ErrUnavailable)
+	require.Equal(t, []string{"heartbeat timeout"}, handler.shutdown)
+
+	beats := stream.heartbeats()
+	require.NotEmpty(t, beats)
+	require.NotNil(t, beats[0].SentAt)
+	require.Equal(t, uint32(1), beats[len(beats)-1].Load.Components)
+}
+
+func TestServeStream_PeerHeartbeatsKeepStreamAlive(t *testing.T) {
+	stream := newChanStream()
+	done := make(chan error, 1)
+	go func() {
+		done <- ServeStream(&recordingHandler{}, stream,
+			WithHeartbeat(HeartbeatOptions{Interval: 5 * time.Millisecond, Timeout: 30 * time.Millisecond}))
+	}()
+
+	for i := 0; i < 10; i++ {
+		stream.in <- &simsdkrpc.PluginMessageEnvelope{
+			Content: &simsdkrpc.PluginMessageEnvelope_Heartbeat{Heartbeat: &simsdkrpc.PluginHeartbeat{}},
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(stream.in)
+	require.NoError(t, <-done)
+}
+
+func TestInflightTracker_ReportsOldest(t *testing.T) {
+	tr := newInflightTracker()
+	first := tr.begin()
+	time.Sleep(5 * time.Millisecond)
+	second := tr.begin()
+
+	n, oldest := tr.snapshot()
+	require.Equal(t, 2, n)
+	require.GreaterOrEqual(t, oldest, 5*time.Millisecond)
+
+	tr.end(first)
+	tr.end(second)
+	n, oldest = tr.snapshot()
+	require.Zero(t, n)
+	require.Zero(t, oldest)
+}
+
+// stuckHandler runs each message until release is closed.
+type stuckHandler struct {
+	recordingHandler
+	release chan struct{}
+}
+
+func (h *stuckHandler) OnSimMessage(*SimMessage) ([]*SimMessage, error) {
+	<-h.release
+	return nil, nil
+}
+
+func TestServeStream_SlowHandlerWithLivePeerIsNotTimedOut(t *testing.T) {
+	handler := &stuckHandler{release: make(chan struct{})}
+	stream := newChanStream()
+	done := make(chan error, 1)
+	go func() {
+		done <- ServeStream(handler, stream,
+			WithHeartbeat(HeartbeatOptions{Interval: 5 * time.Millisecond, Timeout: 30 * time.Millisecond}))
+	}()
+	stream.in <- initEnvelope("a")
+	stream.in <- simMessageEnvelope("m1", "a")
+
+	// The handler runs for 150ms while the core keeps heartbeating.
+	time.AfterFunc(150*time.Millisecond, func() { close(handler.release) })
+	for range 40 {
+		select {
+		case err := <-done:
+			t.Fatalf("stream ended while the core was heartbeating: %v", err)
+		case stream.in <- &simsdkrpc.PluginMessageEnvelope{
+			Content: &simsdkrpc.PluginMessageEnvelope_Heartbeat{Heartbeat: &simsdkrpc.PluginHeartbeat{}},
+		}:
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(stream.in)
+	require.NoError(t, <-done)
+}
+
+func TestServeStream_HeartbeatTimeoutDoesNotWaitForStuckWorkers(t *testing.T) {
+	handler := &stuckHandler{release: make(chan struct{})}
+	defer close(handler.release)
+	stream := newChanStream()
+	stream.in <- initEnvelope("a")
+	stream.in <- simMessageEnvelope("m1", "a")
+
+	err := ServeStream(handler, stream, WithWorkers(2, 0),
+		WithHeartbeat(HeartbeatOptions{Interval: 5 * time.Millisecond, Timeout: 30 * time.Millisecond}))
+	require.ErrorIs(t, err, ErrHeartbeatTimeout)
+	require.Equal(t, []string{"heartbeat timeout"}, handler.shutdown)
+}
+
+// hungStream is a core that stops reading: every Send blocks until the test ends.
+type hungStream struct {
+	*chanStream
+	hung chan struct{}
+}
+
+func (h *hungStream) Send(*simsdkrpc.PluginMessageEnvelope) error {
+	<-h.hung
+	return io.EOF
+}
+
+func TestServeStream_HeartbeatTimeoutWithFullSendQueue(t *testing.T) {
+	stream := &hungStream{chanStream: newChanStream(), hung: make(chan struct{})}
+	defer close(stream.hung)
+	stream.in <- initEnvelope("a")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ServeStream(&recordingHandler{}, stream, WithSendQueueSize(1),
+			WithHeartbeat(HeartbeatOptions{Interval: time.Millisecond, Timeout: 30 * time.Millisecond}))
+	}()
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, ErrHeartbeatTimeout)
+	case <-time.After(2 * time.Second):
+		t.Fatal("a core that stops reading was never timed out")
+	}
+}
@@ -0,0 +1,152 @@
+package simsdk
+
+import "sync"
+
+// ComponentLifecycle is implemented by plugins whose components can be paused,
+// resumed and reset without being destroyed. The SDK validates each call
+// against the component state machine before invoking the plugin.
+type ComponentLifecycle interface {
+	PauseComponent(componentID string) error
+	ResumeComponent(componentID string) error
+	ResetComponent(componentID string) error
+}
+
+// componentTransitions lists the allowed moves of the component state machine:
+// created → running → paused → stopped, with paused → running on resume.
+var componentTransitions = map[ComponentState][]ComponentState{
+	ComponentCreated: {ComponentRunning, ComponentStopped},
+	ComponentRunning: {ComponentPaused, ComponentStopped},
+	ComponentPaused:  {ComponentRunning, ComponentStopped},
+}
+
+// CanTransitionTo reports whether the state machine allows moving from s to next.
+func (s ComponentState) CanTransitionTo(next ComponentState) bool {
+	for _, allowed := range componentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns an error wrapping ErrFailedPrecondition if the
+// component cannot move from one state to the other.
+func ValidateTransition(componentID string, from, to ComponentState) error {
+	if !from.CanTransitionTo(to) {
+		return NewError(ErrFailedPrecondition, componentID, "component %q cannot go from %s to %s", componentID, from, to)
+	}
+	return nil
+}
+
+// LifecycleTracker records the state of each component and enforces the
+// component state machine. It is safe for concurrent use.
+type LifecycleTracker struct {
+	mu     sync.Mutex
+	states map[string]ComponentState
+	// ops serializes the transitions of each component, so the plugin call
+	// of a transition runs without mu and only waits for the same component.
+	ops map[string]*sync.Mutex
+}
+
+// NewLifecycleTracker returns an empty tracker.
+func NewLifecycleTracker() *LifecycleTracker {
+	return &LifecycleTracker{states: make(map[string]ComponentState), ops: make(map[string]*sync.Mutex)}
+}
+
+// Track sets the state of a component unconditionally.
+func (t *LifecycleTracker) Track(componentID string, state ComponentState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.states[componentID] = state
+}
+
+// trackNew sets the state of a component that is not tracked yet, and keeps
+// the state of one that is.
+func (t *LifecycleTracker) trackNew(componentID string, state ComponentState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.states[componentID]; !ok {
+		t.states[componentID] = state
+	}
+}
+
+// Forget removes a component from the tracker.
+func (t *LifecycleTracker) Forget(componentID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.states, componentID)
+	delete(t.ops, componentID)
+}
+
+// State returns the tracked state of a component.
+func (t *LifecycleTracker) State(componentID string) (ComponentState, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.states[componentID]
+	return s, ok
+}
+
+// Transition validates the move to the given state, runs fn and records the
+// new state only if fn succeeds. fn may be nil. Transitions of the same
+// component run one at a time; those of other components are not blocked.
+func (t *LifecycleTracker) Transition(componentID string, to ComponentState, fn func() error) error {
+	op := t.op(componentID)
+	op.Lock()
+	defer op.Unlock()
+
+	from, ok := t.State(componentID)
+	if !ok {
+		return NewError(ErrNotFound, componentID, "unknown component %q", componentID)
+	}
+	if err := ValidateTransition(componentID, from, to); err != nil {
+		return err
+	}
+	if fn != nil {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	t.record(componentID, to)
+	return nil
+}
+
+// Reset runs fn for a tracked component and marks it running again. A reset is
+// allowed from any state, including stopped.
+func (t *LifecycleTracker) Reset(componentID string, fn func() error) error {
+	op := t.op(componentID)
+	op.Lock()
+	defer op.Unlock()
+
+	if _, ok := t.State(componentID); !ok {
+		return NewError(ErrNotFound, componentID, "unknown component %q", componentID)
+	}
+	if fn != nil {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	t.record(componentID, ComponentRunning)
+	return nil
+}
+
+// op returns the lock that serializes the transitions of a component.
+func (t *LifecycleTracker) op(componentID string) *sync.Mutex {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	op, ok := t.ops[componentID]
+	if !ok {
+		op = &sync.Mutex{}
+		t.ops[componentID] = op
+	}
+	return op
+}
+
+// record sets the state reached by a transition, unless the component was
+// forgotten while the plugin call ran.
+func (t *LifecycleTracker) record(componentID string, state ComponentState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.states[componentID]; ok {
+		t.states[componentID] = state
+	}
+}
@@ -0,0 +1,155 @@
+package simsdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestComponentState_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to ComponentState
+		want     bool
+	}{
+		{ComponentCreated, ComponentRunning, true},
+		{ComponentRunning, ComponentPaused, true},
+		{ComponentPaused, ComponentRunning, true},
+		{ComponentPaused, ComponentStopped, true},
+		{ComponentRunning, ComponentRunning, false},
+		{ComponentCreated, ComponentPaused, false},
+		{ComponentStopped, ComponentRunning, false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, tt.from.CanTransitionTo(tt.to), "%s -> %s", tt.from, tt.to)
+	}
+	require.ErrorIs(t, ValidateTransition("c", ComponentStopped, ComponentPaused), ErrFailedPrecondition)
+}
+
+func TestLifecycleTracker(t *testing.T) {
+	lt := NewLifecycleTracker()
+	require.ErrorIs(t, lt.Transition("x", ComponentPaused, nil), ErrNotFound)
+
+	lt.Track("c1", ComponentRunning)
+	require.NoError(t, lt.Transition("c1", ComponentPaused, nil))
+	require.ErrorIs(t, lt.Transition("c1", ComponentPaused, nil), ErrFailedPrecondition)
+
+	// failing callback leaves the state untouched
+	require.Error(t, lt.Transition("c1", ComponentRunning, func() error { return errors.New("nope") }))
+	s, _ := lt.State("c1")
+	require.Equal(t, ComponentPaused, s)
+
+	require.NoError(t, lt.Reset("c1", nil))
+	s, _ = lt.State("c1")
+	require.Equal(t, ComponentRunning, s)
+
+	lt.Forget("c1")
+	_, ok := lt.State("c1")
+	require.False(t, ok)
+}
+
+func TestLifecycleTracker_PluginCallsDoNotBlockOtherComponents(t *testing.T) {
+	lt := NewLifecycleTracker()
+	lt.Track("slow", ComponentRunning)
+	lt.Track("other", ComponentRunning)
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- lt.Transition("slow", ComponentPaused, func() error {
+			close(entered)
+			<-release
+			return nil
+		})
+	}()
+	<-entered
+
+	checked := make(chan error, 1)
+	go func() {
+		_, _ = lt.State("slow")
+		checked <- lt.Transition("other", ComponentPaused, nil)
+	}()
+	select {
+	case err := <-checked:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("a slow plugin call blocked another component")
+	}
+
+	close(release)
+	require.NoError(t, <-done)
+	s, _ := lt.State("slow")
+	require.Equal(t, ComponentPaused, s)
+}
+
+type lifecyclePlugin struct {
+	dummyPlugin
+	calls []string
+}
+
+func (p *lifecyclePlugin) PauseComponent(id string) error {
+	p.calls = append(p.calls, "pause:"+id)
+	return nil
+}
+
+func (p *lifecyclePlugin) ResumeComponent(id string) error {
+	p.calls = append(p.calls, "resume:"+id)
+	return nil
+}
+
+func (p *lifecyclePlugin) ResetComponent(id string) error {
+	p.calls = append(p.calls, "reset:"+id)
+	return nil
+}
+
+func TestGRPCAdapter_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	plugin := &lifecyclePlugin{}
+	adapter := NewGRPCAdapter(plugin)
+	id := wrapperspb.String("c1")
+
+	_, err := adapter.PauseComponent(ctx, id)
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = adapter.CreateComponentInstance(ctx, &simsdkrpc.CreateComponentRequest{ComponentId: "c1"})
+	require.NoError(t, err)
+
+	_, err = adapter.ResumeComponent(ctx, id)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "cannot resume a running component")
+
+	_, err = adapter.PauseComponent(ctx, id)
+	require.NoError(t, err)
+	_, err = adapter.PauseComponent(ctx, id)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = adapter.ResumeComponent(ctx, id)
+	require.NoError(t, err)
+	_, err = adapter.ResetComponent(ctx, id)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"pause:c1", "resume:c1", "reset:c1"}, plugin.calls)
+
+	// a repeated create the plugin accepts keeps the component paused
+	_, err = adapter.PauseComponent(ctx, id)
+	require.NoError(t, err)
+	_, err = adapter.CreateComponentInstance(ctx, &simsdkrpc.CreateComponentRequest{ComponentId: "c1"})
+	require.NoError(t, err)
+	_, err = adapter.ResumeComponent(ctx, id)
+	require.NoError(t, err)
+
+	_, err = adapter.DestroyComponentInstance(ctx, id)
+	require.NoError(t, err)
+	_, err = adapter.ResetComponent(ctx, id)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCAdapter_LifecycleUnimplemented(t *testing.T) {
+	adapter := NewGRPCAdapter(&dummyPlugin{})
+	_, err := adapter.PauseComponent(context.Background(), wrapperspb.String("c1"))
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
@@ -0,0 +1,71 @@
+package simsdk
+
+import (
+	"log"
+	"log/slog"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+)
+
+// WithLogger sets the logger for the adapter and every MessageStream it
+// serves. Records carry the plugin name under "plugin". Without it the SDK
+// logs to slog.Default().
+func WithLogger(logger *slog.Logger) AdapterOption {
+	return func(g *grpcAdapter) { g.log = logger }
+}
+
+// WithStreamLogger sets the logger for ServeStream. Per-message records are
+// logged at debug level; failures at warn or error.
+func WithStreamLogger(logger *slog.Logger) StreamOption {
+	return func(o *streamOptions) { o.logger = logger }
+}
+
+// LoggerFromLog returns a *slog.Logger that writes text records through l,
+// which adds its own prefix and timestamp. It lets APIs that take a
+// *log.Logger log through slog. A nil l gives slog.Default().
+func LoggerFromLog(l *log.Logger) *slog.Logger {
+	if l == nil {
+		return slog.Default()
+	}
+	return slog.New(slog.NewTextHandler(logWriter{l}, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// logWriter writes each record as one log.Logger entry.
+type logWriter struct{ l *log.Logger }
+
+func (w logWriter) Write(p []byte) (int, error) {
+	return len(p), w.l.Output(2, string(p))
+}
+
+// loggerOr returns logger, or slog.Default() when it is nil.
+func loggerOr(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
+
+// messageAttrs returns the attributes identifying an inbound message.
+func messageAttrs(m *simsdkrpc.SimMessage) []any {
+	return []any{
+		slog.String("component_id", m.GetComponentId()),
+		slog.String("message_id", m.GetMessageId()),
+		slog.String("message_type", m.GetMessageType()),
+	}
+}
+
+// simMessageAttrs returns the attributes identifying an SDK message.
+func simMessageAttrs(m *SimMessage) []any {
+	return []any{
+		slog.String("component_id", m.ComponentID),
+		slog.String("message_id", m.MessageID),
+		slog.String("message_type", m.MessageType),
+	}
+}
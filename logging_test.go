@@ -0,0 +1,65 @@
+package simsdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// logRecords decodes the JSON records written to buf.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var r map[string]any
+		require.NoError(t, dec.Decode(&r))
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestGRPCAdapter_LogsStructuredAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	adapter := NewGRPCAdapter(&dummyPlugin{}, WithLogger(logger))
+
+	env := simMessageEnvelope("m1", "c1")
+	env.GetSimMessage().MessageType = "Position"
+	require.NoError(t, adapter.MessageStream(&mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{initEnvelope("c1"), env}}))
+
+	var received map[string]any
+	for _, r := range logRecords(t, &buf) {
+		require.Equal(t, "TestManifest", r["plugin"])
+		if r["msg"] == "Received SimMessage" {
+			received = r
+		}
+	}
+	require.NotNil(t, received)
+	require.Equal(t, "DEBUG", received["level"])
+	require.Equal(t, "c1", received["component_id"])
+	require.Equal(t, "m1", received["message_id"])
+	require.Equal(t, "Position", received["message_type"])
+}
+
+func TestServeStream_PerMessageLogsAreDebug(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{initEnvelope("a"), simMessageEnvelope("m1", "a"), simMessageEnvelope("m2", "a")}}
+	require.NoError(t, ServeStream(&recordingHandler{}, stream, WithStreamLogger(logger)))
+
+	for _, r := range logRecords(t, &buf) {
+		require.NotContains(t, r, "message_id", "per-message records are hidden at info level: %v", r)
+	}
+}
+
+func TestLoggerFromLog_WritesThroughLogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	LoggerFromLog(log.New(&buf, "[plugin] ", 0)).Info("Plugin listening", slog.String("addr", ":9000"))
+	require.Equal(t, "[plugin] level=INFO msg=\"Plugin listening\" addr=:9000\n", buf.String())
+	require.Equal(t, slog.Default(), LoggerFromLog(nil))
+}
@@ -0,0 +1,424 @@
+package simsdk
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the handler
+// latency histogram.
+var DefaultLatencyBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+
+// Metrics is a registry of counters, gauges and histograms exposed in the
+// Prometheus text format. The SDK records its own metrics into it when it is
+// passed to WithMetrics or WithStreamMetrics; plugins may register their own.
+// A nil *Metrics records nothing, and so do the metrics it returns.
+type Metrics struct {
+	mu       sync.Mutex
+	families map[string]*metricFamily
+
+	sdkOnce sync.Once
+	sdk     *sdkMetrics
+}
+
+// NewMetrics returns an empty registry.
+func NewMetrics() *Metrics {
+	return &Metrics{families: make(map[string]*metricFamily)}
+}
+
+type metricKind string
+
+const (
+	counterKind   metricKind = "counter"
+	gaugeKind     metricKind = "gauge"
+	histogramKind metricKind = "histogram"
+)
+
+// metricFamily is one named metric and its series, one per set of label values.
+type metricFamily struct {
+	name    string
+	help    string
+	kind    metricKind
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*metricSeries
+	funcs  map[*func() float64]struct{} // gauge sources summed at scrape time
+}
+
+type metricSeries struct {
+	values []string
+	value  float64
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// family returns the family with the given name, registering it on first use.
+// Registering a name again with a different kind or labels panics.
+func (m *Metrics) family(name, help string, kind metricKind, buckets []float64, labels []string) *metricFamily {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f, ok := m.families[name]; ok {
+		if f.kind != kind || !slices.Equal(f.labels, labels) {
+			panic(fmt.Sprintf("simsdk: metric %q registered twice with different kinds or labels", name))
+		}
+		return f
+	}
+	f := &metricFamily{name: name, help: help, kind: kind, labels: labels, buckets: buckets, series: make(map[string]*metricSeries)}
+	m.families[name] = f
+	return f
+}
+
+// with returns the series for the given label values, creating it if needed.
+// Caller holds f.mu.
+func (f *metricFamily) with(values []string) *metricSeries {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("simsdk: metric %q takes %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &metricSeries{values: slices.Clone(values)}
+		if f.kind == histogramKind {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Counter is a monotonically increasing metric.
+type Counter struct{ f *metricFamily }
+
+// Counter returns the counter with the given name, registering it on first use.
+func (m *Metrics) Counter(name, help string, labels ...string) *Counter {
+	if m == nil {
+		return nil
+	}
+	return &Counter{m.family(name, help, counterKind, nil, labels)}
+}
+
+// Inc adds one to the series with the given label values.
+func (c *Counter) Inc(labelValues ...string) { c.Add(1, labelValues...) }
+
+// Add adds v, which must not be negative, to the series with the given label values.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if c == nil || v < 0 {
+		return
+	}
+	c.f.mu.Lock()
+	c.f.with(labelValues).value += v
+	c.f.mu.Unlock()
+}
+
+// Gauge is a metric that can go up and down.
+type Gauge struct{ f *metricFamily }
+
+// Gauge returns the gauge with the given name, registering it on first use.
+func (m *Metrics) Gauge(name, help string, labels ...string) *Gauge {
+	if m == nil {
+		return nil
+	}
+	return &Gauge{m.family(name, help, gaugeKind, nil, labels)}
+}
+
+// Set sets the series with the given label values to v.
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	if g == nil {
+		return
+	}
+	g.f.mu.Lock()
+	g.f.with(labelValues).value = v
+	g.f.mu.Unlock()
+}
+
+// Add adds v, which may be negative, to the series with the given label values.
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	if g == nil {
+		return
+	}
+	g.f.mu.Lock()
+	g.f.with(labelValues).value += v
+	g.f.mu.Unlock()
+}
+
+// track adds fn to the value of a gauge without labels at every scrape until
+// the returned function is called. A tracked total has no label values to go
+// with, so tracking a gauge with labels panics.
+func (g *Gauge) track(fn func() float64) (untrack func()) {
+	if g == nil {
+		return func() {}
+	}
+	if len(g.f.labels) > 0 {
+		panic(fmt.Sprintf("simsdk: metric %q has labels and cannot track a function", g.f.name))
+	}
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	if g.f.funcs == nil {
+		g.f.funcs = make(map[*func() float64]struct{})
+	}
+	key := &fn
+	g.f.funcs[key] = struct{}{}
+	return func() {
+		g.f.mu.Lock()
+		defer g.f.mu.Unlock()
+		delete(g.f.funcs, key)
+	}
+}
+
+// Histogram counts observations into buckets.
+type Histogram struct{ f *metricFamily }
+
+// Histogram returns the histogram with the given name, registering it on
+// first use. Nil buckets use DefaultLatencyBuckets.
+func (m *Metrics) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if m == nil {
+		return nil
+	}
+	if buckets == nil {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	return &Histogram{m.family(name, help, histogramKind, buckets, labels)}
+}
+
+// Observe records v in the series with the given label values.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	if h == nil {
+		return
+	}
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+	s := h.f.with(labelValues)
+	if i, _ := slices.BinarySearch(h.f.buckets, v); i < len(s.counts) {
+		s.counts[i]++
+	}
+	s.sum += v
+	s.count++
+}
+
+// ObserveSince records the seconds elapsed since start.
+func (h *Histogram) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// WritePrometheus writes every metric in the Prometheus text exposition format.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	families := make([]*metricFamily, 0, len(m.families))
+	for _, f := range m.families {
+		families = append(families, f)
+	}
+	m.mu.Unlock()
+	slices.SortFunc(families, func(a, b *metricFamily) int { return strings.Compare(a.name, b.name) })
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = m.WritePrometheus(w)
+	})
+}
+
+func (f *metricFamily) write(w *bufio.Writer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	series := make([]*metricSeries, 0, len(f.series))
+	for _, s := range f.series {
+		series = append(series, s)
+	}
+	slices.SortFunc(series, func(a, b *metricSeries) int { return slices.Compare(a.values, b.values) })
+	if f.funcs != nil {
+		// Only unlabelled gauges are tracked, so the set value and the
+		// tracked functions add up to the family's single series.
+		s := f.with(nil)
+		total := s.value
+		for fn := range f.funcs {
+			total += (*fn)()
+		}
+		series = []*metricSeries{{values: s.values, value: total}}
+	}
+	if len(series) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+	for _, s := range series {
+		labels := formatLabels(f.labels, s.values)
+		if f.kind != histogramKind {
+			fmt.Fprintf(w, "%s%s %s\n", f.name, labels, formatFloat(s.value))
+			continue
+		}
+		var cumulative uint64
+		for i, le := range f.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, formatLabels(append(slices.Clone(f.labels), "le"), append(slices.Clone(s.values), formatFloat(le))), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, formatLabels(append(slices.Clone(f.labels), "le"), append(slices.Clone(s.values), "+Inf")), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", f.name, labels, formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", f.name, labels, s.count)
+	}
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(values[i]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeHelp(s string) string { return helpEscaper.Replace(s) }
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// WithMetrics records the SDK's metrics for the adapter's unary calls and
+// every MessageStream it serves into m.
+func WithMetrics(m *Metrics) AdapterOption {
+	return func(g *grpcAdapter) {
+		g.metrics = m.sdkMetrics()
+		g.streamOptions = append(g.streamOptions, WithStreamMetrics(m))
+	}
+}
+
+// WithStreamMetrics records the SDK's metrics for ServeStream into m.
+func WithStreamMetrics(m *Metrics) StreamOption {
+	return func(o *streamOptions) { o.metrics = m.sdkMetrics() }
+}
+
+// sdkMetrics holds the metrics the SDK records itself. A nil *sdkMetrics
+// records nothing.
+type sdkMetrics struct {
+	received   *Counter
+	sent       *Counter
+	acked      *Counter
+	nacked     *Counter
+	latency    *Histogram
+	streams    *Gauge
+	queueDepth *Gauge
+}
+
+func (m *Metrics) sdkMetrics() *sdkMetrics {
+	if m == nil {
+		return nil
+	}
+	m.sdkOnce.Do(func() {
+		m.sdk = &sdkMetrics{
+			received:   m.Counter("simsdk_messages_received_total", "SimMessages received from the core.", "component_id", "message_type"),
+			sent:       m.Counter("simsdk_messages_sent_total", "SimMessages sent to the core.", "component_id", "message_type"),
+			acked:      m.Counter("simsdk_messages_acked_total", "Received SimMessages handled successfully.", "component_id", "message_type"),
+			nacked:     m.Counter("simsdk_messages_nacked_total", "Received SimMessages rejected or failed by their handler.", "component_id", "message_type"),
+			latency:    m.Histogram("simsdk_handler_duration_seconds", "Time spent handling a SimMessage.", nil, "component_id", "message_type"),
+			streams:    m.Gauge("simsdk_streams_active", "MessageStreams currently open."),
+			queueDepth: m.Gauge("simsdk_send_queue_depth", "Envelopes waiting in the outbound queues of open streams."),
+		}
+	})
+	return m.sdk
+}
+
+// handled records the outcome of handling one received message.
+func (s *sdkMetrics) handled(componentID, messageType string, start time.Time, failed bool) {
+	if s == nil {
+		return
+	}
+	s.received.Inc(componentID, messageType)
+	if !start.IsZero() {
+		s.latency.ObserveSince(start, componentID, messageType)
+	}
+	if failed {
+		s.nacked.Inc(componentID, messageType)
+	} else {
+		s.acked.Inc(componentID, messageType)
+	}
+}
+
+// sentMessage records one SimMessage sent to the core.
+func (s *sdkMetrics) sentMessage(componentID, messageType string) {
+	if s == nil {
+		return
+	}
+	s.sent.Inc(componentID, messageType)
+}
+
+// openStream counts a stream as open and tracks its send queue until the
+// returned function is called.
+func (s *sdkMetrics) openStream(w *streamWriter) (closeStream func()) {
+	if s == nil {
+		return func() {}
+	}
+	s.streams.Add(1)
+	untrack := s.queueDepth.track(func() float64 { return float64(w.stats().Depth) })
+	return func() {
+		untrack()
+		s.streams.Add(-1)
+	}
+}
+
+// countingSender counts the SimMessages actually written to the stream. It
+// sits above the chunkingSender, so a chunked message counts once.
+type countingSender struct {
+	out     envelopeSender
+	metrics *sdkMetrics
+}
+
+func (c *countingSender) Send(env *simsdkrpc.PluginMessageEnvelope) error {
+	if err := c.out.Send(env); err != nil {
+		return err
+	}
+	if sm := env.GetSimMessage(); sm != nil {
+		c.metrics.sentMessage(sm.GetComponentId(), sm.GetMessageType())
+	}
+	for _, sm := range env.GetBatch().GetMessages() {
+		c.metrics.sentMessage(sm.GetComponentId(), sm.GetMessageType())
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package simsdk
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	var buf strings.Builder
+	require.NoError(t, m.WritePrometheus(&buf))
+	return buf.String()
+}
+
+func TestMetrics_PrometheusTextFormat(t *testing.T) {
+	m := NewMetrics()
+	m.Counter("jobs_total", "Jobs run.", "queue").Add(3, `a"b`)
+	m.Gauge("temperature", "Current temperature.").Set(21.5)
+	h := m.Histogram("latency_seconds", "Latency.", []float64{0.1, 1})
+	h.Observe(0.05)
+	h.Observe(0.1)
+	h.Observe(2)
+
+	require.Equal(t, `# HELP jobs_total Jobs run.
+# TYPE jobs_total counter
+jobs_total{queue="a\"b"} 3
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 2
+latency_seconds_bucket{le="1"} 2
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 2.15
+latency_seconds_count 3
+# HELP temperature Current temperature.
+# TYPE temperature gauge
+temperature 21.5
+`, scrape(t, m))
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	require.Contains(t, string(body), "temperature 21.5")
+	require.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+}
+
+func TestMetrics_TrackedGaugeAddsToSetValue(t *testing.T) {
+	m := NewMetrics()
+	g := m.Gauge("queue_depth", "Queued items.")
+	g.Set(2)
+	untrackA := g.track(func() float64 { return 3 })
+	g.track(func() float64 { return 4 })
+	require.Contains(t, scrape(t, m), "\nqueue_depth 9\n")
+
+	untrackA()
+	require.Contains(t, scrape(t, m), "\nqueue_depth 6\n")
+}
+
+func TestMetrics_TrackingLabelledGaugePanics(t *testing.T) {
+	m := NewMetrics()
+	g := m.Gauge("queue_depth", "Queued items.", "queue")
+	g.Set(1, "a")
+	require.Panics(t, func() { g.track(func() float64 { return 3 }) })
+	require.Contains(t, scrape(t, m), `queue_depth{queue="a"} 1`, "the labelled series is still written")
+}
+
+func TestMetrics_NilRecordsNothing(t *testing.T) {
+	var m *Metrics
+	m.Counter("c", "").Inc()
+	m.Gauge("g", "").Set(1)
+	m.Histogram("h", "", nil).Observe(1)
+	require.Empty(t, scrape(t, m))
+}
+
+func TestServeStreamMux_RecordsMetrics(t *testing.T) {
+	m := NewMetrics()
+	good := simMessageEnvelope("m1", "a")
+	good.GetSimMessage().MessageType = "Position"
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{initEnvelope("a"), good, simMessageEnvelope("m2", "ghost")}}
+	require.NoError(t, ServeStreamMux(func() StreamHandler { return &recordingHandler{} }, stream, WithStreamMetrics(m)))
+
+	out := scrape(t, m)
+	require.Contains(t, out, `simsdk_messages_received_total{component_id="a",message_type="Position"} 1`)
+	require.Contains(t, out, `simsdk_messages_acked_total{component_id="a",message_type="Position"} 1`)
+	require.Contains(t, out, `simsdk_messages_nacked_total{component_id="ghost",message_type=""} 1`)
+	require.Contains(t, out, `simsdk_messages_sent_total{component_id="a",message_type=""} 1`, "the handler pushes one message")
+	require.Contains(t, out, `simsdk_handler_duration_seconds_count{component_id="a",message_type="Position"} 1`)
+	require.Contains(t, out, "simsdk_streams_active 0")
+	require.Contains(t, out, "simsdk_send_queue_depth 0")
+}
+
+func TestGRPCAdapter_RecordsUnaryMetrics(t *testing.T) {
+	m := NewMetrics()
+	adapter := NewGRPCAdapter(&dummyPlugin{}, WithMetrics(m))
+	_, err := adapter.HandleMessage(t.Context(), &simsdkrpc.SimMessage{MessageId: "m1", ComponentId: "c1", MessageType: "Ping"})
+	require.NoError(t, err)
+
+	out := scrape(t, m)
+	require.Contains(t, out, `simsdk_messages_acked_total{component_id="c1",message_type="Ping"} 1`)
+	require.Contains(t, out, `simsdk_messages_sent_total{component_id="CompReply",message_type="ReplyType"} 1`)
+}
@@ -0,0 +1,181 @@
+package simsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// CallKind tells which path a call came in on.
+type CallKind int
+
+const (
+	StreamCall    CallKind = iota + 1 // A SimMessage received on a MessageStream
+	UnaryCall                         // A SimMessage received via HandleMessage or HandleMessages
+	LifecycleCall                     // Create, destroy, pause, resume, reset, snapshot, restore or a control function
+)
+
+func (k CallKind) String() string {
+	switch k {
+	case StreamCall:
+		return "stream"
+	case UnaryCall:
+		return "unary"
+	case LifecycleCall:
+		return "lifecycle"
+	default:
+		return fmt.Sprintf("CallKind(%d)", int(k))
+	}
+}
+
+// Call describes one call passing through the middleware chain.
+type Call struct {
+	Kind        CallKind
+	Method      string // e.g. "OnSimMessage", "HandleMessage", "PauseComponent"
+	ComponentID string
+	Message     *SimMessage // Inbound message; nil for lifecycle calls
+	Request     any         // Lifecycle request, e.g. CreateComponentRequest, ComponentSnapshot or ControlFunctionRequest; nil otherwise
+}
+
+// MessageHandler handles a call and returns the messages it produced.
+// Lifecycle calls produce no messages.
+type MessageHandler func(ctx context.Context, call *Call) ([]*SimMessage, error)
+
+// Middleware wraps a MessageHandler with cross-cutting behavior. It may
+// inspect or modify the call, short-circuit it, or post-process the result.
+type Middleware func(next MessageHandler) MessageHandler
+
+// Chain composes middlewares so that the first one is outermost.
+func Chain(mws ...Middleware) Middleware {
+	return func(next MessageHandler) MessageHandler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
+		return next
+	}
+}
+
+// WithStreamMiddleware runs every inbound SimMessage on the stream through the
+// middlewares before its handler. With middleware installed, batch envelopes
+// are handled message by message so each message passes through the chain.
+func WithStreamMiddleware(mws ...Middleware) StreamOption {
+	return func(o *streamOptions) { o.middleware = append(o.middleware, mws...) }
+}
+
+// WithMiddleware installs middlewares on the adapter. They run around unary
+// messages, lifecycle calls and every MessageStream the adapter serves.
+func WithMiddleware(mws ...Middleware) AdapterOption {
+	return func(g *grpcAdapter) {
+		g.middleware = append(g.middleware, mws...)
+		g.streamOptions = append(g.streamOptions, WithStreamMiddleware(mws...))
+	}
+}
+
+// chainOf composes mws, or returns nil when there are none.
+func chainOf(mws []Middleware) Middleware {
+	if len(mws) == 0 {
+		return nil
+	}
+	return Chain(mws...)
+}
+
+// intercept runs handle through chain, or calls it directly when chain is nil.
+func intercept(chain Middleware, ctx context.Context, call *Call, handle MessageHandler) ([]*SimMessage, error) {
+	if chain == nil {
+		return handle(ctx, call)
+	}
+	return chain(handle)(ctx, call)
+}
+
+// ErrHandlerPanic is wrapped by every *PanicError, whether the panic was
+// recovered by the SDK around a handler or by Recover.
+var ErrHandlerPanic = errors.New("handler panicked")
+
+// Recover turns a panic in the rest of the chain into a *PanicError and logs
+// the stack to slog.Default(). Handler panics are always recovered by the SDK,
+// so Recover only matters for panics in the middlewares after it.
+func Recover() Middleware {
+	return func(next MessageHandler) MessageHandler {
+		return func(ctx context.Context, call *Call) (out []*SimMessage, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					out, err = nil, newPanicError(slog.Default(), call, r)
+				}
+			}()
+			return next(ctx, call)
+		}
+	}
+}
+
+// Logging logs every call with its duration and outcome. Message calls are
+// logged at debug level, lifecycle calls at info and failures at warn. A nil
+// logger uses slog.Default().
+func Logging(logger *slog.Logger) Middleware {
+	logger = loggerOr(logger)
+	return func(next MessageHandler) MessageHandler {
+		return func(ctx context.Context, call *Call) ([]*SimMessage, error) {
+			start := time.Now()
+			out, err := next(ctx, call)
+			attrs := []any{slog.String("kind", call.Kind.String()), slog.String("method", call.Method)}
+			if call.Message != nil {
+				attrs = append(attrs, simMessageAttrs(call.Message)...)
+			} else {
+				attrs = append(attrs, slog.String("component_id", call.ComponentID))
+			}
+			attrs = append(attrs, slog.Duration("took", time.Since(start)))
+			switch {
+			case err != nil:
+				logger.WarnContext(ctx, "Call failed", append(attrs, slog.Any("error", err))...)
+			case call.Kind == LifecycleCall:
+				logger.InfoContext(ctx, "Call handled", attrs...)
+			default:
+				logger.DebugContext(ctx, "Call handled", append(attrs, slog.Int("responses", len(out)))...)
+			}
+			return out, err
+		}
+	}
+}
+
+// Validate rejects calls for which check returns an error. The error is
+// returned as ErrInvalidArgument unless it already has an SDK kind.
+func Validate(check func(ctx context.Context, call *Call) error) Middleware {
+	return guard(check, ErrInvalidArgument)
+}
+
+// Authorize rejects calls for which check returns an error. The error is
+// returned as ErrPermissionDenied unless it already has an SDK kind.
+func Authorize(check func(ctx context.Context, call *Call) error) Middleware {
+	return guard(check, ErrPermissionDenied)
+}
+
+func guard(check func(ctx context.Context, call *Call) error, kind error) Middleware {
+	return func(next MessageHandler) MessageHandler {
+		return func(ctx context.Context, call *Call) ([]*SimMessage, error) {
+			if err := check(ctx, call); err != nil {
+				var sdkErr *Error
+				if !errors.As(err, &sdkErr) {
+					err = WrapError(kind, call.ComponentID, err)
+				}
+				return nil, err
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// KnownMessageTypes rejects messages whose MessageType is not declared in
+// the manifest. Lifecycle calls pass through.
+func KnownMessageTypes(m Manifest) Middleware {
+	known := make(map[string]bool, len(m.MessageTypes))
+	for _, mt := range m.MessageTypes {
+		known[mt.ID] = true
+	}
+	return Validate(func(_ context.Context, call *Call) error {
+		if call.Message == nil || known[call.Message.MessageType] {
+			return nil
+		}
+		return NewError(ErrInvalidArgument, call.ComponentID, "message type %q is not declared in the manifest", call.Message.MessageType)
+	})
+}
@@ -0,0 +1,136 @@
+package simsdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// callLog is a middleware that records every call it sees.
+type callLog struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (l *callLog) middleware(next MessageHandler) MessageHandler {
+	return func(ctx context.Context, call *Call) ([]*SimMessage, error) {
+		l.mu.Lock()
+		l.calls = append(l.calls, call.Kind.String()+":"+call.Method+":"+call.ComponentID)
+		l.mu.Unlock()
+		return next(ctx, call)
+	}
+}
+
+func TestChain_FirstMiddlewareIsOutermost(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next MessageHandler) MessageHandler {
+			return func(ctx context.Context, call *Call) ([]*SimMessage, error) {
+				order = append(order, name+">")
+				out, err := next(ctx, call)
+				order = append(order, "<"+name)
+				return out, err
+			}
+		}
+	}
+	h := Chain(tag("a"), tag("b"))(func(context.Context, *Call) ([]*SimMessage, error) {
+		order = append(order, "handler")
+		return nil, nil
+	})
+	_, err := h(context.Background(), &Call{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a>", "b>", "handler", "<b", "<a"}, order)
+}
+
+func TestBuiltinMiddlewares(t *testing.T) {
+	ctx := context.Background()
+	panics := func(context.Context, *Call) ([]*SimMessage, error) { panic("boom") }
+	_, err := Recover()(panics)(ctx, &Call{Method: "OnSimMessage"})
+	require.ErrorIs(t, err, ErrHandlerPanic)
+	require.ErrorContains(t, err, "boom")
+
+	ok := func(context.Context, *Call) ([]*SimMessage, error) { return nil, nil }
+	deny := func(context.Context, *Call) error { return errors.New("no token") }
+	_, err = Authorize(deny)(ok)(ctx, &Call{ComponentID: "c1"})
+	require.ErrorIs(t, err, ErrPermissionDenied)
+	_, err = Validate(deny)(ok)(ctx, &Call{})
+	require.ErrorIs(t, err, ErrInvalidArgument)
+
+	known := KnownMessageTypes(Manifest{MessageTypes: []MessageType{{ID: "Position"}}})(ok)
+	_, err = known(ctx, &Call{Message: &SimMessage{MessageType: "Position"}})
+	require.NoError(t, err)
+	_, err = known(ctx, &Call{Message: &SimMessage{MessageType: "Bogus"}})
+	require.ErrorIs(t, err, ErrInvalidArgument)
+	_, err = known(ctx, &Call{Kind: LifecycleCall})
+	require.NoError(t, err)
+}
+
+func TestGRPCAdapter_MiddlewareSeesEveryPath(t *testing.T) {
+	ctx := context.Background()
+	var seen callLog
+	adapter := NewGRPCAdapter(&lifecyclePlugin{}, WithMiddleware(seen.middleware))
+
+	_, err := adapter.CreateComponentInstance(ctx, &simsdkrpc.CreateComponentRequest{ComponentId: "c1"})
+	require.NoError(t, err)
+	_, err = adapter.PauseComponent(ctx, wrapperspb.String("c1"))
+	require.NoError(t, err)
+	_, err = adapter.HandleMessage(ctx, &simsdkrpc.SimMessage{MessageId: "m1", ComponentId: "c1"})
+	require.NoError(t, err)
+	_, err = adapter.HandleMessages(ctx, batchOf("m2"))
+	require.NoError(t, err)
+	require.NoError(t, adapter.MessageStream(&mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{initEnvelope("c1"), simMessageEnvelope("m3", "c1")}}))
+	_, err = adapter.DestroyComponentInstance(ctx, wrapperspb.String("c1"))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"lifecycle:CreateComponentInstance:c1",
+		"lifecycle:PauseComponent:c1",
+		"unary:HandleMessage:c1",
+		"unary:HandleMessages:a",
+		"stream:OnSimMessage:c1",
+		"lifecycle:DestroyComponentInstance:c1",
+	}, seen.calls)
+}
+
+func TestGRPCAdapter_MiddlewareCanReject(t *testing.T) {
+	plugin := &lifecyclePlugin{}
+	adapter := NewGRPCAdapter(plugin, WithMiddleware(Authorize(func(_ context.Context, call *Call) error {
+		if call.Method == "ResetComponent" {
+			return errors.New("operators only")
+		}
+		return nil
+	})))
+	ctx := context.Background()
+	_, err := adapter.CreateComponentInstance(ctx, &simsdkrpc.CreateComponentRequest{ComponentId: "c1"})
+	require.NoError(t, err)
+	_, err = adapter.ResetComponent(ctx, wrapperspb.String("c1"))
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.Empty(t, plugin.calls, "the plugin is never reached")
+}
+
+// panickingHandler panics on every message.
+type panickingHandler struct{ recordingHandler }
+
+func (h *panickingHandler) OnSimMessage(*SimMessage) ([]*SimMessage, error) { panic("bad frame") }
+
+func TestServeStream_RecoverMiddlewareNaksPanics(t *testing.T) {
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{initEnvelope("a"), simMessageEnvelope("m1", "a")}}
+	require.NoError(t, ServeStream(&panickingHandler{}, stream, WithStreamMiddleware(Recover())))
+
+	var nak *simsdkrpc.PluginNak
+	for _, env := range stream.sent {
+		if n := env.GetNak(); n != nil {
+			nak = n
+		}
+	}
+	require.NotNil(t, nak)
+	require.Equal(t, "m1", nak.MessageId)
+	require.Contains(t, nak.ErrorMessage, "bad frame")
+}
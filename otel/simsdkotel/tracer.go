@@ -0,0 +1,105 @@
+// Package simsdkotel adapts an OpenTelemetry tracer to simsdk.Tracer, so the
+// SDK's spans join the plugin's OpenTelemetry traces:
+//
+//	simsdk.SetDefaultTracer(simsdkotel.NewTracer(otel.Tracer("my-plugin")))
+package simsdkotel
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/neurosimio/simsdk-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTracer returns a simsdk.Tracer that starts spans with t.
+func NewTracer(t trace.Tracer) simsdk.Tracer {
+	return &tracer{t: t}
+}
+
+type tracer struct{ t trace.Tracer }
+
+func (t *tracer) Start(ctx context.Context, name string, kind simsdk.SpanKind, attrs ...slog.Attr) (context.Context, simsdk.Span) {
+	// A parent that only the SDK knows about, e.g. one extracted from
+	// SimMessage metadata, becomes OpenTelemetry's remote parent.
+	if parent := ToOTel(simsdk.SpanContextFromContext(ctx)); parent.IsValid() && !parent.Equal(trace.SpanContextFromContext(ctx)) {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, parent)
+	}
+	ctx, s := t.t.Start(ctx, name, trace.WithSpanKind(spanKind(kind)), trace.WithAttributes(attributes(attrs)...))
+	return ctx, &span{s: s}
+}
+
+type span struct{ s trace.Span }
+
+func (s *span) SpanContext() simsdk.SpanContext { return FromOTel(s.s.SpanContext()) }
+
+func (s *span) SetAttributes(attrs ...slog.Attr) { s.s.SetAttributes(attributes(attrs)...) }
+
+func (s *span) RecordError(err error) {
+	s.s.RecordError(err)
+	s.s.SetStatus(codes.Error, err.Error())
+}
+
+func (s *span) End() { s.s.End() }
+
+// ToOTel converts an SDK span context to OpenTelemetry's, marked as remote.
+// An unparseable tracestate is dropped.
+func ToOTel(sc simsdk.SpanContext) trace.SpanContext {
+	state, _ := trace.ParseTraceState(sc.TraceState)
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    sc.TraceID,
+		SpanID:     sc.SpanID,
+		TraceFlags: trace.TraceFlags(sc.TraceFlags),
+		TraceState: state,
+		Remote:     true,
+	})
+}
+
+// FromOTel converts an OpenTelemetry span context to the SDK's.
+func FromOTel(sc trace.SpanContext) simsdk.SpanContext {
+	return simsdk.SpanContext{
+		TraceID:    sc.TraceID(),
+		SpanID:     sc.SpanID(),
+		TraceFlags: byte(sc.TraceFlags()),
+		TraceState: sc.TraceState().String(),
+	}
+}
+
+func spanKind(k simsdk.SpanKind) trace.SpanKind {
+	switch k {
+	case simsdk.SpanKindServer:
+		return trace.SpanKindServer
+	case simsdk.SpanKindClient:
+		return trace.SpanKindClient
+	case simsdk.SpanKindProducer:
+		return trace.SpanKindProducer
+	case simsdk.SpanKindConsumer:
+		return trace.SpanKindConsumer
+	default:
+		return trace.SpanKindInternal
+	}
+}
+
+func attributes(attrs []slog.Attr) []attribute.KeyValue {
+	out := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		v := a.Value.Resolve()
+		switch v.Kind() {
+		case slog.KindString:
+			out = append(out, attribute.String(a.Key, v.String()))
+		case slog.KindInt64:
+			out = append(out, attribute.Int64(a.Key, v.Int64()))
+		case slog.KindUint64:
+			out = append(out, attribute.Int64(a.Key, int64(v.Uint64())))
+		case slog.KindFloat64:
+			out = append(out, attribute.Float64(a.Key, v.Float64()))
+		case slog.KindBool:
+			out = append(out, attribute.Bool(a.Key, v.Bool()))
+		default:
+			out = append(out, attribute.String(a.Key, v.String()))
+		}
+	}
+	return out
+}
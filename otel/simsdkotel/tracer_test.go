@@ -0,0 +1,33 @@
+package simsdkotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neurosimio/simsdk-go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestTracer_ContinuesTraceFromMessageMetadata(t *testing.T) {
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	msg := &simsdk.SimMessage{MessageID: "m1", Metadata: map[string]string{
+		simsdk.TraceParentKey: parent,
+		simsdk.TraceStateKey:  "vendor=abc",
+	}}
+
+	// The no-op OpenTelemetry tracer hands back its parent's span context, so
+	// the parent must have reached OpenTelemetry as the remote span context.
+	_, span := simsdk.StartMessageSpan(context.Background(), NewTracer(noop.NewTracerProvider().Tracer("test")), "op", simsdk.SpanKindConsumer, msg)
+	defer span.End()
+	require.Equal(t, parent, span.SpanContext().TraceParent())
+	require.Equal(t, "vendor=abc", span.SpanContext().TraceState)
+	require.Equal(t, parent, msg.Metadata[simsdk.TraceParentKey])
+}
+
+func TestSpanContextConversionRoundTrips(t *testing.T) {
+	sc, err := simsdk.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	sc.TraceState = "a=1,b=2"
+	require.Equal(t, sc, FromOTel(ToOTel(sc)))
+}
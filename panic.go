@@ -0,0 +1,163 @@
+package simsdk
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strconv"
+	"sync"
+)
+
+// PanicError is returned for a handler panic the SDK recovered. Ref appears
+// in the log entry that holds the stack trace, so an error seen by the core
+// can be matched with the plugin's logs.
+type PanicError struct {
+	Method      string
+	ComponentID string
+	Value       any
+	Ref         string
+	Stack       []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in %s for component %q: %v (ref %s)", e.Method, e.ComponentID, e.Value, e.Ref)
+}
+
+func (e *PanicError) Unwrap() error { return ErrHandlerPanic }
+
+// ErrQuarantined is returned for messages sent to a quarantined component.
+var ErrQuarantined = fmt.Errorf("%w: component quarantined", ErrFailedPrecondition)
+
+// newPanicError captures a recovered panic and logs its stack under a new reference.
+func newPanicError(logger *slog.Logger, call *Call, value any) *PanicError {
+	ref := make([]byte, 8)
+	_, _ = rand.Read(ref)
+	e := &PanicError{Method: call.Method, ComponentID: call.ComponentID, Value: value, Ref: hex.EncodeToString(ref), Stack: debug.Stack()}
+	logger.Error("Recovered panic",
+		slog.String("panic_ref", e.Ref),
+		slog.String("method", e.Method),
+		slog.String("component_id", e.ComponentID),
+		slog.Any("panic", e.Value),
+		slog.String("stack", string(e.Stack)))
+	return e
+}
+
+// QuarantineOptions configures what happens to components whose handlers
+// keep panicking.
+type QuarantineOptions struct {
+	MaxPanics    int                                        // Panics after which a component is quarantined; 0 never quarantines
+	OnQuarantine func(componentID string, last *PanicError) // Optional: called once when a component is quarantined
+}
+
+// WithQuarantine quarantines components after repeated panics in the adapter's
+// unary calls and streams. A quarantined component's messages are rejected
+// with ErrQuarantined until it is reset, destroyed, recreated or restored.
+func WithQuarantine(opts QuarantineOptions) AdapterOption {
+	return func(g *grpcAdapter) { g.quarantine = opts }
+}
+
+// WithStreamQuarantine quarantines components served by ServeStream after
+// repeated panics. Shutting a component down releases it.
+func WithStreamQuarantine(opts QuarantineOptions) StreamOption {
+	return func(o *streamOptions) { o.quarantine = opts }
+}
+
+// withPanicTracker shares the adapter's panic counts with its streams.
+func withPanicTracker(t *panicTracker) StreamOption {
+	return func(o *streamOptions) { o.panics = t }
+}
+
+// panicTracker recovers handler panics and counts them per component.
+type panicTracker struct {
+	opts QuarantineOptions
+	log  *slog.Logger
+
+	mu          sync.Mutex
+	counts      map[string]int
+	quarantined map[string]bool
+}
+
+func newPanicTracker(opts QuarantineOptions, logger *slog.Logger) *panicTracker {
+	return &panicTracker{opts: opts, log: logger, counts: make(map[string]int), quarantined: make(map[string]bool)}
+}
+
+// run calls fn, converting a panic into a *PanicError counted against the
+// call's component.
+func (t *panicTracker) run(call *Call, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			perr := newPanicError(t.log, call, r)
+			t.record(perr)
+			err = perr
+		}
+	}()
+	return fn()
+}
+
+// check returns ErrQuarantined if the component is quarantined.
+func (t *panicTracker) check(componentID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.quarantined[componentID] {
+		return nil
+	}
+	e := WrapError(ErrQuarantined, componentID, nil)
+	e.Message = fmt.Sprintf("component %q is quarantined after %d panics", componentID, t.counts[componentID])
+	return e
+}
+
+// anyQuarantined reports whether any of msgs is for a quarantined component.
+func (t *panicTracker) anyQuarantined(msgs []SimMessage) bool {
+	for _, m := range msgs {
+		if t.check(m.ComponentID) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *panicTracker) record(perr *PanicError) {
+	t.mu.Lock()
+	t.counts[perr.ComponentID]++
+	quarantine := t.opts.MaxPanics > 0 && !t.quarantined[perr.ComponentID] && t.counts[perr.ComponentID] >= t.opts.MaxPanics
+	if quarantine {
+		t.quarantined[perr.ComponentID] = true
+	}
+	t.mu.Unlock()
+
+	if quarantine {
+		t.log.Error("Quarantining component", slog.String("component_id", perr.ComponentID), slog.Int("panics", t.opts.MaxPanics))
+		if t.opts.OnQuarantine != nil {
+			t.opts.OnQuarantine(perr.ComponentID, perr)
+		}
+	}
+}
+
+// clear forgets a component's panics and releases it from quarantine.
+func (t *panicTracker) clear(componentID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.counts, componentID)
+	delete(t.quarantined, componentID)
+}
+
+// status adds the component's panic count and quarantine flag to cs.Fields.
+func (t *panicTracker) status(componentID string, cs *ComponentStatus) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := t.counts[componentID]
+	if n == 0 {
+		return
+	}
+	fields := make(map[string]string, len(cs.Fields)+2)
+	for k, v := range cs.Fields {
+		fields[k] = v
+	}
+	fields["panics"] = strconv.Itoa(n)
+	if t.quarantined[componentID] {
+		fields["quarantined"] = "true"
+	}
+	cs.Fields = fields
+}
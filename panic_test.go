@@ -0,0 +1,197 @@
+package simsdk
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestServeStreamMux_QuarantinesPanickingComponent(t *testing.T) {
+	var quarantined []string
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"), initEnvelope("b"),
+		simMessageEnvelope("m1", "a"), simMessageEnvelope("m2", "a"), simMessageEnvelope("m3", "a"),
+		simMessageEnvelope("m4", "b"),
+	}}
+	handlers := []StreamHandler{&panickingHandler{}, &recordingHandler{}}
+	require.NoError(t, ServeStreamMux(func() StreamHandler {
+		h := handlers[0]
+		handlers = handlers[1:]
+		return h
+	}, stream, WithStreamQuarantine(QuarantineOptions{
+		MaxPanics:    2,
+		OnQuarantine: func(id string, _ *PanicError) { quarantined = append(quarantined, id) },
+	})))
+
+	naks := map[string]string{}
+	var acked []string
+	for _, env := range stream.sent {
+		if n := env.GetNak(); n != nil {
+			naks[n.MessageId] = n.ErrorMessage
+		}
+		if a := env.GetAck(); a != nil {
+			acked = append(acked, a.MessageId)
+		}
+	}
+	require.Contains(t, naks["m1"], "bad frame")
+	require.Contains(t, naks["m1"], "(ref ")
+	require.Contains(t, naks["m2"], "bad frame")
+	require.Contains(t, naks["m3"], "quarantined after 2 panics")
+	require.Equal(t, []string{"m4"}, acked, "other components keep running")
+	require.Equal(t, []string{"a"}, quarantined)
+}
+
+// panickingPlugin panics on every unary message.
+type panickingPlugin struct{ dummyPlugin }
+
+func (p *panickingPlugin) HandleMessage(SimMessage) ([]SimMessage, error) { panic("nil map") }
+
+func TestGRPCAdapter_PanicsBecomeInternalErrors(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewGRPCAdapter(&panickingPlugin{}, WithQuarantine(QuarantineOptions{MaxPanics: 1}))
+
+	_, err := adapter.HandleMessage(ctx, &simsdkrpc.SimMessage{MessageId: "m1", ComponentId: "c1"})
+	st, _ := status.FromError(err)
+	require.Equal(t, codes.Internal, st.Code())
+	require.Contains(t, st.Message(), "nil map")
+	require.Len(t, st.Details(), 1)
+	info := st.Details()[0].(*errdetails.ErrorInfo)
+	require.Equal(t, "HANDLER_PANIC", info.Reason)
+	require.Equal(t, "c1", info.Metadata["component_id"])
+	require.NotEmpty(t, info.Metadata["panic_ref"])
+
+	_, err = adapter.HandleMessage(ctx, &simsdkrpc.SimMessage{MessageId: "m2", ComponentId: "c1"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	resp, err := adapter.HandleMessages(ctx, &simsdkrpc.MessageBatch{Messages: []*simsdkrpc.SimMessage{{MessageId: "m3", ComponentId: "c1"}}})
+	require.NoError(t, err)
+	require.Contains(t, resp.Results[0].GetNak().ErrorMessage, "quarantined")
+
+	_, err = adapter.HandleMessage(ctx, &simsdkrpc.SimMessage{MessageId: "m4", ComponentId: "c2"})
+	require.Equal(t, codes.Internal, status.Code(err), "other components are not quarantined")
+
+	_, err = adapter.DestroyComponentInstance(ctx, wrapperspb.String("c1"))
+	require.NoError(t, err)
+	_, err = adapter.HandleMessage(ctx, &simsdkrpc.SimMessage{MessageId: "m5", ComponentId: "c1"})
+	require.Equal(t, codes.Internal, status.Code(err), "destroying a component releases it")
+}
+
+func TestPanicTracker_StatusFields(t *testing.T) {
+	tracker := newPanicTracker(QuarantineOptions{MaxPanics: 2}, slog.New(slog.DiscardHandler))
+	call := &Call{Method: "OnSimMessage", ComponentID: "a"}
+	for range 2 {
+		err := tracker.run(call, func() error { panic("boom") })
+		require.ErrorIs(t, err, ErrHandlerPanic)
+	}
+
+	cs := ComponentStatus{Fields: map[string]string{"mode": "fast"}}
+	tracker.status("a", &cs)
+	require.Equal(t, map[string]string{"mode": "fast", "panics": "2", "quarantined": "true"}, cs.Fields)
+
+	healthy := ComponentStatus{}
+	tracker.status("b", &healthy)
+	require.Nil(t, healthy.Fields)
+}
+
+// panickingStepper panics in OnStep.
+type panickingStepper struct{ recordingHandler }
+
+func (h *panickingStepper) OnStep(context.Context, time.Time, time.Duration) error { panic("bad step") }
+
+func TestServeStream_StepPanicsAreReported(t *testing.T) {
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		stepBeginEnvelope(1, epoch.Add(time.Second), time.Second),
+	}}
+	require.NoError(t, ServeStreamMux(func() StreamHandler { return &panickingStepper{} }, stream))
+
+	var complete *simsdkrpc.PluginStepComplete
+	for _, env := range stream.sent {
+		if c := env.GetStepComplete(); c != nil {
+			complete = c
+		}
+	}
+	require.NotNil(t, complete)
+	require.Contains(t, complete.ComponentErrors["a"], "panic in OnStep")
+	require.Contains(t, complete.ComponentErrors["a"], "bad step")
+}
+
+// panickingSetter panics when given its stream sender.
+type panickingSetter struct{ recordingHandler }
+
+func (h *panickingSetter) SetStreamSender(StreamSender) { panic("no sender wanted") }
+
+func TestServeStream_InitPanicsEndTheStreamWithAnError(t *testing.T) {
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{initEnvelope("a")}}
+	err := ServeStream(&panickingSetter{}, stream)
+	require.ErrorIs(t, err, ErrHandlerPanic)
+	var perr *PanicError
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, "OnInit", perr.Method)
+	require.Equal(t, "a", perr.ComponentID)
+}
+
+// panickingCloser panics in OnShutdown.
+type panickingCloser struct{ recordingHandler }
+
+func (h *panickingCloser) OnShutdown(string) { panic("double close") }
+
+func TestServeStreamMux_ShutdownPanicsDoNotStopOtherComponents(t *testing.T) {
+	b := &recordingHandler{}
+	handlers := []StreamHandler{&panickingCloser{}, &panickingCloser{}, b}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"), initEnvelope("c"), initEnvelope("b"),
+		shutdownEnvelope("done with a", "a"),
+		shutdownEnvelope("bye", ""),
+	}}
+	require.NoError(t, ServeStreamMux(func() StreamHandler {
+		h := handlers[0]
+		handlers = handlers[1:]
+		return h
+	}, stream))
+	require.Equal(t, []string{"bye"}, b.shutdown)
+}
+
+// panickingControlPlugin panics in its control function.
+type panickingControlPlugin struct{ controlPlugin }
+
+func (p *panickingControlPlugin) InvokeControlFunction(ControlFunctionRequest) (ControlFunctionResult, error) {
+	panic("bad speed")
+}
+
+func TestGRPCAdapter_ControlFunctionPanicsBecomeInternalErrors(t *testing.T) {
+	_, err := NewGRPCAdapter(&panickingControlPlugin{}).InvokeControlFunction(context.Background(), &simsdkrpc.ControlFunctionRequest{
+		FunctionId:  "set-speed",
+		ComponentId: "loco-1",
+		Parameters:  map[string]string{"speed": "1"},
+	})
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Contains(t, status.Convert(err).Message(), "bad speed")
+}
+
+// panickingStatusPlugin panics when asked about its components.
+type panickingStatusPlugin struct{ dummyPlugin }
+
+func (p *panickingStatusPlugin) ListComponents() []ComponentStatus { panic("list") }
+func (p *panickingStatusPlugin) GetComponentStatus(string) (ComponentStatus, error) {
+	panic("status")
+}
+
+func TestGRPCAdapter_StatusPanicsBecomeInternalErrors(t *testing.T) {
+	adapter := NewGRPCAdapter(&panickingStatusPlugin{})
+
+	_, err := adapter.ListComponents(context.Background(), &simsdkrpc.ListComponentsRequest{})
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Contains(t, status.Convert(err).Message(), "list")
+
+	_, err = adapter.GetComponentStatus(context.Background(), wrapperspb.String("c1"))
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Contains(t, status.Convert(err).Message(), "status")
+}
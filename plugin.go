@@ -2,9 +2,7 @@
 package simsdk
 
 import (
-	"fmt"
-	"io"
-	"log"
+	"time"
 
 	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
 )
@@ -61,6 +59,8 @@ type SimMessage struct {
 	ComponentID string            `json:"componentId"`
 	Payload     []byte            `json:"payload"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
+	SimTime     time.Time         `json:"simTime,omitempty"`  // Simulation time the message refers to
+	WallTime    time.Time         `json:"wallTime,omitempty"` // Wall-clock time the message was sent
 }
 
 type StreamSender interface {
@@ -89,99 +89,3 @@ type RegisterRequest struct {
 }
 
 type RegisteredPlugins map[string]RegisterRequest
-
-type grpcStreamSender struct {
-	stream      simsdkrpc.PluginService_MessageStreamServer
-	componentID string
-}
-
-func (s *grpcStreamSender) Send(msg *SimMessage) error {
-	return s.stream.Send(&simsdkrpc.PluginMessageEnvelope{
-		Content: &simsdkrpc.PluginMessageEnvelope_SimMessage{
-			SimMessage: ToProtoSimMessage(msg),
-		},
-	})
-}
-
-func (s *grpcStreamSender) ComponentID() string {
-	return s.componentID
-}
-func ServeStream(handler StreamHandler, stream simsdkrpc.PluginService_MessageStreamServer) error {
-	log.Printf("ServeStream handler concrete type: %T", handler)
-
-	for {
-		in, err := stream.Recv()
-		if err == io.EOF {
-			log.Println("Stream closed by client")
-			return nil
-		}
-		if err != nil {
-			return fmt.Errorf("ServeStream: failed to receive from stream: %w", err)
-		}
-
-		switch msg := in.Content.(type) {
-		case *simsdkrpc.PluginMessageEnvelope_Init:
-			log.Printf("Received Init message")
-
-			// Inject stream sender into handler if supported
-			if setter, ok := handler.(StreamSenderSetter); ok {
-				log.Printf("ServeStream: calling SetStreamSender for %s", msg.Init.ComponentId)
-				setter.SetStreamSender(&grpcStreamSender{
-					stream:      stream,
-					componentID: msg.Init.ComponentId,
-				})
-				log.Println("SetStreamSender successfully installed on handler")
-			} else {
-				log.Println("Handler does not implement StreamSenderSetter (stream sender not set)")
-			}
-
-			if err := handler.OnInit(msg.Init); err != nil {
-				return fmt.Errorf("ServeStream: OnInit failed: %w", err)
-			}
-
-		case *simsdkrpc.PluginMessageEnvelope_SimMessage:
-			log.Printf("Received SimMessage: %s", msg.SimMessage.MessageId)
-			sdkMsg := FromProtoSimMessage(msg.SimMessage)
-			responses, err := handler.OnSimMessage(sdkMsg)
-			if err != nil {
-				log.Printf("OnSimMessage failed: %v\n", err)
-				_ = stream.Send(&simsdkrpc.PluginMessageEnvelope{
-					Content: &simsdkrpc.PluginMessageEnvelope_Nak{
-						Nak: &simsdkrpc.PluginNak{
-							MessageId:    msg.SimMessage.MessageId,
-							ErrorMessage: err.Error(),
-						},
-					},
-				})
-				continue
-			}
-
-			for _, resp := range responses {
-				log.Printf("Sending response message: %s", resp.MessageID)
-				if err := stream.Send(&simsdkrpc.PluginMessageEnvelope{
-					Content: &simsdkrpc.PluginMessageEnvelope_SimMessage{
-						SimMessage: ToProtoSimMessage(resp),
-					},
-				}); err != nil {
-					return fmt.Errorf("ServeStream: failed to send SimMessage: %w", err)
-				}
-			}
-
-			_ = stream.Send(&simsdkrpc.PluginMessageEnvelope{
-				Content: &simsdkrpc.PluginMessageEnvelope_Ack{
-					Ack: &simsdkrpc.PluginAck{
-						MessageId: msg.SimMessage.MessageId,
-					},
-				},
-			})
-
-		case *simsdkrpc.PluginMessageEnvelope_Shutdown:
-			log.Println("Received Shutdown message")
-			handler.OnShutdown(msg.Shutdown.Reason)
-			return nil
-
-		default:
-			log.Printf("Unknown message type in PluginMessageEnvelope: %T\n", msg)
-		}
-	}
-}
@@ -1,6 +1,7 @@
 package simsdk
 
 import (
+	"context"
 	"io"
 	"testing"
 
@@ -119,6 +120,10 @@ func (m *mockStream) Recv() (*simsdkrpc.PluginMessageEnvelope, error) {
 	return msg, nil
 }
 
+func (m *mockStream) Context() context.Context {
+	return context.Background()
+}
+
 func (m *mockStream) Send(resp *simsdkrpc.PluginMessageEnvelope) error {
 	m.sent = append(m.sent, resp)
 	return nil
@@ -0,0 +1,294 @@
+package simsdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// recordingMagic starts every recording file.
+const recordingMagic = "SIMREC\x00\x01"
+
+// StreamMethod is the Record.Method of MessageStream envelopes.
+const StreamMethod = "MessageStream"
+
+// Direction tells whether a recorded message was received or sent by the plugin.
+type Direction byte
+
+const (
+	Inbound  Direction = 1 // Received from the core
+	Outbound Direction = 2 // Sent to the core
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Inbound:
+		return "in"
+	case Outbound:
+		return "out"
+	default:
+		return fmt.Sprintf("Direction(%d)", byte(d))
+	}
+}
+
+// Record is one message in a recording. Unary calls are recorded as an
+// inbound request followed by an outbound response (or error status) with
+// Stream 0. Each recorded MessageStream gets its own Stream number.
+type Record struct {
+	Time      time.Time
+	Direction Direction
+	Stream    uint64
+	Method    string // PluginService method name, e.g. "HandleMessage" or StreamMethod
+	IsError   bool   // Payload is a google.rpc.Status
+	Payload   []byte // Marshaled request, response or envelope
+}
+
+// Recorder appends MessageStream envelopes and unary calls to a compact,
+// length-prefixed binary log. It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	w       *bufio.Writer
+	closer  io.Closer
+	err     error
+	streams atomic.Uint64
+}
+
+// NewRecorder writes a recording to w.
+func NewRecorder(w io.Writer) (*Recorder, error) {
+	r := &Recorder{w: bufio.NewWriter(w)}
+	if _, err := r.w.WriteString(recordingMagic); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// CreateRecording creates (or truncates) a recording file at path.
+func CreateRecording(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewRecorder(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r.closer = f
+	return r, nil
+}
+
+// Record appends one message. Errors are sticky and returned by Close.
+func (r *Recorder) Record(rec Record) error {
+	var frame []byte
+	frame = append(frame, byte(rec.Direction))
+	var flags byte
+	if rec.IsError {
+		flags |= 1
+	}
+	frame = append(frame, flags)
+	frame = binary.AppendUvarint(frame, rec.Stream)
+	frame = binary.AppendVarint(frame, rec.Time.UnixNano())
+	frame = binary.AppendUvarint(frame, uint64(len(rec.Method)))
+	frame = append(frame, rec.Method...)
+	frame = append(frame, rec.Payload...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	var size [binary.MaxVarintLen64]byte
+	if _, err := r.w.Write(size[:binary.PutUvarint(size[:], uint64(len(frame)))]); err != nil {
+		r.err = err
+		return err
+	}
+	if _, err := r.w.Write(frame); err != nil {
+		r.err = err
+	}
+	return r.err
+}
+
+func (r *Recorder) recordMessage(dir Direction, stream uint64, method string, msg proto.Message) {
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return
+	}
+	_ = r.Record(Record{Time: time.Now(), Direction: dir, Stream: stream, Method: method, Payload: payload})
+}
+
+func (r *Recorder) recordError(stream uint64, method string, err error) {
+	payload, mErr := proto.Marshal(status.Convert(err).Proto())
+	if mErr != nil {
+		return
+	}
+	_ = r.Record(Record{Time: time.Now(), Direction: Outbound, Stream: stream, Method: method, IsError: true, Payload: payload})
+}
+
+// Flush writes buffered records to the underlying writer.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = r.w.Flush()
+	}
+	return r.err
+}
+
+// Close flushes the recording and closes the file opened by CreateRecording.
+func (r *Recorder) Close() error {
+	err := r.Flush()
+	if r.closer != nil {
+		if cErr := r.closer.Close(); err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+// WithRecorder records every envelope received and sent on the stream.
+func WithRecorder(rec *Recorder) StreamOption {
+	return func(o *streamOptions) { o.recorder = rec }
+}
+
+// WrapStream returns a stream that records its traffic under a new stream number.
+func (r *Recorder) WrapStream(stream simsdkrpc.PluginService_MessageStreamServer) simsdkrpc.PluginService_MessageStreamServer {
+	return &recordingStream{PluginService_MessageStreamServer: stream, rec: r, id: r.streams.Add(1)}
+}
+
+type recordingStream struct {
+	simsdkrpc.PluginService_MessageStreamServer
+	rec *Recorder
+	id  uint64
+}
+
+func (s *recordingStream) Recv() (*simsdkrpc.PluginMessageEnvelope, error) {
+	env, err := s.PluginService_MessageStreamServer.Recv()
+	if err == nil {
+		s.rec.recordMessage(Inbound, s.id, StreamMethod, env)
+	}
+	return env, err
+}
+
+func (s *recordingStream) Send(env *simsdkrpc.PluginMessageEnvelope) error {
+	s.rec.recordMessage(Outbound, s.id, StreamMethod, env)
+	return s.PluginService_MessageStreamServer.Send(env)
+}
+
+// UnaryServerInterceptor records PluginService unary calls and their results.
+// Install it on the gRPC server that serves the adapter.
+func (r *Recorder) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	prefix := "/" + simsdkrpc.PluginService_ServiceDesc.ServiceName + "/"
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+		method := path.Base(info.FullMethod)
+		if m, ok := req.(proto.Message); ok {
+			r.recordMessage(Inbound, 0, method, m)
+		}
+		resp, err := handler(ctx, req)
+		if err != nil {
+			r.recordError(0, method, err)
+		} else if m, ok := resp.(proto.Message); ok {
+			r.recordMessage(Outbound, 0, method, m)
+		}
+		return resp, err
+	}
+}
+
+// RecordingReader reads a recording written by a Recorder.
+type RecordingReader struct {
+	r      *bufio.Reader
+	closer io.Closer
+}
+
+// NewRecordingReader reads a recording from r.
+func NewRecordingReader(r io.Reader) (*RecordingReader, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(recordingMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != recordingMagic {
+		return nil, NewError(ErrInvalidArgument, "", "not a simsdk recording")
+	}
+	return &RecordingReader{r: br}, nil
+}
+
+// OpenRecording opens a recording file.
+func OpenRecording(path string) (*RecordingReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	rd, err := NewRecordingReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	rd.closer = f
+	return rd, nil
+}
+
+// Next returns the next record, or io.EOF at the end of the recording.
+func (rd *RecordingReader) Next() (Record, error) {
+	size, err := binary.ReadUvarint(rd.r)
+	if err != nil {
+		return Record{}, err
+	}
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(rd.r, frame); err != nil {
+		return Record{}, truncated(err)
+	}
+	if len(frame) < 2 {
+		return Record{}, truncated(nil)
+	}
+	rec := Record{Direction: Direction(frame[0]), IsError: frame[1]&1 != 0}
+	buf := frame[2:]
+
+	stream, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return Record{}, truncated(nil)
+	}
+	rec.Stream, buf = stream, buf[n:]
+	nanos, n := binary.Varint(buf)
+	if n <= 0 {
+		return Record{}, truncated(nil)
+	}
+	rec.Time, buf = time.Unix(0, nanos), buf[n:]
+	mlen, n := binary.Uvarint(buf)
+	if n <= 0 || uint64(len(buf)-n) < mlen {
+		return Record{}, truncated(nil)
+	}
+	buf = buf[n:]
+	rec.Method, rec.Payload = string(buf[:mlen]), buf[mlen:]
+	return rec, nil
+}
+
+// Close closes the file opened by OpenRecording.
+func (rd *RecordingReader) Close() error {
+	if rd.closer != nil {
+		return rd.closer.Close()
+	}
+	return nil
+}
+
+func truncated(err error) error {
+	if err == nil || errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	e := WrapError(ErrInvalidArgument, "", err)
+	e.Message = "truncated recording"
+	return e
+}
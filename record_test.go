@@ -0,0 +1,160 @@
+package simsdk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRecorder_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf)
+	require.NoError(t, err)
+
+	at := time.Unix(0, 1234567890)
+	require.NoError(t, rec.Record(Record{Time: at, Direction: Inbound, Stream: 3, Method: StreamMethod, Payload: []byte("abc")}))
+	require.NoError(t, rec.Record(Record{Time: at, Direction: Outbound, Method: "HandleMessage", IsError: true}))
+	require.NoError(t, rec.Close())
+
+	rd, err := NewRecordingReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	first, err := rd.Next()
+	require.NoError(t, err)
+	require.Equal(t, Record{Time: at, Direction: Inbound, Stream: 3, Method: StreamMethod, Payload: []byte("abc")}, first)
+	second, err := rd.Next()
+	require.NoError(t, err)
+	require.True(t, second.IsError)
+	require.Equal(t, "out", second.Direction.String())
+	_, err = rd.Next()
+	require.ErrorIs(t, err, io.EOF)
+
+	truncatedRd, err := NewRecordingReader(bytes.NewReader(buf.Bytes()[:buf.Len()-2]))
+	require.NoError(t, err)
+	_, _ = truncatedRd.Next()
+	_, err = truncatedRd.Next()
+	require.ErrorIs(t, err, ErrInvalidArgument)
+
+	_, err = NewRecordingReader(bytes.NewReader([]byte("nope")))
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+// changedPlugin replies differently from dummyPlugin, like a regression would.
+type changedPlugin struct{ dummyPlugin }
+
+func (c *changedPlugin) HandleMessage(msg SimMessage) ([]SimMessage, error) {
+	return []SimMessage{{MessageType: "OtherReply", MessageID: "Reply1", ComponentID: "CompReply"}}, nil
+}
+
+// recordSession drives a recorded gRPC session against p.
+func recordSession(t *testing.T, path string, p PluginWithHandlers) {
+	t.Helper()
+	rec, err := CreateRecording(path)
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(rec.UnaryServerInterceptor()))
+	simsdkrpc.RegisterPluginServiceServer(srv, NewGRPCAdapter(p, WithStreamOptions(WithRecorder(rec))))
+	go srv.Serve(lis)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	client := simsdkrpc.NewPluginServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = client.GetManifest(ctx, &simsdkrpc.ManifestRequest{})
+	require.NoError(t, err)
+	_, err = client.HandleMessage(ctx, &simsdkrpc.SimMessage{MessageId: "1", ComponentId: "CompX"})
+	require.NoError(t, err)
+	_, err = client.GetComponentStatus(ctx, wrapperspb.String("ghost"))
+	require.Error(t, err)
+
+	stream, err := client.MessageStream(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(initEnvelope("s1")))
+	require.NoError(t, stream.Send(simMessageEnvelope("m1", "s1")))
+	require.NoError(t, stream.Send(shutdownEnvelope("done", "")))
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+
+	conn.Close()
+	srv.GracefulStop()
+	require.NoError(t, rec.Close())
+}
+
+func TestReplay_ReproducesRecording(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.simrec")
+	recordSession(t, path, &dummyPlugin{})
+
+	rd, err := OpenRecording(path)
+	require.NoError(t, err)
+	defer rd.Close()
+	report, err := Replay(context.Background(), &dummyPlugin{}, rd)
+	require.NoError(t, err)
+	require.True(t, report.OK(), "%v", report.Mismatches)
+	require.Equal(t, 3, report.Calls)
+	require.Equal(t, 3, report.Envelopes)
+}
+
+func TestReplay_ReportsRegressions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.simrec")
+	recordSession(t, path, &dummyPlugin{})
+
+	rd, err := OpenRecording(path)
+	require.NoError(t, err)
+	defer rd.Close()
+	report, err := Replay(context.Background(), &changedPlugin{}, rd)
+	require.NoError(t, err)
+	require.Len(t, report.Mismatches, 1)
+	require.Equal(t, "HandleMessage", report.Mismatches[0].Method)
+	require.Contains(t, report.Mismatches[0].String(), "OtherReply")
+}
+
+// idlessPlugin leaves MessageID empty on everything it sends, so the SDK
+// generates the IDs.
+type idlessPlugin struct{ dummyPlugin }
+
+func (p *idlessPlugin) HandleMessage(msg SimMessage) ([]SimMessage, error) {
+	return []SimMessage{{MessageType: "Reply", ComponentID: msg.ComponentID}}, nil
+}
+
+func (p *idlessPlugin) GetStreamHandler() StreamHandler { return &replyingHandler{} }
+
+func TestReplay_GeneratedMessageIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.simrec")
+	recordSession(t, path, &idlessPlugin{})
+
+	rd, err := OpenRecording(path)
+	require.NoError(t, err)
+	defer rd.Close()
+	report, err := Replay(context.Background(), &idlessPlugin{}, rd)
+	require.NoError(t, err)
+	require.True(t, report.OK(), "%v", report.Mismatches)
+}
+
+func TestComparableEnvelopes_KeepLinksBetweenGeneratedIDs(t *testing.T) {
+	reply := func(id, causation string) proto.Message {
+		return &simsdkrpc.PluginMessageEnvelope{Content: &simsdkrpc.PluginMessageEnvelope_SimMessage{SimMessage: &simsdkrpc.SimMessage{
+			MessageId: id, Metadata: map[string]string{CausationIDKey: causation},
+		}}}
+	}
+	a, b := NewMessageID(), NewMessageID()
+	c, d := NewMessageID(), NewMessageID()
+	require.Empty(t, diffEnvelopes(1, []proto.Message{reply(a, "m1"), reply(b, a)}, []proto.Message{reply(c, "m1"), reply(d, c)}))
+	require.Len(t, diffEnvelopes(1, []proto.Message{reply(a, "m1"), reply(b, a)}, []proto.Message{reply(c, "m1"), reply(d, "m1")}), 1)
+}
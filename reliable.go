@@ -0,0 +1,274 @@
+package simsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+)
+
+// DeliveryStatus is the final outcome of a message sent in reliable mode.
+type DeliveryStatus string
+
+const (
+	DeliveryAcked    DeliveryStatus = "acked"     // The peer acknowledged the message
+	DeliveryNaked    DeliveryStatus = "naked"     // The peer rejected every attempt
+	DeliveryTimedOut DeliveryStatus = "timed_out" // No ack/nak arrived for the last attempt
+	DeliveryCanceled DeliveryStatus = "canceled"  // The stream closed before an outcome was known
+)
+
+// DeliveryOutcome reports what happened to a message sent in reliable mode.
+type DeliveryOutcome struct {
+	MessageID   string
+	ComponentID string
+	Sequence    uint64
+	Status      DeliveryStatus
+	Attempts    int
+	Err         error // Last nak reason or timeout; nil when acked
+}
+
+// ReliableOptions configures reliable delivery on a MessageStream.
+type ReliableOptions struct {
+	Window         int           // Max unacknowledged messages in flight (default 64)
+	AckTimeout     time.Duration // Time to wait for an ack before retrying (default 5s)
+	MaxRetries     int           // Redeliveries after the first attempt (default 3)
+	InitialBackoff time.Duration // Delay before the first redelivery (default 100ms)
+	MaxBackoff     time.Duration // Cap for the exponential backoff (default 5s)
+
+	// OnOutcome, if set, is called once per message with its final outcome.
+	// It runs on SDK goroutines and must not block.
+	OnOutcome func(DeliveryOutcome)
+}
+
+func (o ReliableOptions) withDefaults() ReliableOptions {
+	if o.Window <= 0 {
+		o.Window = 64
+	}
+	if o.AckTimeout <= 0 {
+		o.AckTimeout = 5 * time.Second
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	} else if o.MaxRetries == 0 {
+		o.MaxRetries = 3
+	}
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = 100 * time.Millisecond
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = 5 * time.Second
+	}
+	return o
+}
+
+// backoff returns the delay before the given redelivery attempt (1-based).
+func (o ReliableOptions) backoff(retry int) time.Duration {
+	d := o.InitialBackoff
+	for i := 1; i < retry && d < o.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > o.MaxBackoff {
+		d = o.MaxBackoff
+	}
+	return d
+}
+
+// WithReliableDelivery enables sequence-numbered, acknowledged delivery for
+// messages plugins send through their StreamSender. MaxRetries of -1 disables
+// redelivery.
+func WithReliableDelivery(opts ReliableOptions) StreamOption {
+	return func(o *streamOptions) {
+		r := opts.withDefaults()
+		o.reliable = &r
+	}
+}
+
+// ReliableStreamSender is implemented by stream senders when reliable delivery
+// is enabled. SendReliable returns once the message is queued; its final
+// outcome is delivered on the returned channel.
+type ReliableStreamSender interface {
+	StreamSender
+	SendReliable(ctx context.Context, msg *SimMessage) (<-chan DeliveryOutcome, error)
+}
+
+// ErrReliableDisabled is returned by SendReliable when the stream was not
+// served with WithReliableDelivery.
+var ErrReliableDisabled = fmt.Errorf("%w: reliable delivery not enabled on this stream", ErrFailedPrecondition)
+
+type inflightMessage struct {
+	env      *simsdkrpc.PluginMessageEnvelope
+	attempts int
+	timer    *time.Timer
+	result   chan DeliveryOutcome
+}
+
+// reliableTracker assigns sequence numbers, limits the in-flight window and
+// redelivers nak'd or timed-out messages.
+type reliableTracker struct {
+	opts   ReliableOptions
+	writer *streamWriter
+	slots  chan struct{}
+	seq    atomic.Uint64
+
+	mu       sync.Mutex
+	inflight map[uint64]*inflightMessage
+	closed   bool
+}
+
+func newReliableTracker(writer *streamWriter, opts ReliableOptions) *reliableTracker {
+	return &reliableTracker{
+		opts:     opts,
+		writer:   writer,
+		slots:    make(chan struct{}, opts.Window),
+		inflight: make(map[uint64]*inflightMessage),
+	}
+}
+
+// send waits for a window slot, then queues the message and starts its ack timer.
+func (r *reliableTracker) send(ctx context.Context, env *simsdkrpc.PluginMessageEnvelope) (<-chan DeliveryOutcome, error) {
+	select {
+	case r.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-r.writer.done:
+		return nil, ErrStreamClosed
+	}
+
+	seq := r.seq.Add(1)
+	env.Sequence = seq
+	m := &inflightMessage{env: env, attempts: 1, result: make(chan DeliveryOutcome, 1)}
+
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		<-r.slots
+		return nil, ErrStreamClosed
+	}
+	r.inflight[seq] = m
+	m.timer = time.AfterFunc(r.opts.AckTimeout, func() { r.retry(seq, errors.New("ack timeout"), DeliveryTimedOut) })
+	r.mu.Unlock()
+
+	if err := r.writer.enqueue(ctx, env, true); err != nil {
+		r.finish(seq, DeliveryCanceled, err)
+		return nil, err
+	}
+	return m.result, nil
+}
+
+// ack completes a message. Acks without a sequence are matched by message ID.
+func (r *reliableTracker) ack(a *simsdkrpc.PluginAck) {
+	if seq := r.lookup(a.GetSequence(), a.GetMessageId()); seq != 0 {
+		r.finish(seq, DeliveryAcked, nil)
+	}
+}
+
+// nak schedules a redelivery or fails the message once retries are exhausted.
+func (r *reliableTracker) nak(n *simsdkrpc.PluginNak) {
+	if seq := r.lookup(n.GetSequence(), n.GetMessageId()); seq != 0 {
+		r.retry(seq, errors.New(n.GetErrorMessage()), DeliveryNaked)
+	}
+}
+
+func (r *reliableTracker) lookup(seq uint64, messageID string) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if seq != 0 {
+		if _, ok := r.inflight[seq]; ok {
+			return seq
+		}
+		return 0
+	}
+	if messageID == "" {
+		return 0
+	}
+	var found uint64
+	for s, m := range r.inflight {
+		if m.env.GetSimMessage().GetMessageId() == messageID && (found == 0 || s < found) {
+			found = s
+		}
+	}
+	return found
+}
+
+func (r *reliableTracker) retry(seq uint64, cause error, failure DeliveryStatus) {
+	r.mu.Lock()
+	m, ok := r.inflight[seq]
+	if !ok || r.closed {
+		r.mu.Unlock()
+		return
+	}
+	m.timer.Stop()
+	if m.attempts > r.opts.MaxRetries {
+		r.mu.Unlock()
+		r.finish(seq, failure, cause)
+		return
+	}
+	delay := r.opts.backoff(m.attempts)
+	m.attempts++
+	m.timer = time.AfterFunc(delay+r.opts.AckTimeout, func() { r.retry(seq, errors.New("ack timeout"), DeliveryTimedOut) })
+	r.mu.Unlock()
+
+	time.AfterFunc(delay, func() {
+		r.mu.Lock()
+		_, live := r.inflight[seq]
+		r.mu.Unlock()
+		if live {
+			// A redelivery does not take a flow-control credit: the credit
+			// taken by the first send covers every attempt.
+			_ = r.writer.enqueue(context.Background(), m.env, true)
+		}
+	})
+}
+
+func (r *reliableTracker) finish(seq uint64, status DeliveryStatus, err error) {
+	r.mu.Lock()
+	m, ok := r.inflight[seq]
+	if ok {
+		delete(r.inflight, seq)
+		m.timer.Stop()
+	}
+	r.mu.Unlock()
+	if !ok {
+		return
+	}
+	<-r.slots
+
+	msg := m.env.GetSimMessage()
+	out := DeliveryOutcome{
+		MessageID:   msg.GetMessageId(),
+		ComponentID: msg.GetComponentId(),
+		Sequence:    seq,
+		Status:      status,
+		Attempts:    m.attempts,
+		Err:         err,
+	}
+	m.result <- out
+	if r.opts.OnOutcome != nil {
+		r.opts.OnOutcome(out)
+	}
+}
+
+// inFlight returns the number of unacknowledged messages.
+func (r *reliableTracker) inFlight() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.inflight)
+}
+
+// close cancels every message still in flight.
+func (r *reliableTracker) close() {
+	r.mu.Lock()
+	r.closed = true
+	seqs := make([]uint64, 0, len(r.inflight))
+	for seq := range r.inflight {
+		seqs = append(seqs, seq)
+	}
+	r.mu.Unlock()
+	for _, seq := range seqs {
+		r.finish(seq, DeliveryCanceled, ErrStreamClosed)
+	}
+}
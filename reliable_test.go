@@ -0,0 +1,173 @@
+package simsdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTracker(t *testing.T, opts ReliableOptions) (*reliableTracker, *gatedSender) {
+	t.Helper()
+	out := &gatedSender{}
+	w := newStreamWriter(out, 16, OverflowBlock)
+	r := newReliableTracker(w, opts.withDefaults())
+	t.Cleanup(func() {
+		r.close()
+		_ = w.close(context.Background())
+	})
+	return r, out
+}
+
+func (g *gatedSender) count() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.sent)
+}
+
+func awaitOutcome(t *testing.T, ch <-chan DeliveryOutcome) DeliveryOutcome {
+	t.Helper()
+	select {
+	case out := <-ch:
+		return out
+	case <-time.After(2 * time.Second):
+		t.Fatal("no delivery outcome")
+		return DeliveryOutcome{}
+	}
+}
+
+func TestReliableTracker_AckCompletesMessage(t *testing.T) {
+	r, out := newTestTracker(t, ReliableOptions{AckTimeout: time.Minute})
+
+	ch, err := r.send(context.Background(), simEnvelope("m1"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return out.count() == 1 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, uint64(1), out.sent[0].Sequence)
+
+	r.ack(&simsdkrpc.PluginAck{MessageId: "m1", Sequence: 1})
+	got := awaitOutcome(t, ch)
+	require.Equal(t, DeliveryAcked, got.Status)
+	require.Equal(t, 1, got.Attempts)
+	require.NoError(t, got.Err)
+	require.Zero(t, r.inFlight())
+}
+
+func TestReliableTracker_AckByMessageID(t *testing.T) {
+	r, _ := newTestTracker(t, ReliableOptions{AckTimeout: time.Minute})
+
+	ch, err := r.send(context.Background(), simEnvelope("m1"))
+	require.NoError(t, err)
+	r.ack(&simsdkrpc.PluginAck{MessageId: "m1"})
+	require.Equal(t, DeliveryAcked, awaitOutcome(t, ch).Status)
+}
+
+func TestReliableTracker_NakRedeliversThenAck(t *testing.T) {
+	r, out := newTestTracker(t, ReliableOptions{AckTimeout: time.Minute, InitialBackoff: time.Millisecond})
+
+	ch, err := r.send(context.Background(), simEnvelope("m1"))
+	require.NoError(t, err)
+	r.nak(&simsdkrpc.PluginNak{MessageId: "m1", Sequence: 1, ErrorMessage: "busy"})
+	require.Eventually(t, func() bool { return out.count() == 2 }, time.Second, 5*time.Millisecond)
+
+	r.ack(&simsdkrpc.PluginAck{Sequence: 1})
+	got := awaitOutcome(t, ch)
+	require.Equal(t, DeliveryAcked, got.Status)
+	require.Equal(t, 2, got.Attempts)
+}
+
+func TestReliableTracker_RedeliveryKeepsTheOriginalCredit(t *testing.T) {
+	r, out := newTestTracker(t, ReliableOptions{AckTimeout: time.Minute, InitialBackoff: time.Millisecond})
+	f := newFlowController(4)
+	f.grant(1)
+	s := &grpcStreamSender{writer: r.writer, reliable: r, flow: f}
+
+	ch, err := s.SendReliable(context.Background(), &SimMessage{MessageID: "m1"})
+	require.NoError(t, err)
+	r.nak(&simsdkrpc.PluginNak{MessageId: "m1", Sequence: 1, ErrorMessage: "busy"})
+	require.Eventually(t, func() bool { return out.count() == 2 }, time.Second, 5*time.Millisecond)
+
+	st := f.stats()
+	require.Zero(t, st.OutboundCredits, "the redelivery did not take a credit")
+	require.Equal(t, uint64(1), st.OutboundSent)
+
+	r.ack(&simsdkrpc.PluginAck{Sequence: 1})
+	require.Equal(t, DeliveryAcked, awaitOutcome(t, ch).Status)
+}
+
+func TestReliableTracker_NakExhaustsRetries(t *testing.T) {
+	r, _ := newTestTracker(t, ReliableOptions{AckTimeout: time.Minute, MaxRetries: -1})
+
+	ch, err := r.send(context.Background(), simEnvelope("m1"))
+	require.NoError(t, err)
+	r.nak(&simsdkrpc.PluginNak{Sequence: 1, ErrorMessage: "rejected"})
+
+	got := awaitOutcome(t, ch)
+	require.Equal(t, DeliveryNaked, got.Status)
+	require.EqualError(t, got.Err, "rejected")
+}
+
+func TestReliableTracker_TimeoutRetriesThenFails(t *testing.T) {
+	var outcomes []DeliveryOutcome
+	done := make(chan struct{})
+	r, out := newTestTracker(t, ReliableOptions{
+		AckTimeout:     10 * time.Millisecond,
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		OnOutcome: func(o DeliveryOutcome) {
+			outcomes = append(outcomes, o)
+			close(done)
+		},
+	})
+
+	ch, err := r.send(context.Background(), simEnvelope("m1"))
+	require.NoError(t, err)
+	got := awaitOutcome(t, ch)
+	require.Equal(t, DeliveryTimedOut, got.Status)
+	require.Equal(t, 3, got.Attempts)
+	require.Equal(t, 3, out.count())
+
+	<-done
+	require.Equal(t, []DeliveryOutcome{got}, outcomes)
+}
+
+func TestReliableTracker_WindowBlocksSends(t *testing.T) {
+	r, _ := newTestTracker(t, ReliableOptions{Window: 1, AckTimeout: time.Minute})
+
+	_, err := r.send(context.Background(), simEnvelope("m1"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = r.send(ctx, simEnvelope("m2"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	r.ack(&simsdkrpc.PluginAck{Sequence: 1})
+	_, err = r.send(context.Background(), simEnvelope("m3"))
+	require.NoError(t, err)
+}
+
+func TestReliableTracker_CloseCancelsInFlight(t *testing.T) {
+	r, _ := newTestTracker(t, ReliableOptions{AckTimeout: time.Minute})
+
+	ch, err := r.send(context.Background(), simEnvelope("m1"))
+	require.NoError(t, err)
+	r.close()
+
+	got := awaitOutcome(t, ch)
+	require.Equal(t, DeliveryCanceled, got.Status)
+	require.ErrorIs(t, got.Err, ErrStreamClosed)
+
+	_, err = r.send(context.Background(), simEnvelope("m2"))
+	require.ErrorIs(t, err, ErrStreamClosed)
+}
+
+func TestServeStream_SendReliableRequiresOption(t *testing.T) {
+	s := &grpcStreamSender{writer: newStreamWriter(&gatedSender{}, 1, OverflowBlock)}
+	defer s.writer.close(context.Background())
+
+	_, err := s.SendReliable(context.Background(), &SimMessage{MessageID: "m1"})
+	require.ErrorIs(t, err, ErrReliableDisabled)
+	require.ErrorIs(t, err, ErrFailedPrecondition)
+}
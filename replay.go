@@ -0,0 +1,325 @@
+package simsdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// ReplayMismatch is a difference between a recorded output and the output
+// produced during replay. Expected or Actual is nil when one side is missing.
+type ReplayMismatch struct {
+	Stream   uint64 // 0 for unary calls
+	Method   string
+	Index    int // Position among the stream's outbound envelopes or the method's calls
+	Expected proto.Message
+	Actual   proto.Message
+}
+
+func (m ReplayMismatch) String() string {
+	return fmt.Sprintf("%s (stream %d) #%d: expected %v, got %v", m.Method, m.Stream, m.Index, m.Expected, m.Actual)
+}
+
+// ReplayReport summarizes a replay.
+type ReplayReport struct {
+	Calls      int // Unary calls replayed
+	Envelopes  int // Inbound stream envelopes replayed
+	Mismatches []ReplayMismatch
+}
+
+// OK reports whether the replay reproduced every recorded output.
+func (r *ReplayReport) OK() bool {
+	return len(r.Mismatches) == 0
+}
+
+// Replay feeds a recording into p offline and diffs what p produces against
+// what was recorded. Unary calls go through the gRPC adapter; each recorded
+// MessageStream is replayed on its own stream served with opts. Heartbeats
+// and the sim/wall timestamps stamped on outbound SimMessages are ignored
+// because they depend on when the replay runs. Message IDs generated by
+// NewMessageID are compared by their order of first appearance, along with
+// the correlation and causation IDs that refer to them.
+func Replay(ctx context.Context, p PluginWithHandlers, rd *RecordingReader, opts ...StreamOption) (*ReplayReport, error) {
+	adapter := NewGRPCAdapter(p, WithStreamOptions(opts...))
+	report := &ReplayReport{}
+
+	pending := make(map[string][]proto.Message) // unary results waiting for their recorded response
+	calls := make(map[string]int)
+	streams := make(map[uint64]*replayStream)
+	expected := make(map[uint64][]proto.Message)
+
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if rec.Method == StreamMethod {
+			env := &simsdkrpc.PluginMessageEnvelope{}
+			if err := proto.Unmarshal(rec.Payload, env); err != nil {
+				return nil, err
+			}
+			if rec.Direction == Outbound {
+				expected[rec.Stream] = append(expected[rec.Stream], env)
+				continue
+			}
+			rs, ok := streams[rec.Stream]
+			if !ok {
+				rs = newReplayStream(ctx)
+				streams[rec.Stream] = rs
+				go rs.serve(adapter)
+			}
+			rs.in <- env
+			report.Envelopes++
+			continue
+		}
+
+		switch rec.Direction {
+		case Inbound:
+			resp := replayUnary(ctx, adapter, rec)
+			pending[rec.Method] = append(pending[rec.Method], resp)
+			report.Calls++
+		case Outbound:
+			want, err := decodeRecorded(rec)
+			if err != nil {
+				return nil, err
+			}
+			var got proto.Message
+			if q := pending[rec.Method]; len(q) > 0 {
+				got, pending[rec.Method] = q[0], q[1:]
+			}
+			if !proto.Equal(comparableResponse(want), comparableResponse(got)) {
+				report.Mismatches = append(report.Mismatches, ReplayMismatch{Method: rec.Method, Index: calls[rec.Method], Expected: want, Actual: got})
+			}
+			calls[rec.Method]++
+		}
+	}
+
+	ids := make([]uint64, 0, len(streams))
+	for id := range streams {
+		ids = append(ids, id)
+	}
+	for id := range expected {
+		if _, ok := streams[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		var actual []proto.Message
+		if rs, ok := streams[id]; ok {
+			actual = rs.finish()
+		}
+		report.Mismatches = append(report.Mismatches, diffEnvelopes(id, expected[id], actual)...)
+	}
+	return report, nil
+}
+
+// replayUnary invokes the adapter method named by rec with the recorded
+// request and returns its response, or its status when it fails.
+func replayUnary(ctx context.Context, adapter simsdkrpc.PluginServiceServer, rec Record) proto.Message {
+	for _, m := range simsdkrpc.PluginService_ServiceDesc.Methods {
+		if m.MethodName != rec.Method {
+			continue
+		}
+		dec := func(v any) error { return proto.Unmarshal(rec.Payload, v.(proto.Message)) }
+		resp, err := m.Handler(adapter, ctx, dec, nil)
+		if err != nil {
+			return status.Convert(err).Proto()
+		}
+		if msg, ok := resp.(proto.Message); ok {
+			return msg
+		}
+		return nil
+	}
+	return status.Newf(codes.Unimplemented, "unknown method %q", rec.Method).Proto()
+}
+
+// decodeRecorded decodes a recorded unary response or error status.
+func decodeRecorded(rec Record) (proto.Message, error) {
+	if rec.IsError {
+		st := &spb.Status{}
+		return st, proto.Unmarshal(rec.Payload, st)
+	}
+	mt, err := responseType(rec.Method)
+	if err != nil {
+		return nil, err
+	}
+	msg := mt.New().Interface()
+	return msg, proto.Unmarshal(rec.Payload, msg)
+}
+
+// responseType looks up the output message type of a PluginService method.
+func responseType(method string) (protoreflect.MessageType, error) {
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(simsdkrpc.PluginService_ServiceDesc.ServiceName))
+	if err != nil {
+		return nil, err
+	}
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", simsdkrpc.PluginService_ServiceDesc.ServiceName)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(method))
+	if md == nil {
+		return nil, NewError(ErrInvalidArgument, "", "unknown method %q in recording", method)
+	}
+	return protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+}
+
+// diffEnvelopes compares a stream's recorded and replayed outbound envelopes.
+func diffEnvelopes(stream uint64, expected, actual []proto.Message) []ReplayMismatch {
+	expected, actual = comparableEnvelopes(expected), comparableEnvelopes(actual)
+	var out []ReplayMismatch
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		var want, got proto.Message
+		if i < len(expected) {
+			want = expected[i]
+		}
+		if i < len(actual) {
+			got = actual[i]
+		}
+		if want == nil || got == nil || !proto.Equal(want, got) {
+			out = append(out, ReplayMismatch{Stream: stream, Method: StreamMethod, Index: i, Expected: want, Actual: got})
+		}
+	}
+	return out
+}
+
+// comparableEnvelopes drops heartbeats, clears timestamps that depend on
+// when the stream ran and normalizes generated message IDs.
+func comparableEnvelopes(envs []proto.Message) []proto.Message {
+	var out []proto.Message
+	ids := make(generatedIDs)
+	for _, m := range envs {
+		env := proto.Clone(m).(*simsdkrpc.PluginMessageEnvelope)
+		if env.GetHeartbeat() != nil {
+			continue
+		}
+		if sm := env.GetSimMessage(); sm != nil {
+			sm.SimTime, sm.WallTime = nil, nil
+			ids.normalize(sm)
+		}
+		if sm := env.GetChunk().GetHeader(); sm != nil {
+			sm.SimTime, sm.WallTime = nil, nil
+			ids.normalize(sm)
+		}
+		for _, sm := range env.GetBatch().GetMessages() {
+			sm.SimTime, sm.WallTime = nil, nil
+			ids.normalize(sm)
+		}
+		out = append(out, env)
+	}
+	return out
+}
+
+// comparableResponse normalizes the generated message IDs in the outbound
+// messages of a unary response.
+func comparableResponse(m proto.Message) proto.Message {
+	if m == nil {
+		return nil
+	}
+	m = proto.Clone(m)
+	ids := make(generatedIDs)
+	switch resp := m.(type) {
+	case *simsdkrpc.MessageResponse:
+		for _, sm := range resp.GetOutboundMessages() {
+			ids.normalize(sm)
+		}
+	case *simsdkrpc.MessageBatchResponse:
+		for _, r := range resp.GetResults() {
+			for _, sm := range r.GetOutboundMessages() {
+				ids.normalize(sm)
+			}
+		}
+	}
+	return m
+}
+
+// generatedIDs maps IDs in NewMessageID's format to their order of first
+// appearance, so IDs generated while recording and while replaying compare
+// equal when they are used in the same places.
+type generatedIDs map[string]string
+
+func (g generatedIDs) normalize(sm *simsdkrpc.SimMessage) {
+	sm.MessageId = g.id(sm.MessageId)
+	for _, key := range []string{CorrelationIDKey, CausationIDKey} {
+		if v, ok := sm.Metadata[key]; ok {
+			sm.Metadata[key] = g.id(v)
+		}
+	}
+}
+
+func (g generatedIDs) id(id string) string {
+	if !isMessageID(id) {
+		return id
+	}
+	if n, ok := g[id]; ok {
+		return n
+	}
+	n := fmt.Sprintf("generated-%d", len(g)+1)
+	g[id] = n
+	return n
+}
+
+// replayStream is an in-memory MessageStream fed with recorded envelopes.
+type replayStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	in   chan *simsdkrpc.PluginMessageEnvelope
+	done chan struct{}
+
+	mu  sync.Mutex
+	out []proto.Message
+}
+
+func newReplayStream(ctx context.Context) *replayStream {
+	return &replayStream{ctx: ctx, in: make(chan *simsdkrpc.PluginMessageEnvelope), done: make(chan struct{})}
+}
+
+func (s *replayStream) serve(adapter simsdkrpc.PluginServiceServer) {
+	defer close(s.done)
+	_ = adapter.MessageStream(s)
+	// keep accepting envelopes so the replay loop never blocks on a finished stream
+	for range s.in {
+	}
+}
+
+// finish ends the stream's input and returns what it sent.
+func (s *replayStream) finish() []proto.Message {
+	close(s.in)
+	<-s.done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.out
+}
+
+func (s *replayStream) Context() context.Context { return s.ctx }
+
+func (s *replayStream) Recv() (*simsdkrpc.PluginMessageEnvelope, error) {
+	env, ok := <-s.in
+	if !ok {
+		return nil, io.EOF
+	}
+	return env, nil
+}
+
+func (s *replayStream) Send(env *simsdkrpc.PluginMessageEnvelope) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.out = append(s.out, env)
+	return nil
+}
@@ -9,7 +9,9 @@ package simsdkrpc
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
 	reflect "reflect"
 	sync "sync"
@@ -93,6 +95,61 @@ func (FieldType) EnumDescriptor() ([]byte, []int) {
 	return file_plugin_proto_rawDescGZIP(), []int{0}
 }
 
+type ComponentState int32
+
+const (
+	ComponentState_COMPONENT_STATE_UNSPECIFIED ComponentState = 0
+	ComponentState_COMPONENT_STATE_CREATED     ComponentState = 1
+	ComponentState_COMPONENT_STATE_RUNNING     ComponentState = 2
+	ComponentState_COMPONENT_STATE_STOPPED     ComponentState = 3
+	ComponentState_COMPONENT_STATE_PAUSED      ComponentState = 4
+)
+
+// Enum value maps for ComponentState.
+var (
+	ComponentState_name = map[int32]string{
+		0: "COMPONENT_STATE_UNSPECIFIED",
+		1: "COMPONENT_STATE_CREATED",
+		2: "COMPONENT_STATE_RUNNING",
+		3: "COMPONENT_STATE_STOPPED",
+		4: "COMPONENT_STATE_PAUSED",
+	}
+	ComponentState_value = map[string]int32{
+		"COMPONENT_STATE_UNSPECIFIED": 0,
+		"COMPONENT_STATE_CREATED":     1,
+		"COMPONENT_STATE_RUNNING":     2,
+		"COMPONENT_STATE_STOPPED":     3,
+		"COMPONENT_STATE_PAUSED":      4,
+	}
+)
+
+func (x ComponentState) Enum() *ComponentState {
+	p := new(ComponentState)
+	*p = x
+	return p
+}
+
+func (x ComponentState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ComponentState) Descriptor() protoreflect.EnumDescriptor {
+	return file_plugin_proto_enumTypes[1].Descriptor()
+}
+
+func (ComponentState) Type() protoreflect.EnumType {
+	return &file_plugin_proto_enumTypes[1]
+}
+
+func (x ComponentState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ComponentState.Descriptor instead.
+func (ComponentState) EnumDescriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{1}
+}
+
 type ManifestRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -733,6 +790,84 @@ func (*CreateComponentResponse) Descriptor() ([]byte, []int) {
 	return file_plugin_proto_rawDescGZIP(), []int{9}
 }
 
+// ComponentSnapshot is a serialized component. state is opaque to the core;
+// version identifies its format so plugins can restore older snapshots.
+type ComponentSnapshot struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	ComponentId   string                  `protobuf:"bytes,1,opt,name=component_id,json=componentId,proto3" json:"component_id,omitempty"`
+	CreateRequest *CreateComponentRequest `protobuf:"bytes,2,opt,name=create_request,json=createRequest,proto3" json:"create_request,omitempty"`
+	Version       uint32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	State         []byte                  `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	TakenAt       *timestamppb.Timestamp  `protobuf:"bytes,5,opt,name=taken_at,json=takenAt,proto3" json:"taken_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComponentSnapshot) Reset() {
+	*x = ComponentSnapshot{}
+	mi := &file_plugin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComponentSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComponentSnapshot) ProtoMessage() {}
+
+func (x *ComponentSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComponentSnapshot.ProtoReflect.Descriptor instead.
+func (*ComponentSnapshot) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ComponentSnapshot) GetComponentId() string {
+	if x != nil {
+		return x.ComponentId
+	}
+	return ""
+}
+
+func (x *ComponentSnapshot) GetCreateRequest() *CreateComponentRequest {
+	if x != nil {
+		return x.CreateRequest
+	}
+	return nil
+}
+
+func (x *ComponentSnapshot) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ComponentSnapshot) GetState() []byte {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *ComponentSnapshot) GetTakenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TakenAt
+	}
+	return nil
+}
+
 type SimMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	MessageType   string                 `protobuf:"bytes,1,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
@@ -740,13 +875,15 @@ type SimMessage struct {
 	ComponentId   string                 `protobuf:"bytes,3,opt,name=component_id,json=componentId,proto3" json:"component_id,omitempty"`
 	Payload       []byte                 `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
 	Metadata      map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	SimTime       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=sim_time,json=simTime,proto3" json:"sim_time,omitempty"`    // simulation time the message refers to
+	WallTime      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=wall_time,json=wallTime,proto3" json:"wall_time,omitempty"` // wall-clock time the message was sent
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SimMessage) Reset() {
 	*x = SimMessage{}
-	mi := &file_plugin_proto_msgTypes[10]
+	mi := &file_plugin_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -758,7 +895,7 @@ func (x *SimMessage) String() string {
 func (*SimMessage) ProtoMessage() {}
 
 func (x *SimMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[10]
+	mi := &file_plugin_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -771,7 +908,7 @@ func (x *SimMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SimMessage.ProtoReflect.Descriptor instead.
 func (*SimMessage) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{10}
+	return file_plugin_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *SimMessage) GetMessageType() string {
@@ -809,6 +946,20 @@ func (x *SimMessage) GetMetadata() map[string]string {
 	return nil
 }
 
+func (x *SimMessage) GetSimTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SimTime
+	}
+	return nil
+}
+
+func (x *SimMessage) GetWallTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WallTime
+	}
+	return nil
+}
+
 type MessageResponse struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	OutboundMessages []*SimMessage          `protobuf:"bytes,1,rep,name=outbound_messages,json=outboundMessages,proto3" json:"outbound_messages,omitempty"`
@@ -818,7 +969,7 @@ type MessageResponse struct {
 
 func (x *MessageResponse) Reset() {
 	*x = MessageResponse{}
-	mi := &file_plugin_proto_msgTypes[11]
+	mi := &file_plugin_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -830,7 +981,7 @@ func (x *MessageResponse) String() string {
 func (*MessageResponse) ProtoMessage() {}
 
 func (x *MessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[11]
+	mi := &file_plugin_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -843,7 +994,7 @@ func (x *MessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageResponse.ProtoReflect.Descriptor instead.
 func (*MessageResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{11}
+	return file_plugin_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *MessageResponse) GetOutboundMessages() []*SimMessage {
@@ -853,35 +1004,30 @@ func (x *MessageResponse) GetOutboundMessages() []*SimMessage {
 	return nil
 }
 
-type PluginMessageEnvelope struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Content:
-	//
-	//	*PluginMessageEnvelope_SimMessage
-	//	*PluginMessageEnvelope_Ack
-	//	*PluginMessageEnvelope_Nak
-	//	*PluginMessageEnvelope_Init
-	//	*PluginMessageEnvelope_Shutdown
-	Content       isPluginMessageEnvelope_Content `protobuf_oneof:"content"`
+// MessageBatch carries several SimMessages in one call or envelope.
+type MessageBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*SimMessage          `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	Sequences     []uint64               `protobuf:"varint,2,rep,packed,name=sequences,proto3" json:"sequences,omitempty"` // optional: reliable-mode sequence of each message, echoed in its result
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PluginMessageEnvelope) Reset() {
-	*x = PluginMessageEnvelope{}
-	mi := &file_plugin_proto_msgTypes[12]
+func (x *MessageBatch) Reset() {
+	*x = MessageBatch{}
+	mi := &file_plugin_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PluginMessageEnvelope) String() string {
+func (x *MessageBatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PluginMessageEnvelope) ProtoMessage() {}
+func (*MessageBatch) ProtoMessage() {}
 
-func (x *PluginMessageEnvelope) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[12]
+func (x *MessageBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -892,119 +1038,139 @@ func (x *PluginMessageEnvelope) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PluginMessageEnvelope.ProtoReflect.Descriptor instead.
-func (*PluginMessageEnvelope) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use MessageBatch.ProtoReflect.Descriptor instead.
+func (*MessageBatch) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *PluginMessageEnvelope) GetContent() isPluginMessageEnvelope_Content {
+func (x *MessageBatch) GetMessages() []*SimMessage {
 	if x != nil {
-		return x.Content
+		return x.Messages
 	}
 	return nil
 }
 
-func (x *PluginMessageEnvelope) GetSimMessage() *SimMessage {
+func (x *MessageBatch) GetSequences() []uint64 {
 	if x != nil {
-		if x, ok := x.Content.(*PluginMessageEnvelope_SimMessage); ok {
-			return x.SimMessage
-		}
+		return x.Sequences
 	}
 	return nil
 }
 
-func (x *PluginMessageEnvelope) GetAck() *PluginAck {
+// MessageResult is the outcome of one message of a batch.
+type MessageResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*MessageResult_Ack
+	//	*MessageResult_Nak
+	Result           isMessageResult_Result `protobuf_oneof:"result"`
+	OutboundMessages []*SimMessage          `protobuf:"bytes,3,rep,name=outbound_messages,json=outboundMessages,proto3" json:"outbound_messages,omitempty"` // HandleMessages only; streams send responses as envelopes
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *MessageResult) Reset() {
+	*x = MessageResult{}
+	mi := &file_plugin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageResult) ProtoMessage() {}
+
+func (x *MessageResult) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[14]
 	if x != nil {
-		if x, ok := x.Content.(*PluginMessageEnvelope_Ack); ok {
-			return x.Ack
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *PluginMessageEnvelope) GetNak() *PluginNak {
+// Deprecated: Use MessageResult.ProtoReflect.Descriptor instead.
+func (*MessageResult) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MessageResult) GetResult() isMessageResult_Result {
 	if x != nil {
-		if x, ok := x.Content.(*PluginMessageEnvelope_Nak); ok {
-			return x.Nak
-		}
+		return x.Result
 	}
 	return nil
 }
 
-func (x *PluginMessageEnvelope) GetInit() *PluginInit {
+func (x *MessageResult) GetAck() *PluginAck {
 	if x != nil {
-		if x, ok := x.Content.(*PluginMessageEnvelope_Init); ok {
-			return x.Init
+		if x, ok := x.Result.(*MessageResult_Ack); ok {
+			return x.Ack
 		}
 	}
 	return nil
 }
 
-func (x *PluginMessageEnvelope) GetShutdown() *PluginShutdown {
+func (x *MessageResult) GetNak() *PluginNak {
 	if x != nil {
-		if x, ok := x.Content.(*PluginMessageEnvelope_Shutdown); ok {
-			return x.Shutdown
+		if x, ok := x.Result.(*MessageResult_Nak); ok {
+			return x.Nak
 		}
 	}
 	return nil
 }
 
-type isPluginMessageEnvelope_Content interface {
-	isPluginMessageEnvelope_Content()
-}
-
-type PluginMessageEnvelope_SimMessage struct {
-	SimMessage *SimMessage `protobuf:"bytes,1,opt,name=sim_message,json=simMessage,proto3,oneof"`
-}
-
-type PluginMessageEnvelope_Ack struct {
-	Ack *PluginAck `protobuf:"bytes,2,opt,name=ack,proto3,oneof"`
+func (x *MessageResult) GetOutboundMessages() []*SimMessage {
+	if x != nil {
+		return x.OutboundMessages
+	}
+	return nil
 }
 
-type PluginMessageEnvelope_Nak struct {
-	Nak *PluginNak `protobuf:"bytes,3,opt,name=nak,proto3,oneof"`
+type isMessageResult_Result interface {
+	isMessageResult_Result()
 }
 
-type PluginMessageEnvelope_Init struct {
-	Init *PluginInit `protobuf:"bytes,4,opt,name=init,proto3,oneof"`
+type MessageResult_Ack struct {
+	Ack *PluginAck `protobuf:"bytes,1,opt,name=ack,proto3,oneof"`
 }
 
-type PluginMessageEnvelope_Shutdown struct {
-	Shutdown *PluginShutdown `protobuf:"bytes,5,opt,name=shutdown,proto3,oneof"`
+type MessageResult_Nak struct {
+	Nak *PluginNak `protobuf:"bytes,2,opt,name=nak,proto3,oneof"`
 }
 
-func (*PluginMessageEnvelope_SimMessage) isPluginMessageEnvelope_Content() {}
-
-func (*PluginMessageEnvelope_Ack) isPluginMessageEnvelope_Content() {}
-
-func (*PluginMessageEnvelope_Nak) isPluginMessageEnvelope_Content() {}
-
-func (*PluginMessageEnvelope_Init) isPluginMessageEnvelope_Content() {}
+func (*MessageResult_Ack) isMessageResult_Result() {}
 
-func (*PluginMessageEnvelope_Shutdown) isPluginMessageEnvelope_Content() {}
+func (*MessageResult_Nak) isMessageResult_Result() {}
 
-type PluginInit struct {
+// MessageBatchResponse holds one result per message, in batch order.
+type MessageBatchResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ComponentId   string                 `protobuf:"bytes,1,opt,name=component_id,json=componentId,proto3" json:"component_id,omitempty"`
+	Results       []*MessageResult       `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PluginInit) Reset() {
-	*x = PluginInit{}
-	mi := &file_plugin_proto_msgTypes[13]
+func (x *MessageBatchResponse) Reset() {
+	*x = MessageBatchResponse{}
+	mi := &file_plugin_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PluginInit) String() string {
+func (x *MessageBatchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PluginInit) ProtoMessage() {}
+func (*MessageBatchResponse) ProtoMessage() {}
 
-func (x *PluginInit) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[13]
+func (x *MessageBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1015,40 +1181,43 @@ func (x *PluginInit) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PluginInit.ProtoReflect.Descriptor instead.
-func (*PluginInit) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use MessageBatchResponse.ProtoReflect.Descriptor instead.
+func (*MessageBatchResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *PluginInit) GetComponentId() string {
+func (x *MessageBatchResponse) GetResults() []*MessageResult {
 	if x != nil {
-		return x.ComponentId
+		return x.Results
 	}
-	return ""
+	return nil
 }
 
-type PluginShutdown struct {
+type ControlFunctionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	FunctionId    string                 `protobuf:"bytes,1,opt,name=function_id,json=functionId,proto3" json:"function_id,omitempty"`
+	ComponentId   string                 `protobuf:"bytes,2,opt,name=component_id,json=componentId,proto3" json:"component_id,omitempty"`
+	Parameters    map[string]string      `protobuf:"bytes,3,rep,name=parameters,proto3" json:"parameters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Payload       []byte                 `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PluginShutdown) Reset() {
-	*x = PluginShutdown{}
-	mi := &file_plugin_proto_msgTypes[14]
+func (x *ControlFunctionRequest) Reset() {
+	*x = ControlFunctionRequest{}
+	mi := &file_plugin_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PluginShutdown) String() string {
+func (x *ControlFunctionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PluginShutdown) ProtoMessage() {}
+func (*ControlFunctionRequest) ProtoMessage() {}
 
-func (x *PluginShutdown) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[14]
+func (x *ControlFunctionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1059,40 +1228,1248 @@ func (x *PluginShutdown) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PluginShutdown.ProtoReflect.Descriptor instead.
-func (*PluginShutdown) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ControlFunctionRequest.ProtoReflect.Descriptor instead.
+func (*ControlFunctionRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *PluginShutdown) GetReason() string {
+func (x *ControlFunctionRequest) GetFunctionId() string {
 	if x != nil {
-		return x.Reason
+		return x.FunctionId
 	}
 	return ""
 }
 
-type PluginAck struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ControlFunctionRequest) GetComponentId() string {
+	if x != nil {
+		return x.ComponentId
+	}
+	return ""
 }
 
-func (x *PluginAck) Reset() {
-	*x = PluginAck{}
-	mi := &file_plugin_proto_msgTypes[15]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ControlFunctionRequest) GetParameters() map[string]string {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
 }
 
-func (x *PluginAck) String() string {
+func (x *ControlFunctionRequest) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type ControlFunctionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        []byte                 `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ControlFunctionResponse) Reset() {
+	*x = ControlFunctionResponse{}
+	mi := &file_plugin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControlFunctionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControlFunctionResponse) ProtoMessage() {}
+
+func (x *ControlFunctionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControlFunctionResponse.ProtoReflect.Descriptor instead.
+func (*ControlFunctionResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ControlFunctionResponse) GetResult() []byte {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ControlFunctionResponse) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type ComponentStatus struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ComponentId      string                 `protobuf:"bytes,1,opt,name=component_id,json=componentId,proto3" json:"component_id,omitempty"`
+	ComponentType    string                 `protobuf:"bytes,2,opt,name=component_type,json=componentType,proto3" json:"component_type,omitempty"`
+	State            ComponentState         `protobuf:"varint,3,opt,name=state,proto3,enum=simsdkrpc.ComponentState" json:"state,omitempty"`
+	StartTime        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	MessagesReceived uint64                 `protobuf:"varint,5,opt,name=messages_received,json=messagesReceived,proto3" json:"messages_received,omitempty"`
+	MessagesSent     uint64                 `protobuf:"varint,6,opt,name=messages_sent,json=messagesSent,proto3" json:"messages_sent,omitempty"`
+	LastError        string                 `protobuf:"bytes,7,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	Fields           map[string]string      `protobuf:"bytes,8,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ComponentStatus) Reset() {
+	*x = ComponentStatus{}
+	mi := &file_plugin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComponentStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComponentStatus) ProtoMessage() {}
+
+func (x *ComponentStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComponentStatus.ProtoReflect.Descriptor instead.
+func (*ComponentStatus) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ComponentStatus) GetComponentId() string {
+	if x != nil {
+		return x.ComponentId
+	}
+	return ""
+}
+
+func (x *ComponentStatus) GetComponentType() string {
+	if x != nil {
+		return x.ComponentType
+	}
+	return ""
+}
+
+func (x *ComponentStatus) GetState() ComponentState {
+	if x != nil {
+		return x.State
+	}
+	return ComponentState_COMPONENT_STATE_UNSPECIFIED
+}
+
+func (x *ComponentStatus) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ComponentStatus) GetMessagesReceived() uint64 {
+	if x != nil {
+		return x.MessagesReceived
+	}
+	return 0
+}
+
+func (x *ComponentStatus) GetMessagesSent() uint64 {
+	if x != nil {
+		return x.MessagesSent
+	}
+	return 0
+}
+
+func (x *ComponentStatus) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *ComponentStatus) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type ListComponentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ComponentType string                 `protobuf:"bytes,1,opt,name=component_type,json=componentType,proto3" json:"component_type,omitempty"` // optional filter
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListComponentsRequest) Reset() {
+	*x = ListComponentsRequest{}
+	mi := &file_plugin_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListComponentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListComponentsRequest) ProtoMessage() {}
+
+func (x *ListComponentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListComponentsRequest.ProtoReflect.Descriptor instead.
+func (*ListComponentsRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListComponentsRequest) GetComponentType() string {
+	if x != nil {
+		return x.ComponentType
+	}
+	return ""
+}
+
+type ListComponentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Components    []*ComponentStatus     `protobuf:"bytes,1,rep,name=components,proto3" json:"components,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListComponentsResponse) Reset() {
+	*x = ListComponentsResponse{}
+	mi := &file_plugin_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListComponentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListComponentsResponse) ProtoMessage() {}
+
+func (x *ListComponentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListComponentsResponse.ProtoReflect.Descriptor instead.
+func (*ListComponentsResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListComponentsResponse) GetComponents() []*ComponentStatus {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+type PluginMessageEnvelope struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Content:
+	//
+	//	*PluginMessageEnvelope_SimMessage
+	//	*PluginMessageEnvelope_Ack
+	//	*PluginMessageEnvelope_Nak
+	//	*PluginMessageEnvelope_Init
+	//	*PluginMessageEnvelope_Shutdown
+	//	*PluginMessageEnvelope_Credit
+	//	*PluginMessageEnvelope_Heartbeat
+	//	*PluginMessageEnvelope_ClockSync
+	//	*PluginMessageEnvelope_StepBegin
+	//	*PluginMessageEnvelope_StepComplete
+	//	*PluginMessageEnvelope_Lookahead
+	//	*PluginMessageEnvelope_Batch
+	//	*PluginMessageEnvelope_BatchResult
+	//	*PluginMessageEnvelope_Chunk
+	//	*PluginMessageEnvelope_Compression
+	Content       isPluginMessageEnvelope_Content `protobuf_oneof:"content"`
+	Sequence      uint64                          `protobuf:"varint,16,opt,name=sequence,proto3" json:"sequence,omitempty"` // set by the sender in reliable mode; echoed in acks/naks
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginMessageEnvelope) Reset() {
+	*x = PluginMessageEnvelope{}
+	mi := &file_plugin_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginMessageEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginMessageEnvelope) ProtoMessage() {}
+
+func (x *PluginMessageEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginMessageEnvelope.ProtoReflect.Descriptor instead.
+func (*PluginMessageEnvelope) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PluginMessageEnvelope) GetContent() isPluginMessageEnvelope_Content {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetSimMessage() *SimMessage {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_SimMessage); ok {
+			return x.SimMessage
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetAck() *PluginAck {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetNak() *PluginNak {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_Nak); ok {
+			return x.Nak
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetInit() *PluginInit {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_Init); ok {
+			return x.Init
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetShutdown() *PluginShutdown {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_Shutdown); ok {
+			return x.Shutdown
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetCredit() *PluginCredit {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_Credit); ok {
+			return x.Credit
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetHeartbeat() *PluginHeartbeat {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetClockSync() *PluginClockSync {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_ClockSync); ok {
+			return x.ClockSync
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetStepBegin() *PluginStepBegin {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_StepBegin); ok {
+			return x.StepBegin
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetStepComplete() *PluginStepComplete {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_StepComplete); ok {
+			return x.StepComplete
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetLookahead() *PluginLookahead {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_Lookahead); ok {
+			return x.Lookahead
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetBatch() *MessageBatch {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_Batch); ok {
+			return x.Batch
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetBatchResult() *MessageBatchResponse {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_BatchResult); ok {
+			return x.BatchResult
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetChunk() *PluginChunk {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetCompression() *PluginCompression {
+	if x != nil {
+		if x, ok := x.Content.(*PluginMessageEnvelope_Compression); ok {
+			return x.Compression
+		}
+	}
+	return nil
+}
+
+func (x *PluginMessageEnvelope) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type isPluginMessageEnvelope_Content interface {
+	isPluginMessageEnvelope_Content()
+}
+
+type PluginMessageEnvelope_SimMessage struct {
+	SimMessage *SimMessage `protobuf:"bytes,1,opt,name=sim_message,json=simMessage,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_Ack struct {
+	Ack *PluginAck `protobuf:"bytes,2,opt,name=ack,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_Nak struct {
+	Nak *PluginNak `protobuf:"bytes,3,opt,name=nak,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_Init struct {
+	Init *PluginInit `protobuf:"bytes,4,opt,name=init,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_Shutdown struct {
+	Shutdown *PluginShutdown `protobuf:"bytes,5,opt,name=shutdown,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_Credit struct {
+	Credit *PluginCredit `protobuf:"bytes,6,opt,name=credit,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_Heartbeat struct {
+	Heartbeat *PluginHeartbeat `protobuf:"bytes,7,opt,name=heartbeat,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_ClockSync struct {
+	ClockSync *PluginClockSync `protobuf:"bytes,8,opt,name=clock_sync,json=clockSync,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_StepBegin struct {
+	StepBegin *PluginStepBegin `protobuf:"bytes,9,opt,name=step_begin,json=stepBegin,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_StepComplete struct {
+	StepComplete *PluginStepComplete `protobuf:"bytes,10,opt,name=step_complete,json=stepComplete,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_Lookahead struct {
+	Lookahead *PluginLookahead `protobuf:"bytes,11,opt,name=lookahead,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_Batch struct {
+	Batch *MessageBatch `protobuf:"bytes,12,opt,name=batch,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_BatchResult struct {
+	BatchResult *MessageBatchResponse `protobuf:"bytes,13,opt,name=batch_result,json=batchResult,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_Chunk struct {
+	Chunk *PluginChunk `protobuf:"bytes,14,opt,name=chunk,proto3,oneof"`
+}
+
+type PluginMessageEnvelope_Compression struct {
+	Compression *PluginCompression `protobuf:"bytes,15,opt,name=compression,proto3,oneof"`
+}
+
+func (*PluginMessageEnvelope_SimMessage) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_Ack) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_Nak) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_Init) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_Shutdown) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_Credit) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_Heartbeat) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_ClockSync) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_StepBegin) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_StepComplete) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_Lookahead) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_Batch) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_BatchResult) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_Chunk) isPluginMessageEnvelope_Content() {}
+
+func (*PluginMessageEnvelope_Compression) isPluginMessageEnvelope_Content() {}
+
+// PluginChunk carries one piece of a SimMessage whose payload is too large
+// for a single envelope. The chunks of a message are sent back to back, in
+// order, and share the envelope sequence of the whole message.
+type PluginChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Index         uint32                 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Count         uint32                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Data          []byte                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	Header        *SimMessage            `protobuf:"bytes,5,opt,name=header,proto3" json:"header,omitempty"`                         // first chunk only: the message without its payload
+	TotalSize     uint64                 `protobuf:"varint,6,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"` // size of the whole payload
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginChunk) Reset() {
+	*x = PluginChunk{}
+	mi := &file_plugin_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginChunk) ProtoMessage() {}
+
+func (x *PluginChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginChunk.ProtoReflect.Descriptor instead.
+func (*PluginChunk) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PluginChunk) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *PluginChunk) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *PluginChunk) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PluginChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *PluginChunk) GetHeader() *SimMessage {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *PluginChunk) GetTotalSize() uint64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+// PluginCompression announces the payload codecs a side can decode, most
+// preferred first. Each side compresses only with a codec the other announced.
+type PluginCompression struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      []string               `protobuf:"bytes,1,rep,name=accepted,proto3" json:"accepted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginCompression) Reset() {
+	*x = PluginCompression{}
+	mi := &file_plugin_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginCompression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginCompression) ProtoMessage() {}
+
+func (x *PluginCompression) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginCompression.ProtoReflect.Descriptor instead.
+func (*PluginCompression) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PluginCompression) GetAccepted() []string {
+	if x != nil {
+		return x.Accepted
+	}
+	return nil
+}
+
+type PluginInit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ComponentId   string                 `protobuf:"bytes,1,opt,name=component_id,json=componentId,proto3" json:"component_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginInit) Reset() {
+	*x = PluginInit{}
+	mi := &file_plugin_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginInit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginInit) ProtoMessage() {}
+
+func (x *PluginInit) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginInit.ProtoReflect.Descriptor instead.
+func (*PluginInit) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PluginInit) GetComponentId() string {
+	if x != nil {
+		return x.ComponentId
+	}
+	return ""
+}
+
+type PluginShutdown struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	ComponentId   string                 `protobuf:"bytes,2,opt,name=component_id,json=componentId,proto3" json:"component_id,omitempty"` // optional: shut down only this component
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginShutdown) Reset() {
+	*x = PluginShutdown{}
+	mi := &file_plugin_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginShutdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginShutdown) ProtoMessage() {}
+
+func (x *PluginShutdown) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginShutdown.ProtoReflect.Descriptor instead.
+func (*PluginShutdown) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PluginShutdown) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PluginShutdown) GetComponentId() string {
+	if x != nil {
+		return x.ComponentId
+	}
+	return ""
+}
+
+// PluginCredit grants the peer permission to send `credits` more SimMessages
+// on the stream. Grants are additive.
+type PluginCredit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Credits       uint32                 `protobuf:"varint,1,opt,name=credits,proto3" json:"credits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginCredit) Reset() {
+	*x = PluginCredit{}
+	mi := &file_plugin_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginCredit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginCredit) ProtoMessage() {}
+
+func (x *PluginCredit) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginCredit.ProtoReflect.Descriptor instead.
+func (*PluginCredit) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PluginCredit) GetCredits() uint32 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+// PluginClockSync is sent by the core to set the plugin's simulation clock.
+// sim_time is the simulation time at wall_time; while not paused, sim time
+// advances at time_scale times wall-clock speed.
+type PluginClockSync struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SimTime       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=sim_time,json=simTime,proto3" json:"sim_time,omitempty"`
+	WallTime      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=wall_time,json=wallTime,proto3" json:"wall_time,omitempty"`
+	TimeScale     float64                `protobuf:"fixed64,3,opt,name=time_scale,json=timeScale,proto3" json:"time_scale,omitempty"`
+	Paused        bool                   `protobuf:"varint,4,opt,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginClockSync) Reset() {
+	*x = PluginClockSync{}
+	mi := &file_plugin_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginClockSync) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginClockSync) ProtoMessage() {}
+
+func (x *PluginClockSync) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginClockSync.ProtoReflect.Descriptor instead.
+func (*PluginClockSync) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PluginClockSync) GetSimTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SimTime
+	}
+	return nil
+}
+
+func (x *PluginClockSync) GetWallTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WallTime
+	}
+	return nil
+}
+
+func (x *PluginClockSync) GetTimeScale() float64 {
+	if x != nil {
+		return x.TimeScale
+	}
+	return 0
+}
+
+func (x *PluginClockSync) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+// PluginStepBegin is sent by the core in lockstep mode after the messages for
+// a step. The plugin handles those messages, advances to sim_time, runs the
+// step and answers with PluginStepComplete.
+type PluginStepBegin struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Step          uint64                 `protobuf:"varint,1,opt,name=step,proto3" json:"step,omitempty"`
+	SimTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=sim_time,json=simTime,proto3" json:"sim_time,omitempty"`
+	Dt            *durationpb.Duration   `protobuf:"bytes,3,opt,name=dt,proto3" json:"dt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginStepBegin) Reset() {
+	*x = PluginStepBegin{}
+	mi := &file_plugin_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginStepBegin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginStepBegin) ProtoMessage() {}
+
+func (x *PluginStepBegin) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginStepBegin.ProtoReflect.Descriptor instead.
+func (*PluginStepBegin) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *PluginStepBegin) GetStep() uint64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *PluginStepBegin) GetSimTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SimTime
+	}
+	return nil
+}
+
+func (x *PluginStepBegin) GetDt() *durationpb.Duration {
+	if x != nil {
+		return x.Dt
+	}
+	return nil
+}
+
+// PluginStepComplete reports that the plugin finished a step. It is sent after
+// every message the plugin produced during the step.
+type PluginStepComplete struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Step            uint64                 `protobuf:"varint,1,opt,name=step,proto3" json:"step,omitempty"`
+	ComponentErrors map[string]string      `protobuf:"bytes,2,rep,name=component_errors,json=componentErrors,proto3" json:"component_errors,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // component ID -> step error
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PluginStepComplete) Reset() {
+	*x = PluginStepComplete{}
+	mi := &file_plugin_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginStepComplete) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginStepComplete) ProtoMessage() {}
+
+func (x *PluginStepComplete) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginStepComplete.ProtoReflect.Descriptor instead.
+func (*PluginStepComplete) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PluginStepComplete) GetStep() uint64 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *PluginStepComplete) GetComponentErrors() map[string]string {
+	if x != nil {
+		return x.ComponentErrors
+	}
+	return nil
+}
+
+// PluginLookahead declares how far ahead of its current sim time a component
+// promises not to send messages.
+type PluginLookahead struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ComponentId   string                 `protobuf:"bytes,1,opt,name=component_id,json=componentId,proto3" json:"component_id,omitempty"`
+	Lookahead     *durationpb.Duration   `protobuf:"bytes,2,opt,name=lookahead,proto3" json:"lookahead,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginLookahead) Reset() {
+	*x = PluginLookahead{}
+	mi := &file_plugin_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginLookahead) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginLookahead) ProtoMessage() {}
+
+func (x *PluginLookahead) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginLookahead.ProtoReflect.Descriptor instead.
+func (*PluginLookahead) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *PluginLookahead) GetComponentId() string {
+	if x != nil {
+		return x.ComponentId
+	}
+	return ""
+}
+
+func (x *PluginLookahead) GetLookahead() *durationpb.Duration {
+	if x != nil {
+		return x.Lookahead
+	}
+	return nil
+}
+
+// PluginHeartbeat is sent periodically by both sides. A peer that sends
+// nothing for longer than the heartbeat timeout is considered dead.
+type PluginHeartbeat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	Load          *PluginLoad            `protobuf:"bytes,2,opt,name=load,proto3" json:"load,omitempty"` // set by the plugin side
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginHeartbeat) Reset() {
+	*x = PluginHeartbeat{}
+	mi := &file_plugin_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginHeartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginHeartbeat) ProtoMessage() {}
+
+func (x *PluginHeartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginHeartbeat.ProtoReflect.Descriptor instead.
+func (*PluginHeartbeat) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *PluginHeartbeat) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *PluginHeartbeat) GetLoad() *PluginLoad {
+	if x != nil {
+		return x.Load
+	}
+	return nil
+}
+
+// PluginLoad describes how busy a plugin's stream is.
+type PluginLoad struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	QueueDepth       uint32                 `protobuf:"varint,1,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`                       // outbound envelopes waiting to be written
+	InFlight         uint32                 `protobuf:"varint,2,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"`                             // inbound messages being handled
+	Pending          uint32                 `protobuf:"varint,3,opt,name=pending,proto3" json:"pending,omitempty"`                                               // inbound messages queued for a worker
+	Components       uint32                 `protobuf:"varint,4,opt,name=components,proto3" json:"components,omitempty"`                                         // components initialized on the stream
+	OldestInFlightMs uint64                 `protobuf:"varint,5,opt,name=oldest_in_flight_ms,json=oldestInFlightMs,proto3" json:"oldest_in_flight_ms,omitempty"` // age of the longest-running handler call
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PluginLoad) Reset() {
+	*x = PluginLoad{}
+	mi := &file_plugin_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginLoad) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginLoad) ProtoMessage() {}
+
+func (x *PluginLoad) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginLoad.ProtoReflect.Descriptor instead.
+func (*PluginLoad) Descriptor() ([]byte, []int) {
+	return file_plugin_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *PluginLoad) GetQueueDepth() uint32 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+func (x *PluginLoad) GetInFlight() uint32 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+func (x *PluginLoad) GetPending() uint32 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *PluginLoad) GetComponents() uint32 {
+	if x != nil {
+		return x.Components
+	}
+	return 0
+}
+
+func (x *PluginLoad) GetOldestInFlightMs() uint64 {
+	if x != nil {
+		return x.OldestInFlightMs
+	}
+	return 0
+}
+
+type PluginAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PluginAck) Reset() {
+	*x = PluginAck{}
+	mi := &file_plugin_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PluginAck) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
 func (*PluginAck) ProtoMessage() {}
 
 func (x *PluginAck) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[15]
+	mi := &file_plugin_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1105,7 +2482,7 @@ func (x *PluginAck) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginAck.ProtoReflect.Descriptor instead.
 func (*PluginAck) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{15}
+	return file_plugin_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *PluginAck) GetMessageId() string {
@@ -1115,17 +2492,25 @@ func (x *PluginAck) GetMessageId() string {
 	return ""
 }
 
+func (x *PluginAck) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
 type PluginNak struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
 	ErrorMessage  string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	Sequence      uint64                 `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PluginNak) Reset() {
 	*x = PluginNak{}
-	mi := &file_plugin_proto_msgTypes[16]
+	mi := &file_plugin_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1137,7 +2522,7 @@ func (x *PluginNak) String() string {
 func (*PluginNak) ProtoMessage() {}
 
 func (x *PluginNak) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[16]
+	mi := &file_plugin_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1150,7 +2535,7 @@ func (x *PluginNak) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PluginNak.ProtoReflect.Descriptor instead.
 func (*PluginNak) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{16}
+	return file_plugin_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *PluginNak) GetMessageId() string {
@@ -1167,6 +2552,13 @@ func (x *PluginNak) GetErrorMessage() string {
 	return ""
 }
 
+func (x *PluginNak) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
 type DestroyComponentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ComponentId   string                 `protobuf:"bytes,1,opt,name=component_id,json=componentId,proto3" json:"component_id,omitempty"`
@@ -1176,7 +2568,7 @@ type DestroyComponentRequest struct {
 
 func (x *DestroyComponentRequest) Reset() {
 	*x = DestroyComponentRequest{}
-	mi := &file_plugin_proto_msgTypes[17]
+	mi := &file_plugin_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1188,7 +2580,7 @@ func (x *DestroyComponentRequest) String() string {
 func (*DestroyComponentRequest) ProtoMessage() {}
 
 func (x *DestroyComponentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[17]
+	mi := &file_plugin_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1201,7 +2593,7 @@ func (x *DestroyComponentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DestroyComponentRequest.ProtoReflect.Descriptor instead.
 func (*DestroyComponentRequest) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{17}
+	return file_plugin_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *DestroyComponentRequest) GetComponentId() string {
@@ -1220,7 +2612,7 @@ type DestroyComponentResponse struct {
 
 func (x *DestroyComponentResponse) Reset() {
 	*x = DestroyComponentResponse{}
-	mi := &file_plugin_proto_msgTypes[18]
+	mi := &file_plugin_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1232,7 +2624,7 @@ func (x *DestroyComponentResponse) String() string {
 func (*DestroyComponentResponse) ProtoMessage() {}
 
 func (x *DestroyComponentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_proto_msgTypes[18]
+	mi := &file_plugin_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1245,7 +2637,7 @@ func (x *DestroyComponentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DestroyComponentResponse.ProtoReflect.Descriptor instead.
 func (*DestroyComponentResponse) Descriptor() ([]byte, []int) {
-	return file_plugin_proto_rawDescGZIP(), []int{18}
+	return file_plugin_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *DestroyComponentResponse) GetSuccess() bool {
@@ -1259,7 +2651,7 @@ var File_plugin_proto protoreflect.FileDescriptor
 
 const file_plugin_proto_rawDesc = "" +
 	"\n" +
-	"\fplugin.proto\x12\tsimsdkrpc\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\x11\n" +
+	"\fplugin.proto\x12\tsimsdkrpc\x1a\x1egoogle/protobuf/duration.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\"\x11\n" +
 	"\x0fManifestRequest\"C\n" +
 	"\x10ManifestResponse\x12/\n" +
 	"\bmanifest\x18\x01 \x01(\v2\x13.simsdkrpc.ManifestR\bmanifest\"\xc8\x02\n" +
@@ -1310,7 +2702,13 @@ const file_plugin_proto_rawDesc = "" +
 	"\x0fParametersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x19\n" +
-	"\x17CreateComponentResponse\"\x89\x02\n" +
+	"\x17CreateComponentResponse\"\xe7\x01\n" +
+	"\x11ComponentSnapshot\x12!\n" +
+	"\fcomponent_id\x18\x01 \x01(\tR\vcomponentId\x12H\n" +
+	"\x0ecreate_request\x18\x02 \x01(\v2!.simsdkrpc.CreateComponentRequestR\rcreateRequest\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\rR\aversion\x12\x14\n" +
+	"\x05state\x18\x04 \x01(\fR\x05state\x125\n" +
+	"\btaken_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\atakenAt\"\xf9\x02\n" +
 	"\n" +
 	"SimMessage\x12!\n" +
 	"\fmessage_type\x18\x01 \x01(\tR\vmessageType\x12\x1d\n" +
@@ -1318,32 +2716,143 @@ const file_plugin_proto_rawDesc = "" +
 	"message_id\x18\x02 \x01(\tR\tmessageId\x12!\n" +
 	"\fcomponent_id\x18\x03 \x01(\tR\vcomponentId\x12\x18\n" +
 	"\apayload\x18\x04 \x01(\fR\apayload\x12?\n" +
-	"\bmetadata\x18\x05 \x03(\v2#.simsdkrpc.SimMessage.MetadataEntryR\bmetadata\x1a;\n" +
+	"\bmetadata\x18\x05 \x03(\v2#.simsdkrpc.SimMessage.MetadataEntryR\bmetadata\x125\n" +
+	"\bsim_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\asimTime\x127\n" +
+	"\twall_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bwallTime\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"U\n" +
 	"\x0fMessageResponse\x12B\n" +
-	"\x11outbound_messages\x18\x01 \x03(\v2\x15.simsdkrpc.SimMessageR\x10outboundMessages\"\x96\x02\n" +
+	"\x11outbound_messages\x18\x01 \x03(\v2\x15.simsdkrpc.SimMessageR\x10outboundMessages\"_\n" +
+	"\fMessageBatch\x121\n" +
+	"\bmessages\x18\x01 \x03(\v2\x15.simsdkrpc.SimMessageR\bmessages\x12\x1c\n" +
+	"\tsequences\x18\x02 \x03(\x04R\tsequences\"\xb1\x01\n" +
+	"\rMessageResult\x12(\n" +
+	"\x03ack\x18\x01 \x01(\v2\x14.simsdkrpc.PluginAckH\x00R\x03ack\x12(\n" +
+	"\x03nak\x18\x02 \x01(\v2\x14.simsdkrpc.PluginNakH\x00R\x03nak\x12B\n" +
+	"\x11outbound_messages\x18\x03 \x03(\v2\x15.simsdkrpc.SimMessageR\x10outboundMessagesB\b\n" +
+	"\x06result\"J\n" +
+	"\x14MessageBatchResponse\x122\n" +
+	"\aresults\x18\x01 \x03(\v2\x18.simsdkrpc.MessageResultR\aresults\"\x88\x02\n" +
+	"\x16ControlFunctionRequest\x12\x1f\n" +
+	"\vfunction_id\x18\x01 \x01(\tR\n" +
+	"functionId\x12!\n" +
+	"\fcomponent_id\x18\x02 \x01(\tR\vcomponentId\x12Q\n" +
+	"\n" +
+	"parameters\x18\x03 \x03(\v21.simsdkrpc.ControlFunctionRequest.ParametersEntryR\n" +
+	"parameters\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\fR\apayload\x1a=\n" +
+	"\x0fParametersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xbc\x01\n" +
+	"\x17ControlFunctionResponse\x12\x16\n" +
+	"\x06result\x18\x01 \x01(\fR\x06result\x12L\n" +
+	"\bmetadata\x18\x02 \x03(\v20.simsdkrpc.ControlFunctionResponse.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb3\x03\n" +
+	"\x0fComponentStatus\x12!\n" +
+	"\fcomponent_id\x18\x01 \x01(\tR\vcomponentId\x12%\n" +
+	"\x0ecomponent_type\x18\x02 \x01(\tR\rcomponentType\x12/\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x19.simsdkrpc.ComponentStateR\x05state\x129\n" +
+	"\n" +
+	"start_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x12+\n" +
+	"\x11messages_received\x18\x05 \x01(\x04R\x10messagesReceived\x12#\n" +
+	"\rmessages_sent\x18\x06 \x01(\x04R\fmessagesSent\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\a \x01(\tR\tlastError\x12>\n" +
+	"\x06fields\x18\b \x03(\v2&.simsdkrpc.ComponentStatus.FieldsEntryR\x06fields\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\">\n" +
+	"\x15ListComponentsRequest\x12%\n" +
+	"\x0ecomponent_type\x18\x01 \x01(\tR\rcomponentType\"T\n" +
+	"\x16ListComponentsResponse\x12:\n" +
+	"\n" +
+	"components\x18\x01 \x03(\v2\x1a.simsdkrpc.ComponentStatusR\n" +
+	"components\"\x86\a\n" +
 	"\x15PluginMessageEnvelope\x128\n" +
 	"\vsim_message\x18\x01 \x01(\v2\x15.simsdkrpc.SimMessageH\x00R\n" +
 	"simMessage\x12(\n" +
 	"\x03ack\x18\x02 \x01(\v2\x14.simsdkrpc.PluginAckH\x00R\x03ack\x12(\n" +
 	"\x03nak\x18\x03 \x01(\v2\x14.simsdkrpc.PluginNakH\x00R\x03nak\x12+\n" +
 	"\x04init\x18\x04 \x01(\v2\x15.simsdkrpc.PluginInitH\x00R\x04init\x127\n" +
-	"\bshutdown\x18\x05 \x01(\v2\x19.simsdkrpc.PluginShutdownH\x00R\bshutdownB\t\n" +
-	"\acontent\"/\n" +
+	"\bshutdown\x18\x05 \x01(\v2\x19.simsdkrpc.PluginShutdownH\x00R\bshutdown\x121\n" +
+	"\x06credit\x18\x06 \x01(\v2\x17.simsdkrpc.PluginCreditH\x00R\x06credit\x12:\n" +
+	"\theartbeat\x18\a \x01(\v2\x1a.simsdkrpc.PluginHeartbeatH\x00R\theartbeat\x12;\n" +
+	"\n" +
+	"clock_sync\x18\b \x01(\v2\x1a.simsdkrpc.PluginClockSyncH\x00R\tclockSync\x12;\n" +
+	"\n" +
+	"step_begin\x18\t \x01(\v2\x1a.simsdkrpc.PluginStepBeginH\x00R\tstepBegin\x12D\n" +
+	"\rstep_complete\x18\n" +
+	" \x01(\v2\x1d.simsdkrpc.PluginStepCompleteH\x00R\fstepComplete\x12:\n" +
+	"\tlookahead\x18\v \x01(\v2\x1a.simsdkrpc.PluginLookaheadH\x00R\tlookahead\x12/\n" +
+	"\x05batch\x18\f \x01(\v2\x17.simsdkrpc.MessageBatchH\x00R\x05batch\x12D\n" +
+	"\fbatch_result\x18\r \x01(\v2\x1f.simsdkrpc.MessageBatchResponseH\x00R\vbatchResult\x12.\n" +
+	"\x05chunk\x18\x0e \x01(\v2\x16.simsdkrpc.PluginChunkH\x00R\x05chunk\x12@\n" +
+	"\vcompression\x18\x0f \x01(\v2\x1c.simsdkrpc.PluginCompressionH\x00R\vcompression\x12\x1a\n" +
+	"\bsequence\x18\x10 \x01(\x04R\bsequenceB\t\n" +
+	"\acontent\"\xba\x01\n" +
+	"\vPluginChunk\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\rR\x05index\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\rR\x05count\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04data\x12-\n" +
+	"\x06header\x18\x05 \x01(\v2\x15.simsdkrpc.SimMessageR\x06header\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x06 \x01(\x04R\ttotalSize\"/\n" +
+	"\x11PluginCompression\x12\x1a\n" +
+	"\baccepted\x18\x01 \x03(\tR\baccepted\"/\n" +
 	"\n" +
 	"PluginInit\x12!\n" +
-	"\fcomponent_id\x18\x01 \x01(\tR\vcomponentId\"(\n" +
+	"\fcomponent_id\x18\x01 \x01(\tR\vcomponentId\"K\n" +
 	"\x0ePluginShutdown\x12\x16\n" +
-	"\x06reason\x18\x01 \x01(\tR\x06reason\"*\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12!\n" +
+	"\fcomponent_id\x18\x02 \x01(\tR\vcomponentId\"(\n" +
+	"\fPluginCredit\x12\x18\n" +
+	"\acredits\x18\x01 \x01(\rR\acredits\"\xb8\x01\n" +
+	"\x0fPluginClockSync\x125\n" +
+	"\bsim_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\asimTime\x127\n" +
+	"\twall_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bwallTime\x12\x1d\n" +
+	"\n" +
+	"time_scale\x18\x03 \x01(\x01R\ttimeScale\x12\x16\n" +
+	"\x06paused\x18\x04 \x01(\bR\x06paused\"\x87\x01\n" +
+	"\x0fPluginStepBegin\x12\x12\n" +
+	"\x04step\x18\x01 \x01(\x04R\x04step\x125\n" +
+	"\bsim_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\asimTime\x12)\n" +
+	"\x02dt\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x02dt\"\xcb\x01\n" +
+	"\x12PluginStepComplete\x12\x12\n" +
+	"\x04step\x18\x01 \x01(\x04R\x04step\x12]\n" +
+	"\x10component_errors\x18\x02 \x03(\v22.simsdkrpc.PluginStepComplete.ComponentErrorsEntryR\x0fcomponentErrors\x1aB\n" +
+	"\x14ComponentErrorsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"m\n" +
+	"\x0fPluginLookahead\x12!\n" +
+	"\fcomponent_id\x18\x01 \x01(\tR\vcomponentId\x127\n" +
+	"\tlookahead\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\tlookahead\"q\n" +
+	"\x0fPluginHeartbeat\x123\n" +
+	"\asent_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12)\n" +
+	"\x04load\x18\x02 \x01(\v2\x15.simsdkrpc.PluginLoadR\x04load\"\xb3\x01\n" +
+	"\n" +
+	"PluginLoad\x12\x1f\n" +
+	"\vqueue_depth\x18\x01 \x01(\rR\n" +
+	"queueDepth\x12\x1b\n" +
+	"\tin_flight\x18\x02 \x01(\rR\binFlight\x12\x18\n" +
+	"\apending\x18\x03 \x01(\rR\apending\x12\x1e\n" +
+	"\n" +
+	"components\x18\x04 \x01(\rR\n" +
+	"components\x12-\n" +
+	"\x13oldest_in_flight_ms\x18\x05 \x01(\x04R\x10oldestInFlightMs\"F\n" +
 	"\tPluginAck\x12\x1d\n" +
 	"\n" +
-	"message_id\x18\x01 \x01(\tR\tmessageId\"O\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x04R\bsequence\"k\n" +
 	"\tPluginNak\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x12#\n" +
-	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"<\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\x12\x1a\n" +
+	"\bsequence\x18\x03 \x01(\x04R\bsequence\"<\n" +
 	"\x17DestroyComponentRequest\x12!\n" +
 	"\fcomponent_id\x18\x01 \x01(\tR\vcomponentId\"4\n" +
 	"\x18DestroyComponentResponse\x12\x18\n" +
@@ -1360,13 +2869,28 @@ const file_plugin_proto_rawDesc = "" +
 	"\tTIMESTAMP\x10\a\x12\f\n" +
 	"\bREPEATED\x10\b\x12\n" +
 	"\n" +
-	"\x06OBJECT\x10\t2\xa8\x03\n" +
+	"\x06OBJECT\x10\t*\xa4\x01\n" +
+	"\x0eComponentState\x12\x1f\n" +
+	"\x1bCOMPONENT_STATE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17COMPONENT_STATE_CREATED\x10\x01\x12\x1b\n" +
+	"\x17COMPONENT_STATE_RUNNING\x10\x02\x12\x1b\n" +
+	"\x17COMPONENT_STATE_STOPPED\x10\x03\x12\x1a\n" +
+	"\x16COMPONENT_STATE_PAUSED\x10\x042\xef\b\n" +
 	"\rPluginService\x12F\n" +
 	"\vGetManifest\x12\x1a.simsdkrpc.ManifestRequest\x1a\x1b.simsdkrpc.ManifestResponse\x12`\n" +
 	"\x17CreateComponentInstance\x12!.simsdkrpc.CreateComponentRequest\x1a\".simsdkrpc.CreateComponentResponse\x12P\n" +
 	"\x18DestroyComponentInstance\x12\x1c.google.protobuf.StringValue\x1a\x16.google.protobuf.Empty\x12B\n" +
-	"\rHandleMessage\x12\x15.simsdkrpc.SimMessage\x1a\x1a.simsdkrpc.MessageResponse\x12W\n" +
-	"\rMessageStream\x12 .simsdkrpc.PluginMessageEnvelope\x1a .simsdkrpc.PluginMessageEnvelope(\x010\x01B6Z4github.com/neurosimio/simsdk/rpc/simsdkrpc;simsdkrpcb\x06proto3"
+	"\rHandleMessage\x12\x15.simsdkrpc.SimMessage\x1a\x1a.simsdkrpc.MessageResponse\x12J\n" +
+	"\x0eHandleMessages\x12\x17.simsdkrpc.MessageBatch\x1a\x1f.simsdkrpc.MessageBatchResponse\x12W\n" +
+	"\rMessageStream\x12 .simsdkrpc.PluginMessageEnvelope\x1a .simsdkrpc.PluginMessageEnvelope(\x010\x01\x12^\n" +
+	"\x15InvokeControlFunction\x12!.simsdkrpc.ControlFunctionRequest\x1a\".simsdkrpc.ControlFunctionResponse\x12U\n" +
+	"\x0eListComponents\x12 .simsdkrpc.ListComponentsRequest\x1a!.simsdkrpc.ListComponentsResponse\x12N\n" +
+	"\x12GetComponentStatus\x12\x1c.google.protobuf.StringValue\x1a\x1a.simsdkrpc.ComponentStatus\x12F\n" +
+	"\x0ePauseComponent\x12\x1c.google.protobuf.StringValue\x1a\x16.google.protobuf.Empty\x12G\n" +
+	"\x0fResumeComponent\x12\x1c.google.protobuf.StringValue\x1a\x16.google.protobuf.Empty\x12F\n" +
+	"\x0eResetComponent\x12\x1c.google.protobuf.StringValue\x1a\x16.google.protobuf.Empty\x12O\n" +
+	"\x11SnapshotComponent\x12\x1c.google.protobuf.StringValue\x1a\x1c.simsdkrpc.ComponentSnapshot\x12H\n" +
+	"\x10RestoreComponent\x12\x1c.simsdkrpc.ComponentSnapshot\x1a\x16.google.protobuf.EmptyBBZ4github.com/neurosimio/simsdk/rpc/simsdkrpc;simsdkrpc\xaa\x02\tSimsdkrpcb\x06proto3"
 
 var (
 	file_plugin_proto_rawDescOnce sync.Once
@@ -1380,68 +2904,145 @@ func file_plugin_proto_rawDescGZIP() []byte {
 	return file_plugin_proto_rawDescData
 }
 
-var file_plugin_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_plugin_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_plugin_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_plugin_proto_goTypes = []any{
 	(FieldType)(0),                   // 0: simsdkrpc.FieldType
-	(*ManifestRequest)(nil),          // 1: simsdkrpc.ManifestRequest
-	(*ManifestResponse)(nil),         // 2: simsdkrpc.ManifestResponse
-	(*Manifest)(nil),                 // 3: simsdkrpc.Manifest
-	(*MessageType)(nil),              // 4: simsdkrpc.MessageType
-	(*ControlFunctionType)(nil),      // 5: simsdkrpc.ControlFunctionType
-	(*ComponentType)(nil),            // 6: simsdkrpc.ComponentType
-	(*TransportType)(nil),            // 7: simsdkrpc.TransportType
-	(*FieldSpec)(nil),                // 8: simsdkrpc.FieldSpec
-	(*CreateComponentRequest)(nil),   // 9: simsdkrpc.CreateComponentRequest
-	(*CreateComponentResponse)(nil),  // 10: simsdkrpc.CreateComponentResponse
-	(*SimMessage)(nil),               // 11: simsdkrpc.SimMessage
-	(*MessageResponse)(nil),          // 12: simsdkrpc.MessageResponse
-	(*PluginMessageEnvelope)(nil),    // 13: simsdkrpc.PluginMessageEnvelope
-	(*PluginInit)(nil),               // 14: simsdkrpc.PluginInit
-	(*PluginShutdown)(nil),           // 15: simsdkrpc.PluginShutdown
-	(*PluginAck)(nil),                // 16: simsdkrpc.PluginAck
-	(*PluginNak)(nil),                // 17: simsdkrpc.PluginNak
-	(*DestroyComponentRequest)(nil),  // 18: simsdkrpc.DestroyComponentRequest
-	(*DestroyComponentResponse)(nil), // 19: simsdkrpc.DestroyComponentResponse
-	nil,                              // 20: simsdkrpc.CreateComponentRequest.ParametersEntry
-	nil,                              // 21: simsdkrpc.SimMessage.MetadataEntry
-	(*wrapperspb.StringValue)(nil),   // 22: google.protobuf.StringValue
-	(*emptypb.Empty)(nil),            // 23: google.protobuf.Empty
+	(ComponentState)(0),              // 1: simsdkrpc.ComponentState
+	(*ManifestRequest)(nil),          // 2: simsdkrpc.ManifestRequest
+	(*ManifestResponse)(nil),         // 3: simsdkrpc.ManifestResponse
+	(*Manifest)(nil),                 // 4: simsdkrpc.Manifest
+	(*MessageType)(nil),              // 5: simsdkrpc.MessageType
+	(*ControlFunctionType)(nil),      // 6: simsdkrpc.ControlFunctionType
+	(*ComponentType)(nil),            // 7: simsdkrpc.ComponentType
+	(*TransportType)(nil),            // 8: simsdkrpc.TransportType
+	(*FieldSpec)(nil),                // 9: simsdkrpc.FieldSpec
+	(*CreateComponentRequest)(nil),   // 10: simsdkrpc.CreateComponentRequest
+	(*CreateComponentResponse)(nil),  // 11: simsdkrpc.CreateComponentResponse
+	(*ComponentSnapshot)(nil),        // 12: simsdkrpc.ComponentSnapshot
+	(*SimMessage)(nil),               // 13: simsdkrpc.SimMessage
+	(*MessageResponse)(nil),          // 14: simsdkrpc.MessageResponse
+	(*MessageBatch)(nil),             // 15: simsdkrpc.MessageBatch
+	(*MessageResult)(nil),            // 16: simsdkrpc.MessageResult
+	(*MessageBatchResponse)(nil),     // 17: simsdkrpc.MessageBatchResponse
+	(*ControlFunctionRequest)(nil),   // 18: simsdkrpc.ControlFunctionRequest
+	(*ControlFunctionResponse)(nil),  // 19: simsdkrpc.ControlFunctionResponse
+	(*ComponentStatus)(nil),          // 20: simsdkrpc.ComponentStatus
+	(*ListComponentsRequest)(nil),    // 21: simsdkrpc.ListComponentsRequest
+	(*ListComponentsResponse)(nil),   // 22: simsdkrpc.ListComponentsResponse
+	(*PluginMessageEnvelope)(nil),    // 23: simsdkrpc.PluginMessageEnvelope
+	(*PluginChunk)(nil),              // 24: simsdkrpc.PluginChunk
+	(*PluginCompression)(nil),        // 25: simsdkrpc.PluginCompression
+	(*PluginInit)(nil),               // 26: simsdkrpc.PluginInit
+	(*PluginShutdown)(nil),           // 27: simsdkrpc.PluginShutdown
+	(*PluginCredit)(nil),             // 28: simsdkrpc.PluginCredit
+	(*PluginClockSync)(nil),          // 29: simsdkrpc.PluginClockSync
+	(*PluginStepBegin)(nil),          // 30: simsdkrpc.PluginStepBegin
+	(*PluginStepComplete)(nil),       // 31: simsdkrpc.PluginStepComplete
+	(*PluginLookahead)(nil),          // 32: simsdkrpc.PluginLookahead
+	(*PluginHeartbeat)(nil),          // 33: simsdkrpc.PluginHeartbeat
+	(*PluginLoad)(nil),               // 34: simsdkrpc.PluginLoad
+	(*PluginAck)(nil),                // 35: simsdkrpc.PluginAck
+	(*PluginNak)(nil),                // 36: simsdkrpc.PluginNak
+	(*DestroyComponentRequest)(nil),  // 37: simsdkrpc.DestroyComponentRequest
+	(*DestroyComponentResponse)(nil), // 38: simsdkrpc.DestroyComponentResponse
+	nil,                              // 39: simsdkrpc.CreateComponentRequest.ParametersEntry
+	nil,                              // 40: simsdkrpc.SimMessage.MetadataEntry
+	nil,                              // 41: simsdkrpc.ControlFunctionRequest.ParametersEntry
+	nil,                              // 42: simsdkrpc.ControlFunctionResponse.MetadataEntry
+	nil,                              // 43: simsdkrpc.ComponentStatus.FieldsEntry
+	nil,                              // 44: simsdkrpc.PluginStepComplete.ComponentErrorsEntry
+	(*timestamppb.Timestamp)(nil),    // 45: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),      // 46: google.protobuf.Duration
+	(*wrapperspb.StringValue)(nil),   // 47: google.protobuf.StringValue
+	(*emptypb.Empty)(nil),            // 48: google.protobuf.Empty
 }
 var file_plugin_proto_depIdxs = []int32{
-	3,  // 0: simsdkrpc.ManifestResponse.manifest:type_name -> simsdkrpc.Manifest
-	4,  // 1: simsdkrpc.Manifest.message_types:type_name -> simsdkrpc.MessageType
-	5,  // 2: simsdkrpc.Manifest.control_functions:type_name -> simsdkrpc.ControlFunctionType
-	6,  // 3: simsdkrpc.Manifest.component_types:type_name -> simsdkrpc.ComponentType
-	7,  // 4: simsdkrpc.Manifest.transport_types:type_name -> simsdkrpc.TransportType
-	8,  // 5: simsdkrpc.MessageType.fields:type_name -> simsdkrpc.FieldSpec
-	8,  // 6: simsdkrpc.ControlFunctionType.fields:type_name -> simsdkrpc.FieldSpec
+	4,  // 0: simsdkrpc.ManifestResponse.manifest:type_name -> simsdkrpc.Manifest
+	5,  // 1: simsdkrpc.Manifest.message_types:type_name -> simsdkrpc.MessageType
+	6,  // 2: simsdkrpc.Manifest.control_functions:type_name -> simsdkrpc.ControlFunctionType
+	7,  // 3: simsdkrpc.Manifest.component_types:type_name -> simsdkrpc.ComponentType
+	8,  // 4: simsdkrpc.Manifest.transport_types:type_name -> simsdkrpc.TransportType
+	9,  // 5: simsdkrpc.MessageType.fields:type_name -> simsdkrpc.FieldSpec
+	9,  // 6: simsdkrpc.ControlFunctionType.fields:type_name -> simsdkrpc.FieldSpec
 	0,  // 7: simsdkrpc.FieldSpec.type:type_name -> simsdkrpc.FieldType
 	0,  // 8: simsdkrpc.FieldSpec.subtype:type_name -> simsdkrpc.FieldType
-	8,  // 9: simsdkrpc.FieldSpec.object_fields:type_name -> simsdkrpc.FieldSpec
-	20, // 10: simsdkrpc.CreateComponentRequest.parameters:type_name -> simsdkrpc.CreateComponentRequest.ParametersEntry
-	21, // 11: simsdkrpc.SimMessage.metadata:type_name -> simsdkrpc.SimMessage.MetadataEntry
-	11, // 12: simsdkrpc.MessageResponse.outbound_messages:type_name -> simsdkrpc.SimMessage
-	11, // 13: simsdkrpc.PluginMessageEnvelope.sim_message:type_name -> simsdkrpc.SimMessage
-	16, // 14: simsdkrpc.PluginMessageEnvelope.ack:type_name -> simsdkrpc.PluginAck
-	17, // 15: simsdkrpc.PluginMessageEnvelope.nak:type_name -> simsdkrpc.PluginNak
-	14, // 16: simsdkrpc.PluginMessageEnvelope.init:type_name -> simsdkrpc.PluginInit
-	15, // 17: simsdkrpc.PluginMessageEnvelope.shutdown:type_name -> simsdkrpc.PluginShutdown
-	1,  // 18: simsdkrpc.PluginService.GetManifest:input_type -> simsdkrpc.ManifestRequest
-	9,  // 19: simsdkrpc.PluginService.CreateComponentInstance:input_type -> simsdkrpc.CreateComponentRequest
-	22, // 20: simsdkrpc.PluginService.DestroyComponentInstance:input_type -> google.protobuf.StringValue
-	11, // 21: simsdkrpc.PluginService.HandleMessage:input_type -> simsdkrpc.SimMessage
-	13, // 22: simsdkrpc.PluginService.MessageStream:input_type -> simsdkrpc.PluginMessageEnvelope
-	2,  // 23: simsdkrpc.PluginService.GetManifest:output_type -> simsdkrpc.ManifestResponse
-	10, // 24: simsdkrpc.PluginService.CreateComponentInstance:output_type -> simsdkrpc.CreateComponentResponse
-	23, // 25: simsdkrpc.PluginService.DestroyComponentInstance:output_type -> google.protobuf.Empty
-	12, // 26: simsdkrpc.PluginService.HandleMessage:output_type -> simsdkrpc.MessageResponse
-	13, // 27: simsdkrpc.PluginService.MessageStream:output_type -> simsdkrpc.PluginMessageEnvelope
-	23, // [23:28] is the sub-list for method output_type
-	18, // [18:23] is the sub-list for method input_type
-	18, // [18:18] is the sub-list for extension type_name
-	18, // [18:18] is the sub-list for extension extendee
-	0,  // [0:18] is the sub-list for field type_name
+	9,  // 9: simsdkrpc.FieldSpec.object_fields:type_name -> simsdkrpc.FieldSpec
+	39, // 10: simsdkrpc.CreateComponentRequest.parameters:type_name -> simsdkrpc.CreateComponentRequest.ParametersEntry
+	10, // 11: simsdkrpc.ComponentSnapshot.create_request:type_name -> simsdkrpc.CreateComponentRequest
+	45, // 12: simsdkrpc.ComponentSnapshot.taken_at:type_name -> google.protobuf.Timestamp
+	40, // 13: simsdkrpc.SimMessage.metadata:type_name -> simsdkrpc.SimMessage.MetadataEntry
+	45, // 14: simsdkrpc.SimMessage.sim_time:type_name -> google.protobuf.Timestamp
+	45, // 15: simsdkrpc.SimMessage.wall_time:type_name -> google.protobuf.Timestamp
+	13, // 16: simsdkrpc.MessageResponse.outbound_messages:type_name -> simsdkrpc.SimMessage
+	13, // 17: simsdkrpc.MessageBatch.messages:type_name -> simsdkrpc.SimMessage
+	35, // 18: simsdkrpc.MessageResult.ack:type_name -> simsdkrpc.PluginAck
+	36, // 19: simsdkrpc.MessageResult.nak:type_name -> simsdkrpc.PluginNak
+	13, // 20: simsdkrpc.MessageResult.outbound_messages:type_name -> simsdkrpc.SimMessage
+	16, // 21: simsdkrpc.MessageBatchResponse.results:type_name -> simsdkrpc.MessageResult
+	41, // 22: simsdkrpc.ControlFunctionRequest.parameters:type_name -> simsdkrpc.ControlFunctionRequest.ParametersEntry
+	42, // 23: simsdkrpc.ControlFunctionResponse.metadata:type_name -> simsdkrpc.ControlFunctionResponse.MetadataEntry
+	1,  // 24: simsdkrpc.ComponentStatus.state:type_name -> simsdkrpc.ComponentState
+	45, // 25: simsdkrpc.ComponentStatus.start_time:type_name -> google.protobuf.Timestamp
+	43, // 26: simsdkrpc.ComponentStatus.fields:type_name -> simsdkrpc.ComponentStatus.FieldsEntry
+	20, // 27: simsdkrpc.ListComponentsResponse.components:type_name -> simsdkrpc.ComponentStatus
+	13, // 28: simsdkrpc.PluginMessageEnvelope.sim_message:type_name -> simsdkrpc.SimMessage
+	35, // 29: simsdkrpc.PluginMessageEnvelope.ack:type_name -> simsdkrpc.PluginAck
+	36, // 30: simsdkrpc.PluginMessageEnvelope.nak:type_name -> simsdkrpc.PluginNak
+	26, // 31: simsdkrpc.PluginMessageEnvelope.init:type_name -> simsdkrpc.PluginInit
+	27, // 32: simsdkrpc.PluginMessageEnvelope.shutdown:type_name -> simsdkrpc.PluginShutdown
+	28, // 33: simsdkrpc.PluginMessageEnvelope.credit:type_name -> simsdkrpc.PluginCredit
+	33, // 34: simsdkrpc.PluginMessageEnvelope.heartbeat:type_name -> simsdkrpc.PluginHeartbeat
+	29, // 35: simsdkrpc.PluginMessageEnvelope.clock_sync:type_name -> simsdkrpc.PluginClockSync
+	30, // 36: simsdkrpc.PluginMessageEnvelope.step_begin:type_name -> simsdkrpc.PluginStepBegin
+	31, // 37: simsdkrpc.PluginMessageEnvelope.step_complete:type_name -> simsdkrpc.PluginStepComplete
+	32, // 38: simsdkrpc.PluginMessageEnvelope.lookahead:type_name -> simsdkrpc.PluginLookahead
+	15, // 39: simsdkrpc.PluginMessageEnvelope.batch:type_name -> simsdkrpc.MessageBatch
+	17, // 40: simsdkrpc.PluginMessageEnvelope.batch_result:type_name -> simsdkrpc.MessageBatchResponse
+	24, // 41: simsdkrpc.PluginMessageEnvelope.chunk:type_name -> simsdkrpc.PluginChunk
+	25, // 42: simsdkrpc.PluginMessageEnvelope.compression:type_name -> simsdkrpc.PluginCompression
+	13, // 43: simsdkrpc.PluginChunk.header:type_name -> simsdkrpc.SimMessage
+	45, // 44: simsdkrpc.PluginClockSync.sim_time:type_name -> google.protobuf.Timestamp
+	45, // 45: simsdkrpc.PluginClockSync.wall_time:type_name -> google.protobuf.Timestamp
+	45, // 46: simsdkrpc.PluginStepBegin.sim_time:type_name -> google.protobuf.Timestamp
+	46, // 47: simsdkrpc.PluginStepBegin.dt:type_name -> google.protobuf.Duration
+	44, // 48: simsdkrpc.PluginStepComplete.component_errors:type_name -> simsdkrpc.PluginStepComplete.ComponentErrorsEntry
+	46, // 49: simsdkrpc.PluginLookahead.lookahead:type_name -> google.protobuf.Duration
+	45, // 50: simsdkrpc.PluginHeartbeat.sent_at:type_name -> google.protobuf.Timestamp
+	34, // 51: simsdkrpc.PluginHeartbeat.load:type_name -> simsdkrpc.PluginLoad
+	2,  // 52: simsdkrpc.PluginService.GetManifest:input_type -> simsdkrpc.ManifestRequest
+	10, // 53: simsdkrpc.PluginService.CreateComponentInstance:input_type -> simsdkrpc.CreateComponentRequest
+	47, // 54: simsdkrpc.PluginService.DestroyComponentInstance:input_type -> google.protobuf.StringValue
+	13, // 55: simsdkrpc.PluginService.HandleMessage:input_type -> simsdkrpc.SimMessage
+	15, // 56: simsdkrpc.PluginService.HandleMessages:input_type -> simsdkrpc.MessageBatch
+	23, // 57: simsdkrpc.PluginService.MessageStream:input_type -> simsdkrpc.PluginMessageEnvelope
+	18, // 58: simsdkrpc.PluginService.InvokeControlFunction:input_type -> simsdkrpc.ControlFunctionRequest
+	21, // 59: simsdkrpc.PluginService.ListComponents:input_type -> simsdkrpc.ListComponentsRequest
+	47, // 60: simsdkrpc.PluginService.GetComponentStatus:input_type -> google.protobuf.StringValue
+	47, // 61: simsdkrpc.PluginService.PauseComponent:input_type -> google.protobuf.StringValue
+	47, // 62: simsdkrpc.PluginService.ResumeComponent:input_type -> google.protobuf.StringValue
+	47, // 63: simsdkrpc.PluginService.ResetComponent:input_type -> google.protobuf.StringValue
+	47, // 64: simsdkrpc.PluginService.SnapshotComponent:input_type -> google.protobuf.StringValue
+	12, // 65: simsdkrpc.PluginService.RestoreComponent:input_type -> simsdkrpc.ComponentSnapshot
+	3,  // 66: simsdkrpc.PluginService.GetManifest:output_type -> simsdkrpc.ManifestResponse
+	11, // 67: simsdkrpc.PluginService.CreateComponentInstance:output_type -> simsdkrpc.CreateComponentResponse
+	48, // 68: simsdkrpc.PluginService.DestroyComponentInstance:output_type -> google.protobuf.Empty
+	14, // 69: simsdkrpc.PluginService.HandleMessage:output_type -> simsdkrpc.MessageResponse
+	17, // 70: simsdkrpc.PluginService.HandleMessages:output_type -> simsdkrpc.MessageBatchResponse
+	23, // 71: simsdkrpc.PluginService.MessageStream:output_type -> simsdkrpc.PluginMessageEnvelope
+	19, // 72: simsdkrpc.PluginService.InvokeControlFunction:output_type -> simsdkrpc.ControlFunctionResponse
+	22, // 73: simsdkrpc.PluginService.ListComponents:output_type -> simsdkrpc.ListComponentsResponse
+	20, // 74: simsdkrpc.PluginService.GetComponentStatus:output_type -> simsdkrpc.ComponentStatus
+	48, // 75: simsdkrpc.PluginService.PauseComponent:output_type -> google.protobuf.Empty
+	48, // 76: simsdkrpc.PluginService.ResumeComponent:output_type -> google.protobuf.Empty
+	48, // 77: simsdkrpc.PluginService.ResetComponent:output_type -> google.protobuf.Empty
+	12, // 78: simsdkrpc.PluginService.SnapshotComponent:output_type -> simsdkrpc.ComponentSnapshot
+	48, // 79: simsdkrpc.PluginService.RestoreComponent:output_type -> google.protobuf.Empty
+	66, // [66:80] is the sub-list for method output_type
+	52, // [52:66] is the sub-list for method input_type
+	52, // [52:52] is the sub-list for extension type_name
+	52, // [52:52] is the sub-list for extension extendee
+	0,  // [0:52] is the sub-list for field type_name
 }
 
 func init() { file_plugin_proto_init() }
@@ -1449,20 +3050,34 @@ func file_plugin_proto_init() {
 	if File_plugin_proto != nil {
 		return
 	}
-	file_plugin_proto_msgTypes[12].OneofWrappers = []any{
+	file_plugin_proto_msgTypes[14].OneofWrappers = []any{
+		(*MessageResult_Ack)(nil),
+		(*MessageResult_Nak)(nil),
+	}
+	file_plugin_proto_msgTypes[21].OneofWrappers = []any{
 		(*PluginMessageEnvelope_SimMessage)(nil),
 		(*PluginMessageEnvelope_Ack)(nil),
 		(*PluginMessageEnvelope_Nak)(nil),
 		(*PluginMessageEnvelope_Init)(nil),
 		(*PluginMessageEnvelope_Shutdown)(nil),
+		(*PluginMessageEnvelope_Credit)(nil),
+		(*PluginMessageEnvelope_Heartbeat)(nil),
+		(*PluginMessageEnvelope_ClockSync)(nil),
+		(*PluginMessageEnvelope_StepBegin)(nil),
+		(*PluginMessageEnvelope_StepComplete)(nil),
+		(*PluginMessageEnvelope_Lookahead)(nil),
+		(*PluginMessageEnvelope_Batch)(nil),
+		(*PluginMessageEnvelope_BatchResult)(nil),
+		(*PluginMessageEnvelope_Chunk)(nil),
+		(*PluginMessageEnvelope_Compression)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_proto_rawDesc), len(file_plugin_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   21,
+			NumEnums:      2,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
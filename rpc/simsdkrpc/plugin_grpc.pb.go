@@ -25,7 +25,16 @@ const (
 	PluginService_CreateComponentInstance_FullMethodName  = "/simsdkrpc.PluginService/CreateComponentInstance"
 	PluginService_DestroyComponentInstance_FullMethodName = "/simsdkrpc.PluginService/DestroyComponentInstance"
 	PluginService_HandleMessage_FullMethodName            = "/simsdkrpc.PluginService/HandleMessage"
+	PluginService_HandleMessages_FullMethodName           = "/simsdkrpc.PluginService/HandleMessages"
 	PluginService_MessageStream_FullMethodName            = "/simsdkrpc.PluginService/MessageStream"
+	PluginService_InvokeControlFunction_FullMethodName    = "/simsdkrpc.PluginService/InvokeControlFunction"
+	PluginService_ListComponents_FullMethodName           = "/simsdkrpc.PluginService/ListComponents"
+	PluginService_GetComponentStatus_FullMethodName       = "/simsdkrpc.PluginService/GetComponentStatus"
+	PluginService_PauseComponent_FullMethodName           = "/simsdkrpc.PluginService/PauseComponent"
+	PluginService_ResumeComponent_FullMethodName          = "/simsdkrpc.PluginService/ResumeComponent"
+	PluginService_ResetComponent_FullMethodName           = "/simsdkrpc.PluginService/ResetComponent"
+	PluginService_SnapshotComponent_FullMethodName        = "/simsdkrpc.PluginService/SnapshotComponent"
+	PluginService_RestoreComponent_FullMethodName         = "/simsdkrpc.PluginService/RestoreComponent"
 )
 
 // PluginServiceClient is the client API for PluginService service.
@@ -36,7 +45,16 @@ type PluginServiceClient interface {
 	CreateComponentInstance(ctx context.Context, in *CreateComponentRequest, opts ...grpc.CallOption) (*CreateComponentResponse, error)
 	DestroyComponentInstance(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	HandleMessage(ctx context.Context, in *SimMessage, opts ...grpc.CallOption) (*MessageResponse, error)
+	HandleMessages(ctx context.Context, in *MessageBatch, opts ...grpc.CallOption) (*MessageBatchResponse, error)
 	MessageStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PluginMessageEnvelope, PluginMessageEnvelope], error)
+	InvokeControlFunction(ctx context.Context, in *ControlFunctionRequest, opts ...grpc.CallOption) (*ControlFunctionResponse, error)
+	ListComponents(ctx context.Context, in *ListComponentsRequest, opts ...grpc.CallOption) (*ListComponentsResponse, error)
+	GetComponentStatus(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*ComponentStatus, error)
+	PauseComponent(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ResumeComponent(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ResetComponent(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SnapshotComponent(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*ComponentSnapshot, error)
+	RestoreComponent(ctx context.Context, in *ComponentSnapshot, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type pluginServiceClient struct {
@@ -87,6 +105,16 @@ func (c *pluginServiceClient) HandleMessage(ctx context.Context, in *SimMessage,
 	return out, nil
 }
 
+func (c *pluginServiceClient) HandleMessages(ctx context.Context, in *MessageBatch, opts ...grpc.CallOption) (*MessageBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MessageBatchResponse)
+	err := c.cc.Invoke(ctx, PluginService_HandleMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *pluginServiceClient) MessageStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PluginMessageEnvelope, PluginMessageEnvelope], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &PluginService_ServiceDesc.Streams[0], PluginService_MessageStream_FullMethodName, cOpts...)
@@ -100,6 +128,86 @@ func (c *pluginServiceClient) MessageStream(ctx context.Context, opts ...grpc.Ca
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PluginService_MessageStreamClient = grpc.BidiStreamingClient[PluginMessageEnvelope, PluginMessageEnvelope]
 
+func (c *pluginServiceClient) InvokeControlFunction(ctx context.Context, in *ControlFunctionRequest, opts ...grpc.CallOption) (*ControlFunctionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ControlFunctionResponse)
+	err := c.cc.Invoke(ctx, PluginService_InvokeControlFunction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) ListComponents(ctx context.Context, in *ListComponentsRequest, opts ...grpc.CallOption) (*ListComponentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListComponentsResponse)
+	err := c.cc.Invoke(ctx, PluginService_ListComponents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) GetComponentStatus(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*ComponentStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComponentStatus)
+	err := c.cc.Invoke(ctx, PluginService_GetComponentStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) PauseComponent(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PluginService_PauseComponent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) ResumeComponent(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PluginService_ResumeComponent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) ResetComponent(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PluginService_ResetComponent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) SnapshotComponent(ctx context.Context, in *wrapperspb.StringValue, opts ...grpc.CallOption) (*ComponentSnapshot, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComponentSnapshot)
+	err := c.cc.Invoke(ctx, PluginService_SnapshotComponent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pluginServiceClient) RestoreComponent(ctx context.Context, in *ComponentSnapshot, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PluginService_RestoreComponent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PluginServiceServer is the server API for PluginService service.
 // All implementations must embed UnimplementedPluginServiceServer
 // for forward compatibility.
@@ -108,7 +216,16 @@ type PluginServiceServer interface {
 	CreateComponentInstance(context.Context, *CreateComponentRequest) (*CreateComponentResponse, error)
 	DestroyComponentInstance(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error)
 	HandleMessage(context.Context, *SimMessage) (*MessageResponse, error)
+	HandleMessages(context.Context, *MessageBatch) (*MessageBatchResponse, error)
 	MessageStream(grpc.BidiStreamingServer[PluginMessageEnvelope, PluginMessageEnvelope]) error
+	InvokeControlFunction(context.Context, *ControlFunctionRequest) (*ControlFunctionResponse, error)
+	ListComponents(context.Context, *ListComponentsRequest) (*ListComponentsResponse, error)
+	GetComponentStatus(context.Context, *wrapperspb.StringValue) (*ComponentStatus, error)
+	PauseComponent(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error)
+	ResumeComponent(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error)
+	ResetComponent(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error)
+	SnapshotComponent(context.Context, *wrapperspb.StringValue) (*ComponentSnapshot, error)
+	RestoreComponent(context.Context, *ComponentSnapshot) (*emptypb.Empty, error)
 	mustEmbedUnimplementedPluginServiceServer()
 }
 
@@ -131,9 +248,36 @@ func (UnimplementedPluginServiceServer) DestroyComponentInstance(context.Context
 func (UnimplementedPluginServiceServer) HandleMessage(context.Context, *SimMessage) (*MessageResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HandleMessage not implemented")
 }
+func (UnimplementedPluginServiceServer) HandleMessages(context.Context, *MessageBatch) (*MessageBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandleMessages not implemented")
+}
 func (UnimplementedPluginServiceServer) MessageStream(grpc.BidiStreamingServer[PluginMessageEnvelope, PluginMessageEnvelope]) error {
 	return status.Errorf(codes.Unimplemented, "method MessageStream not implemented")
 }
+func (UnimplementedPluginServiceServer) InvokeControlFunction(context.Context, *ControlFunctionRequest) (*ControlFunctionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InvokeControlFunction not implemented")
+}
+func (UnimplementedPluginServiceServer) ListComponents(context.Context, *ListComponentsRequest) (*ListComponentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListComponents not implemented")
+}
+func (UnimplementedPluginServiceServer) GetComponentStatus(context.Context, *wrapperspb.StringValue) (*ComponentStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetComponentStatus not implemented")
+}
+func (UnimplementedPluginServiceServer) PauseComponent(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseComponent not implemented")
+}
+func (UnimplementedPluginServiceServer) ResumeComponent(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeComponent not implemented")
+}
+func (UnimplementedPluginServiceServer) ResetComponent(context.Context, *wrapperspb.StringValue) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetComponent not implemented")
+}
+func (UnimplementedPluginServiceServer) SnapshotComponent(context.Context, *wrapperspb.StringValue) (*ComponentSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotComponent not implemented")
+}
+func (UnimplementedPluginServiceServer) RestoreComponent(context.Context, *ComponentSnapshot) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreComponent not implemented")
+}
 func (UnimplementedPluginServiceServer) mustEmbedUnimplementedPluginServiceServer() {}
 func (UnimplementedPluginServiceServer) testEmbeddedByValue()                       {}
 
@@ -227,6 +371,24 @@ func _PluginService_HandleMessage_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PluginService_HandleMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MessageBatch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).HandleMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PluginService_HandleMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).HandleMessages(ctx, req.(*MessageBatch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PluginService_MessageStream_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(PluginServiceServer).MessageStream(&grpc.GenericServerStream[PluginMessageEnvelope, PluginMessageEnvelope]{ServerStream: stream})
 }
@@ -234,6 +396,150 @@ func _PluginService_MessageStream_Handler(srv interface{}, stream grpc.ServerStr
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type PluginService_MessageStreamServer = grpc.BidiStreamingServer[PluginMessageEnvelope, PluginMessageEnvelope]
 
+func _PluginService_InvokeControlFunction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ControlFunctionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).InvokeControlFunction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PluginService_InvokeControlFunction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).InvokeControlFunction(ctx, req.(*ControlFunctionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_ListComponents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListComponentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).ListComponents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PluginService_ListComponents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).ListComponents(ctx, req.(*ListComponentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_GetComponentStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(wrapperspb.StringValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).GetComponentStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PluginService_GetComponentStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).GetComponentStatus(ctx, req.(*wrapperspb.StringValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_PauseComponent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(wrapperspb.StringValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).PauseComponent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PluginService_PauseComponent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).PauseComponent(ctx, req.(*wrapperspb.StringValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_ResumeComponent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(wrapperspb.StringValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).ResumeComponent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PluginService_ResumeComponent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).ResumeComponent(ctx, req.(*wrapperspb.StringValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_ResetComponent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(wrapperspb.StringValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).ResetComponent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PluginService_ResetComponent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).ResetComponent(ctx, req.(*wrapperspb.StringValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_SnapshotComponent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(wrapperspb.StringValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).SnapshotComponent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PluginService_SnapshotComponent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).SnapshotComponent(ctx, req.(*wrapperspb.StringValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PluginService_RestoreComponent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComponentSnapshot)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PluginServiceServer).RestoreComponent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PluginService_RestoreComponent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PluginServiceServer).RestoreComponent(ctx, req.(*ComponentSnapshot))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PluginService_ServiceDesc is the grpc.ServiceDesc for PluginService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -257,6 +563,42 @@ var PluginService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "HandleMessage",
 			Handler:    _PluginService_HandleMessage_Handler,
 		},
+		{
+			MethodName: "HandleMessages",
+			Handler:    _PluginService_HandleMessages_Handler,
+		},
+		{
+			MethodName: "InvokeControlFunction",
+			Handler:    _PluginService_InvokeControlFunction_Handler,
+		},
+		{
+			MethodName: "ListComponents",
+			Handler:    _PluginService_ListComponents_Handler,
+		},
+		{
+			MethodName: "GetComponentStatus",
+			Handler:    _PluginService_GetComponentStatus_Handler,
+		},
+		{
+			MethodName: "PauseComponent",
+			Handler:    _PluginService_PauseComponent_Handler,
+		},
+		{
+			MethodName: "ResumeComponent",
+			Handler:    _PluginService_ResumeComponent_Handler,
+		},
+		{
+			MethodName: "ResetComponent",
+			Handler:    _PluginService_ResetComponent_Handler,
+		},
+		{
+			MethodName: "SnapshotComponent",
+			Handler:    _PluginService_SnapshotComponent_Handler,
+		},
+		{
+			MethodName: "RestoreComponent",
+			Handler:    _PluginService_RestoreComponent_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
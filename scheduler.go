@@ -0,0 +1,227 @@
+package simsdk
+
+import (
+	"container/heap"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ScheduledSender is implemented by the SDK's stream senders. SendAt and
+// SendAfter hand a message to the stream's scheduler for delivery at a future
+// simulation time.
+type ScheduledSender interface {
+	StreamSender
+	SendAt(simTime time.Time, msg *SimMessage) (*ScheduledMessage, error)
+	SendAfter(d time.Duration, msg *SimMessage) (*ScheduledMessage, error)
+}
+
+// ScheduledMessage is a handle to a message waiting in a Scheduler.
+type ScheduledMessage struct {
+	at    time.Time
+	seq   uint64
+	msg   *SimMessage
+	send  func(*SimMessage) error
+	sched *Scheduler
+	index int // position in the heap, -1 once delivered or canceled
+}
+
+// At returns the simulation time the message is due.
+func (m *ScheduledMessage) At() time.Time {
+	return m.at
+}
+
+// Cancel removes the message from the scheduler. It reports false if the
+// message was already delivered or canceled.
+func (m *ScheduledMessage) Cancel() bool {
+	s := m.sched
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m.index < 0 {
+		return false
+	}
+	heap.Remove(&s.items, m.index)
+	return true
+}
+
+// Scheduler delivers messages at future simulation times read from a Clock.
+// Messages due at the same time are delivered in the order they were
+// scheduled. Messages without a SimTime are stamped with their due time.
+type Scheduler struct {
+	clock Clock
+	log   *slog.Logger
+	runMu sync.Mutex // serializes RunDue so deliveries never interleave
+
+	mu      sync.Mutex
+	items   scheduleHeap
+	seq     uint64
+	stopped bool
+
+	changed chan struct{} // signalled when the earliest deadline moves
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewScheduler returns a running scheduler driven by clock. Call Stop to
+// release it; pending messages are then dropped.
+func NewScheduler(clock Clock) *Scheduler {
+	return newScheduler(clock, slog.Default())
+}
+
+func newScheduler(clock Clock, logger *slog.Logger) *Scheduler {
+	s := &Scheduler{
+		clock:   clock,
+		log:     logger,
+		changed: make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// At schedules msg for delivery through send at simulation time t. A time
+// that has already passed delivers on the next run.
+func (s *Scheduler) At(t time.Time, msg *SimMessage, send func(*SimMessage) error) (*ScheduledMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return nil, ErrStreamClosed
+	}
+	s.seq++
+	m := &ScheduledMessage{at: t, seq: s.seq, msg: msg, send: send, sched: s}
+	heap.Push(&s.items, m)
+	if m.index == 0 {
+		select {
+		case s.changed <- struct{}{}:
+		default:
+		}
+	}
+	return m, nil
+}
+
+// After schedules msg for delivery through send once d of simulation time has passed.
+func (s *Scheduler) After(d time.Duration, msg *SimMessage, send func(*SimMessage) error) (*ScheduledMessage, error) {
+	return s.At(s.clock.Now().Add(d), msg, send)
+}
+
+// Pending returns the number of messages waiting for delivery.
+func (s *Scheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+// RunDue delivers every message due at the clock's current time, in order,
+// before returning. The scheduler calls it on its own as time passes; call it
+// directly to deliver synchronously, e.g. after advancing a ManualClock.
+func (s *Scheduler) RunDue() {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	now := s.clock.Now()
+	var due []*ScheduledMessage
+	s.mu.Lock()
+	for len(s.items) > 0 && !s.items[0].at.After(now) {
+		due = append(due, heap.Pop(&s.items).(*ScheduledMessage))
+	}
+	s.mu.Unlock()
+
+	for _, m := range due {
+		if m.msg.SimTime.IsZero() {
+			m.msg.SimTime = m.at
+		}
+		if err := m.send(m.msg); err != nil {
+			s.log.Warn("Scheduler: failed to deliver message", append(simMessageAttrs(m.msg), slog.Any("error", err))...)
+		}
+	}
+}
+
+// Stop stops the scheduler and drops pending messages.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.stopped = true
+	for _, m := range s.items {
+		m.index = -1
+	}
+	s.items = nil
+	s.mu.Unlock()
+	close(s.stop)
+	<-s.done
+}
+
+// run waits on the clock for the earliest deadline. It keeps one wait at a
+// time, replacing it only when the earliest deadline moves.
+func (s *Scheduler) run() {
+	defer close(s.done)
+	var (
+		wake   <-chan time.Time
+		wakeAt time.Time
+	)
+	for {
+		s.mu.Lock()
+		if len(s.items) == 0 {
+			s.cancelWait(wake)
+			wake = nil
+		} else if at := s.items[0].at; wake == nil || !at.Equal(wakeAt) {
+			s.cancelWait(wake)
+			wake, wakeAt = s.clock.After(at.Sub(s.clock.Now())), at
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-wake:
+			wake = nil
+			s.RunDue()
+		case <-s.changed:
+		case <-s.stop:
+			s.cancelWait(wake)
+			return
+		}
+	}
+}
+
+// cancelWait drops a superseded wait from clocks that support it.
+func (s *Scheduler) cancelWait(wake <-chan time.Time) {
+	if c, ok := s.clock.(waitCanceler); ok && wake != nil {
+		c.cancelAfter(wake)
+	}
+}
+
+// scheduleHeap orders messages by due time, then by scheduling order.
+type scheduleHeap []*ScheduledMessage
+
+func (h scheduleHeap) Len() int { return len(h) }
+
+func (h scheduleHeap) Less(i, j int) bool {
+	if h[i].at.Equal(h[j].at) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].at.Before(h[j].at)
+}
+
+func (h scheduleHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *scheduleHeap) Push(x any) {
+	m := x.(*ScheduledMessage)
+	m.index = len(*h)
+	*h = append(*h, m)
+}
+
+func (h *scheduleHeap) Pop() any {
+	old := *h
+	n := len(old)
+	m := old[n-1]
+	old[n-1] = nil
+	m.index = -1
+	*h = old[:n-1]
+	return m
+}
@@ -0,0 +1,158 @@
+package simsdk
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// collector records delivered messages.
+type collector struct {
+	mu   sync.Mutex
+	msgs []*SimMessage
+}
+
+func (c *collector) send(m *SimMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.msgs = append(c.msgs, m)
+	return nil
+}
+
+func (c *collector) ids() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var ids []string
+	for _, m := range c.msgs {
+		ids = append(ids, m.MessageID)
+	}
+	return ids
+}
+
+func TestScheduler_DeliversInTimeThenScheduleOrder(t *testing.T) {
+	clock := NewManualClock(epoch)
+	s := NewScheduler(clock)
+	defer s.Stop()
+	var out collector
+
+	_, err := s.After(2*time.Second, &SimMessage{MessageID: "late"}, out.send)
+	require.NoError(t, err)
+	_, err = s.After(time.Second, &SimMessage{MessageID: "first"}, out.send)
+	require.NoError(t, err)
+	_, err = s.At(epoch.Add(time.Second), &SimMessage{MessageID: "second"}, out.send)
+	require.NoError(t, err)
+
+	clock.Advance(500 * time.Millisecond)
+	s.RunDue()
+	require.Empty(t, out.ids())
+
+	clock.Advance(500 * time.Millisecond)
+	s.RunDue()
+	require.Equal(t, []string{"first", "second"}, out.ids())
+	require.Equal(t, epoch.Add(time.Second), out.msgs[0].SimTime)
+	require.Equal(t, 1, s.Pending())
+}
+
+func TestScheduler_KeepsOneClockWait(t *testing.T) {
+	clock := NewManualClock(epoch)
+	s := NewScheduler(clock)
+	var out collector
+
+	for i := 5; i > 0; i-- {
+		_, err := s.After(time.Duration(i)*time.Second, &SimMessage{MessageID: "m"}, out.send)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool { return clock.Waiters() == 1 }, time.Second, time.Millisecond)
+	}
+
+	s.Stop()
+	require.Zero(t, clock.Waiters())
+}
+
+func TestScheduler_CancelAndBackgroundDelivery(t *testing.T) {
+	clock := NewManualClock(epoch)
+	s := NewScheduler(clock)
+	defer s.Stop()
+	var out collector
+
+	canceled, err := s.After(time.Second, &SimMessage{MessageID: "canceled"}, out.send)
+	require.NoError(t, err)
+	_, err = s.After(time.Second, &SimMessage{MessageID: "kept"}, out.send)
+	require.NoError(t, err)
+
+	require.True(t, canceled.Cancel())
+	require.False(t, canceled.Cancel())
+
+	require.Eventually(t, func() bool { return clock.Waiters() > 0 }, time.Second, time.Millisecond)
+	clock.Advance(time.Second)
+	require.Eventually(t, func() bool { return len(out.ids()) == 1 }, time.Second, time.Millisecond)
+	require.Equal(t, []string{"kept"}, out.ids())
+}
+
+func TestScheduler_StopDropsPending(t *testing.T) {
+	s := NewScheduler(NewManualClock(epoch))
+	m, err := s.After(time.Second, &SimMessage{MessageID: "m"}, (&collector{}).send)
+	require.NoError(t, err)
+	s.Stop()
+
+	require.False(t, m.Cancel())
+	_, err = s.After(time.Second, &SimMessage{}, (&collector{}).send)
+	require.ErrorIs(t, err, ErrStreamClosed)
+}
+
+// delayingHandler returns a response due one second of sim time later.
+type delayingHandler struct {
+	recordingHandler
+	clock Clock
+}
+
+func (h *delayingHandler) SetClock(c Clock) { h.clock = c }
+
+func (h *delayingHandler) OnSimMessage(msg *SimMessage) ([]*SimMessage, error) {
+	return []*SimMessage{{MessageID: "delayed-" + msg.MessageID, SimTime: h.clock.Now().Add(time.Second)}}, nil
+}
+
+func TestServeStream_FutureResponsesWaitForSimTime(t *testing.T) {
+	clock := NewSimClock(epoch)
+	clock.Sync(epoch, time.Time{}, 1, true)
+	handler := &delayingHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		simMessageEnvelope("m1", "a"),
+		stepBeginEnvelope(1, epoch.Add(500*time.Millisecond), 500*time.Millisecond),
+		stepBeginEnvelope(2, epoch.Add(time.Second), 500*time.Millisecond),
+	}}
+
+	require.NoError(t, ServeStream(handler, stream, WithClock(clock)))
+
+	var kinds []string
+	for _, env := range stream.sent {
+		switch {
+		case env.GetSimMessage() != nil:
+			kinds = append(kinds, env.GetSimMessage().MessageId)
+			require.Equal(t, epoch.Add(time.Second), env.GetSimMessage().SimTime.AsTime())
+		case env.GetStepComplete() != nil:
+			kinds = append(kinds, "complete")
+		}
+	}
+	require.Equal(t, []string{"complete", "delayed-m1", "complete"}, kinds)
+}
+
+func TestServeStream_FutureResponsesAreSentBeforeClockSync(t *testing.T) {
+	handler := &delayingHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		simMessageEnvelope("m1", "a"),
+	}}
+	require.NoError(t, ServeStream(handler, stream))
+
+	var sent []string
+	for _, env := range stream.sent {
+		if m := env.GetSimMessage(); m != nil {
+			sent = append(sent, m.MessageId)
+		}
+	}
+	require.Equal(t, []string{"delayed-m1"}, sent, "an unsynced clock holds nothing back")
+}
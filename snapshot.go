@@ -0,0 +1,130 @@
+package simsdk
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// ComponentSnapshot captures a component so it can be recreated later, in
+// this process or another. State is opaque to the core; Version identifies
+// its format so a plugin can still restore snapshots taken by older builds.
+type ComponentSnapshot struct {
+	ComponentID   string
+	CreateRequest CreateComponentRequest // Request the component was created with
+	Version       uint32
+	State         []byte
+	TakenAt       time.Time
+}
+
+// Snapshotter is implemented by plugins whose components can be snapshotted
+// and restored. RestoreComponent replaces any live component with the same ID.
+type Snapshotter interface {
+	SnapshotComponent(componentID string) (ComponentSnapshot, error)
+	RestoreComponent(snap ComponentSnapshot) error
+}
+
+func ToProtoComponentSnapshot(s ComponentSnapshot) *simsdkrpc.ComponentSnapshot {
+	return &simsdkrpc.ComponentSnapshot{
+		ComponentId: s.ComponentID,
+		CreateRequest: &simsdkrpc.CreateComponentRequest{
+			ComponentType: s.CreateRequest.ComponentType,
+			ComponentId:   s.CreateRequest.ComponentID,
+			Parameters:    s.CreateRequest.Parameters,
+		},
+		Version: s.Version,
+		State:   s.State,
+		TakenAt: toProtoTime(s.TakenAt),
+	}
+}
+
+func FromProtoComponentSnapshot(p *simsdkrpc.ComponentSnapshot) ComponentSnapshot {
+	s := ComponentSnapshot{
+		ComponentID: p.GetComponentId(),
+		Version:     p.GetVersion(),
+		State:       p.GetState(),
+		TakenAt:     fromProtoTime(p.GetTakenAt()),
+	}
+	if req := p.GetCreateRequest(); req != nil {
+		s.CreateRequest = fromProtoCreateComponentRequest(req)
+	}
+	return s
+}
+
+func (g *grpcAdapter) SnapshotComponent(ctx context.Context, id *wrapperspb.StringValue) (*simsdkrpc.ComponentSnapshot, error) {
+	sn, err := g.snapshotter(id.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	var snap ComponentSnapshot
+	err = g.lifecycleCall(ctx, "SnapshotComponent", id.GetValue(), nil, func() (err error) {
+		snap, err = sn.SnapshotComponent(id.GetValue())
+		return err
+	})
+	if err != nil {
+		return nil, ToGRPCError(err)
+	}
+	if snap.TakenAt.IsZero() {
+		snap.TakenAt = time.Now()
+	}
+	return ToProtoComponentSnapshot(snap), nil
+}
+
+func (g *grpcAdapter) RestoreComponent(ctx context.Context, p *simsdkrpc.ComponentSnapshot) (*emptypb.Empty, error) {
+	snap := FromProtoComponentSnapshot(p)
+	if snap.ComponentID == "" {
+		snap.ComponentID = snap.CreateRequest.ComponentID
+	}
+	if snap.ComponentID == "" {
+		return nil, ToGRPCError(NewError(ErrInvalidArgument, "", "snapshot has no component ID"))
+	}
+	sn, err := g.snapshotter(snap.ComponentID)
+	if err != nil {
+		return nil, err
+	}
+	err = g.lifecycleCall(ctx, "RestoreComponent", snap.ComponentID, snap, func() error {
+		return sn.RestoreComponent(snap)
+	})
+	if err != nil {
+		g.syncLifecycle(snap.ComponentID)
+		return nil, ToGRPCError(err)
+	}
+	g.lifecycle.Track(snap.ComponentID, ComponentRunning)
+	g.panics.clear(snap.ComponentID)
+	return &emptypb.Empty{}, nil
+}
+
+// syncLifecycle tracks the state the plugin reports for componentID after a
+// restore failed part way, e.g. once the live instance was destroyed, so that
+// pause and resume are checked against the state GetComponentStatus reports.
+// Plugins that do not report component status are left as tracked.
+func (g *grpcAdapter) syncLifecycle(componentID string) {
+	provider, ok := g.plugin.(ComponentStatusProvider)
+	if !ok {
+		return
+	}
+	var cs ComponentStatus
+	err := g.panics.run(&Call{Kind: LifecycleCall, Method: "GetComponentStatus", ComponentID: componentID}, func() (err error) {
+		cs, err = provider.GetComponentStatus(componentID)
+		return err
+	})
+	switch {
+	case err == nil:
+		g.lifecycle.Track(componentID, cs.State)
+	case errors.Is(err, ErrNotFound):
+		g.lifecycle.Forget(componentID)
+	}
+}
+
+func (g *grpcAdapter) snapshotter(componentID string) (Snapshotter, error) {
+	sn, ok := g.plugin.(Snapshotter)
+	if !ok {
+		return nil, ToGRPCError(NewError(ErrUnimplemented, componentID,
+			"plugin %q does not support snapshot/restore", g.plugin.GetManifest().Name))
+	}
+	return sn, nil
+}
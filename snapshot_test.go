@@ -0,0 +1,129 @@
+package simsdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// snapshotPlugin keeps one counter per component as its state.
+type snapshotPlugin struct {
+	dummyPlugin
+	requests map[string]CreateComponentRequest
+	counters map[string]byte
+}
+
+func (p *snapshotPlugin) CreateComponentInstance(req CreateComponentRequest) error {
+	p.requests[req.ComponentID] = req
+	p.counters[req.ComponentID] = 0
+	return nil
+}
+
+func (p *snapshotPlugin) SnapshotComponent(id string) (ComponentSnapshot, error) {
+	req, ok := p.requests[id]
+	if !ok {
+		return ComponentSnapshot{}, NewError(ErrNotFound, id, "no component %q", id)
+	}
+	return ComponentSnapshot{ComponentID: id, CreateRequest: req, Version: 1, State: []byte{p.counters[id]}}, nil
+}
+
+func (p *snapshotPlugin) RestoreComponent(snap ComponentSnapshot) error {
+	if snap.Version != 1 {
+		return NewError(ErrInvalidArgument, snap.ComponentID, "unknown snapshot version %d", snap.Version)
+	}
+	p.requests[snap.ComponentID] = snap.CreateRequest
+	p.counters[snap.ComponentID] = snap.State[0]
+	return nil
+}
+
+func TestGRPCAdapter_SnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	plugin := &snapshotPlugin{requests: map[string]CreateComponentRequest{}, counters: map[string]byte{}}
+	adapter := NewGRPCAdapter(plugin)
+
+	_, err := adapter.SnapshotComponent(ctx, wrapperspb.String("c1"))
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = adapter.CreateComponentInstance(ctx, &simsdkrpc.CreateComponentRequest{
+		ComponentId: "c1", ComponentType: "loco", Parameters: map[string]string{"speed": "3"},
+	})
+	require.NoError(t, err)
+	plugin.counters["c1"] = 7
+
+	before := time.Now()
+	snap, err := adapter.SnapshotComponent(ctx, wrapperspb.String("c1"))
+	require.NoError(t, err)
+	require.Equal(t, "loco", snap.CreateRequest.GetComponentType())
+	require.Equal(t, "3", snap.CreateRequest.GetParameters()["speed"])
+	require.False(t, snap.TakenAt.AsTime().Before(before), "TakenAt is stamped when the plugin leaves it unset")
+
+	// Restore under a new ID, as a core migrating the component would.
+	snap.ComponentId = "c2"
+	_, err = adapter.RestoreComponent(ctx, snap)
+	require.NoError(t, err)
+	require.Equal(t, byte(7), plugin.counters["c2"])
+	_, err = adapter.PauseComponent(ctx, wrapperspb.String("c2"))
+	require.Equal(t, codes.Unimplemented, status.Code(err), "restored component is tracked, so only the lifecycle support is missing")
+
+	snap.Version = 9
+	_, err = adapter.RestoreComponent(ctx, snap)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCAdapter_SnapshotUnimplemented(t *testing.T) {
+	adapter := NewGRPCAdapter(&dummyPlugin{})
+	_, err := adapter.SnapshotComponent(context.Background(), wrapperspb.String("c1"))
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+	_, err = adapter.RestoreComponent(context.Background(), &simsdkrpc.ComponentSnapshot{ComponentId: "c1"})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+// brokenRestorePlugin loses its component when a restore fails, as the
+// transport plugins do when the replacement cannot be created.
+type brokenRestorePlugin struct {
+	dummyPlugin
+	state ComponentState
+}
+
+func (p *brokenRestorePlugin) SnapshotComponent(id string) (ComponentSnapshot, error) {
+	return ComponentSnapshot{ComponentID: id}, nil
+}
+
+func (p *brokenRestorePlugin) RestoreComponent(snap ComponentSnapshot) error {
+	p.state = ComponentStopped
+	return NewError(ErrUnavailable, snap.ComponentID, "cannot recreate %q", snap.ComponentID)
+}
+
+func (p *brokenRestorePlugin) ListComponents() []ComponentStatus { return nil }
+
+func (p *brokenRestorePlugin) GetComponentStatus(id string) (ComponentStatus, error) {
+	return ComponentStatus{ComponentID: id, State: p.state}, nil
+}
+
+func (p *brokenRestorePlugin) PauseComponent(string) error  { return nil }
+func (p *brokenRestorePlugin) ResumeComponent(string) error { return nil }
+func (p *brokenRestorePlugin) ResetComponent(string) error  { p.state = ComponentRunning; return nil }
+
+func TestGRPCAdapter_FailedRestoreTracksPluginState(t *testing.T) {
+	ctx := context.Background()
+	plugin := &brokenRestorePlugin{state: ComponentRunning}
+	adapter := NewGRPCAdapter(plugin)
+	_, err := adapter.CreateComponentInstance(ctx, &simsdkrpc.CreateComponentRequest{ComponentId: "c1"})
+	require.NoError(t, err)
+
+	_, err = adapter.RestoreComponent(ctx, &simsdkrpc.ComponentSnapshot{ComponentId: "c1"})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	_, err = adapter.PauseComponent(ctx, wrapperspb.String("c1"))
+	require.Equal(t, codes.FailedPrecondition, status.Code(err), "the component is tracked as stopped, as its status reports")
+	_, err = adapter.ResetComponent(ctx, wrapperspb.String("c1"))
+	require.NoError(t, err)
+	_, err = adapter.PauseComponent(ctx, wrapperspb.String("c1"))
+	require.NoError(t, err)
+}
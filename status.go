@@ -0,0 +1,33 @@
+package simsdk
+
+import "time"
+
+// ComponentState is the lifecycle state of a component instance.
+type ComponentState string
+
+const (
+	ComponentStateUnspecified ComponentState = ""
+	ComponentCreated          ComponentState = "created" // Instantiated but not yet started
+	ComponentRunning          ComponentState = "running" // Started and processing messages
+	ComponentPaused           ComponentState = "paused"  // Configured but not processing messages
+	ComponentStopped          ComponentState = "stopped" // Stopped or failed to start
+)
+
+// ComponentStatus is a point-in-time view of a live component instance.
+type ComponentStatus struct {
+	ComponentID      string            `json:"componentId"`
+	ComponentType    string            `json:"componentType"`
+	State            ComponentState    `json:"state"`
+	StartTime        time.Time         `json:"startTime,omitempty"`
+	MessagesReceived uint64            `json:"messagesReceived"`
+	MessagesSent     uint64            `json:"messagesSent"`
+	LastError        string            `json:"lastError,omitempty"`
+	Fields           map[string]string `json:"fields,omitempty"` // Plugin-defined status fields
+}
+
+// ComponentStatusProvider is implemented by plugins that can report on the
+// component instances they currently host.
+type ComponentStatusProvider interface {
+	ListComponents() []ComponentStatus
+	GetComponentStatus(componentID string) (ComponentStatus, error)
+}
@@ -0,0 +1,79 @@
+package simsdk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type statusPlugin struct {
+	dummyPlugin
+	components []ComponentStatus
+}
+
+func (p *statusPlugin) ListComponents() []ComponentStatus { return p.components }
+
+func (p *statusPlugin) GetComponentStatus(id string) (ComponentStatus, error) {
+	for _, cs := range p.components {
+		if cs.ComponentID == id {
+			return cs, nil
+		}
+	}
+	return ComponentStatus{}, NewError(ErrNotFound, id, "no component %q", id)
+}
+
+func TestGRPCAdapter_ListComponents(t *testing.T) {
+	started := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	plugin := &statusPlugin{components: []ComponentStatus{
+		{ComponentID: "a", ComponentType: "loco", State: ComponentRunning, StartTime: started, MessagesReceived: 3},
+		{ComponentID: "b", ComponentType: "signal", State: ComponentStopped, LastError: "boom"},
+	}}
+	adapter := NewGRPCAdapter(plugin)
+
+	all, err := adapter.ListComponents(context.Background(), &simsdkrpc.ListComponentsRequest{})
+	require.NoError(t, err)
+	require.Len(t, all.Components, 2)
+
+	filtered, err := adapter.ListComponents(context.Background(), &simsdkrpc.ListComponentsRequest{ComponentType: "loco"})
+	require.NoError(t, err)
+	require.Len(t, filtered.Components, 1)
+	require.Equal(t, simsdkrpc.ComponentState_COMPONENT_STATE_RUNNING, filtered.Components[0].State)
+	require.Equal(t, started, filtered.Components[0].StartTime.AsTime())
+
+	got, err := adapter.GetComponentStatus(context.Background(), wrapperspb.String("b"))
+	require.NoError(t, err)
+	require.Equal(t, "boom", got.LastError)
+
+	_, err = adapter.GetComponentStatus(context.Background(), wrapperspb.String("zzz"))
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCAdapter_ComponentIntrospectionUnimplemented(t *testing.T) {
+	adapter := NewGRPCAdapter(&dummyPlugin{})
+
+	_, err := adapter.ListComponents(context.Background(), &simsdkrpc.ListComponentsRequest{})
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, err = adapter.GetComponentStatus(context.Background(), wrapperspb.String("a"))
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestComponentStatus_ProtoRoundTrip(t *testing.T) {
+	for _, state := range []ComponentState{ComponentStateUnspecified, ComponentCreated, ComponentRunning, ComponentPaused, ComponentStopped} {
+		in := ComponentStatus{
+			ComponentID:   "c1",
+			ComponentType: "t",
+			State:         state,
+			StartTime:     time.Unix(1700000000, 0).UTC(),
+			MessagesSent:  7,
+			Fields:        map[string]string{"k": "v"},
+		}
+		require.Equal(t, in, FromProtoComponentStatus(ToProtoComponentStatus(in)))
+	}
+}
@@ -0,0 +1,128 @@
+package simsdk
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// StepHandler is implemented by stream handlers that take part in lockstep
+// execution. OnStep is called once per step after every message for the step
+// has been handled, with the step's simulation time and length. A returned
+// error is reported to the core in the step's completion.
+type StepHandler interface {
+	OnStep(ctx context.Context, simTime time.Time, dt time.Duration) error
+}
+
+// LookaheadProvider is implemented by stream handlers that declare a
+// lookahead: the minimum simulation-time delay between the component's
+// current time and any message it sends. The SDK reports it to the core after
+// OnInit succeeds.
+type LookaheadProvider interface {
+	Lookahead() time.Duration
+}
+
+// startStep runs a PluginStepBegin off the receive loop, so the credits, acks
+// and naks that the step's sends wait for are still read. The receive loop
+// waits for the step before it handles anything else.
+func (s *streamSession) startStep(begin *simsdkrpc.PluginStepBegin) {
+	s.waitWorkers()
+	done := make(chan struct{})
+	s.step = done
+	go func() {
+		defer close(done)
+		s.runStep(begin)
+	}()
+}
+
+// waitStep waits for the running step, if any, to complete. It is only
+// called from the receive loop.
+func (s *streamSession) waitStep() {
+	if s.step != nil {
+		<-s.step
+		s.step = nil
+	}
+}
+
+// runStep handles a PluginStepBegin once the step's messages were handled: it
+// moves the clock to the step time, delivers scheduled messages that are due,
+// calls OnStep on every handler and reports completion. Sends made by the
+// scheduled messages and OnStep return before the completion is queued, so the
+// core sees the step's messages first, even when they waited for credits or
+// for room in the reliable window.
+func (s *streamSession) runStep(begin *simsdkrpc.PluginStepBegin) {
+	simTime := fromProtoTime(begin.GetSimTime())
+	dt := begin.GetDt().AsDuration()
+	s.clock.Sync(simTime, time.Time{}, s.clock.Scale(), true)
+	s.sched.RunDue()
+
+	errs := make(map[string]string)
+	for _, c := range s.stepHandlers() {
+		err := s.panics.check(c.id)
+		if err == nil {
+			err = s.panics.run(&Call{Kind: StreamCall, Method: "OnStep", ComponentID: c.id}, func() error {
+				return c.handler.OnStep(s.ctx, simTime, dt)
+			})
+		}
+		if err != nil {
+			s.log.Warn("OnStep failed", slog.String("component_id", c.id), slog.Uint64("step", begin.GetStep()), slog.Any("error", err))
+			errs[c.id] = err.Error()
+		}
+	}
+
+	_ = s.writer.enqueue(s.ctx, &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_StepComplete{
+			StepComplete: &simsdkrpc.PluginStepComplete{
+				Step:            begin.GetStep(),
+				ComponentErrors: errs,
+			},
+		},
+	}, false)
+}
+
+type stepTarget struct {
+	id      string
+	handler StepHandler
+}
+
+// stepHandlers returns the handlers to step, in component ID order so steps
+// are deterministic. A shared handler is stepped once.
+func (s *streamSession) stepHandlers() []stepTarget {
+	if s.shared != nil {
+		if h, ok := s.shared.(StepHandler); ok {
+			return []stepTarget{{handler: h}}
+		}
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var targets []stepTarget
+	for id, c := range s.components {
+		if h, ok := c.handler.(StepHandler); ok {
+			targets = append(targets, stepTarget{id: id, handler: h})
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].id < targets[j].id })
+	return targets
+}
+
+// declareLookahead reports a component's lookahead if its handler declares one.
+func (s *streamSession) declareLookahead(componentID string, handler StreamHandler) {
+	lp, ok := handler.(LookaheadProvider)
+	if !ok {
+		return
+	}
+	_ = s.writer.enqueue(s.ctx, &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_Lookahead{
+			Lookahead: &simsdkrpc.PluginLookahead{
+				ComponentId: componentID,
+				Lookahead:   durationpb.New(lp.Lookahead()),
+			},
+		},
+	}, false)
+}
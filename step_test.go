@@ -0,0 +1,163 @@
+package simsdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// steppingHandler records the order of messages and steps for one component.
+type steppingHandler struct {
+	recordingHandler
+	events  *[]string
+	mu      *sync.Mutex
+	failing bool
+}
+
+func (h *steppingHandler) OnSimMessage(msg *SimMessage) ([]*SimMessage, error) {
+	time.Sleep(5 * time.Millisecond)
+	h.mu.Lock()
+	*h.events = append(*h.events, "msg:"+msg.MessageID)
+	h.mu.Unlock()
+	return []*SimMessage{{MessageID: "reply-" + msg.MessageID}}, nil
+}
+
+func (h *steppingHandler) OnStep(_ context.Context, simTime time.Time, dt time.Duration) error {
+	h.mu.Lock()
+	*h.events = append(*h.events, "step:"+simTime.Format(time.RFC3339)+":"+dt.String())
+	h.mu.Unlock()
+	if h.failing {
+		return errors.New("diverged")
+	}
+	return nil
+}
+
+func (h *steppingHandler) Lookahead() time.Duration { return 50 * time.Millisecond }
+
+func stepBeginEnvelope(step uint64, simTime time.Time, dt time.Duration) *simsdkrpc.PluginMessageEnvelope {
+	return &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_StepBegin{StepBegin: &simsdkrpc.PluginStepBegin{
+			Step:    step,
+			SimTime: timestamppb.New(simTime),
+			Dt:      durationpb.New(dt),
+		}},
+	}
+}
+
+func TestServeStream_LockstepStep(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	handlers := map[string]bool{"a": false, "b": true}
+	var order []string
+	factory := func() StreamHandler {
+		id := []string{"a", "b"}[len(order)]
+		order = append(order, id)
+		return &steppingHandler{events: &events, mu: &mu, failing: handlers[id]}
+	}
+
+	clock := NewSimClock(time.Now())
+	stepTime := epoch.Add(time.Second)
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		initEnvelope("b"),
+		simMessageEnvelope("m1", "a"),
+		simMessageEnvelope("m2", "b"),
+		stepBeginEnvelope(1, stepTime, time.Second),
+	}}
+
+	require.NoError(t, ServeStreamMux(factory, stream, WithWorkers(2, 0), WithClock(clock)))
+
+	// both messages are handled before either component steps
+	require.ElementsMatch(t, []string{"msg:m1", "msg:m2"}, events[:2])
+	step := "step:" + stepTime.Format(time.RFC3339) + ":1s"
+	require.Equal(t, []string{step, step}, events[2:])
+	require.Equal(t, stepTime, clock.Now())
+	require.True(t, clock.Paused())
+
+	// the completion follows every reply and reports the failing component
+	var lookaheads []string
+	complete := -1
+	for i, env := range stream.sent {
+		switch {
+		case env.GetLookahead() != nil:
+			require.Equal(t, 50*time.Millisecond, env.GetLookahead().Lookahead.AsDuration())
+			lookaheads = append(lookaheads, env.GetLookahead().ComponentId)
+		case env.GetStepComplete() != nil:
+			complete = i
+			require.Equal(t, uint64(1), env.GetStepComplete().Step)
+			require.Equal(t, map[string]string{"b": "diverged"}, env.GetStepComplete().ComponentErrors)
+		case env.GetSimMessage() != nil:
+			require.Equal(t, -1, complete, "reply sent after step completion")
+		}
+	}
+	require.Equal(t, []string{"a", "b"}, lookaheads)
+	require.Equal(t, len(stream.sent)-1, complete)
+}
+
+// sendingStepper schedules a message for the step in OnInit and sends two
+// more from OnStep.
+type sendingStepper struct {
+	recordingHandler
+	stepTime time.Time
+}
+
+func (h *sendingStepper) OnInit(init *simsdkrpc.PluginInit) error {
+	_, err := h.sender.(ScheduledSender).SendAt(h.stepTime, &SimMessage{MessageID: "scheduled"})
+	return err
+}
+
+func (h *sendingStepper) OnStep(context.Context, time.Time, time.Duration) error {
+	for _, id := range []string{"step-1", "step-2"} {
+		if err := h.sender.Send(&SimMessage{MessageID: id}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestServeStream_StepSendsWaitForCredits(t *testing.T) {
+	stepTime := epoch.Add(time.Second)
+	handler := &sendingStepper{stepTime: stepTime}
+	stream := newChanStream()
+	stream.in <- &simsdkrpc.PluginMessageEnvelope{Content: &simsdkrpc.PluginMessageEnvelope_ClockSync{ClockSync: &simsdkrpc.PluginClockSync{
+		SimTime: timestamppb.New(epoch),
+		Paused:  true,
+	}}}
+	stream.in <- creditEnvelope(1)
+	stream.in <- initEnvelope("a")
+	// The step's three sends need three credits; the rest arrive after the
+	// step has begun and can only be read while it runs.
+	stream.in <- stepBeginEnvelope(1, stepTime, time.Second)
+	stream.in <- creditEnvelope(2)
+	close(stream.in)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ServeStream(handler, stream, WithFlowControl(FlowControlOptions{Window: 4}), WithClock(NewSimClock(time.Now())))
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("step sends blocked on credits deadlocked the stream")
+	}
+
+	var order []string
+	for _, env := range stream.sent {
+		if m := env.GetSimMessage(); m != nil {
+			order = append(order, m.MessageId)
+		}
+		if env.GetStepComplete() != nil {
+			order = append(order, "complete")
+			require.Empty(t, env.GetStepComplete().ComponentErrors)
+		}
+	}
+	require.Equal(t, []string{"scheduled", "step-1", "step-2", "complete"}, order)
+}
@@ -0,0 +1,841 @@
+package simsdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+)
+
+// StreamOption customizes ServeStream.
+type StreamOption func(*streamOptions)
+
+type streamOptions struct {
+	sendQueueSize  int
+	overflowPolicy OverflowPolicy
+	reliable       *ReliableOptions
+	flow           *FlowControlOptions
+	workers        int
+	maxPending     int
+	orderingKey    string
+	heartbeat      *HeartbeatOptions
+	clock          *SimClock
+	recorder       *Recorder
+	chunking       *ChunkingOptions // nil unless outbound chunking was enabled
+	compression    *CompressionOptions
+	middleware     []Middleware
+	quarantine     QuarantineOptions
+	panics         *panicTracker // shared by the adapter's streams; nil builds one from quarantine
+	logger         *slog.Logger
+	metrics        *sdkMetrics
+	tracer         Tracer
+}
+
+func defaultStreamOptions() streamOptions {
+	return streamOptions{
+		sendQueueSize:  DefaultSendQueueSize,
+		overflowPolicy: OverflowBlock,
+	}
+}
+
+// WithSendQueueSize sets the capacity of the outbound queue shared by the
+// stream's senders, responses and acks.
+func WithSendQueueSize(n int) StreamOption {
+	return func(o *streamOptions) { o.sendQueueSize = n }
+}
+
+// WithClock makes the stream drive the given clock from the core's clock syncs
+// instead of a clock of its own, e.g. to share one clock across streams.
+func WithClock(clock *SimClock) StreamOption {
+	return func(o *streamOptions) { o.clock = clock }
+}
+
+// WithOverflowPolicy sets what StreamSender.Send does when the outbound queue is full.
+func WithOverflowPolicy(p OverflowPolicy) StreamOption {
+	return func(o *streamOptions) { o.overflowPolicy = p }
+}
+
+var (
+	_ QueuedStreamSender         = (*grpcStreamSender)(nil)
+	_ ReliableStreamSender       = (*grpcStreamSender)(nil)
+	_ FlowControlledStreamSender = (*grpcStreamSender)(nil)
+	_ ScheduledSender            = (*grpcStreamSender)(nil)
+)
+
+// grpcStreamSender is the per-component StreamSender handed to handlers. It never
+// touches the gRPC stream directly; messages go through the stream's single
+// writer. Messages without a ComponentID are stamped with the sender's component.
+type grpcStreamSender struct {
+	writer      *streamWriter
+	reliable    *reliableTracker // nil unless reliable delivery is enabled
+	flow        *flowController  // nil unless flow control is enabled
+	clock       Clock
+	scheduler   *Scheduler
+	codec       *payloadCodec
+	componentID string
+	closed      atomic.Bool
+}
+
+func (s *grpcStreamSender) Send(msg *SimMessage) error {
+	return s.SendContext(context.Background(), msg)
+}
+
+// SendContext queues msg for delivery. With flow control it first waits for a
+// credit from the core. In reliable mode it waits for a slot in the in-flight
+// window; the delivery outcome is reported via OnOutcome. A message without
+// trace context of its own carries the one in ctx.
+func (s *grpcStreamSender) SendContext(ctx context.Context, msg *SimMessage) error {
+	if s.closed.Load() {
+		return ErrStreamClosed
+	}
+	release, err := s.acquireCredit(ctx)
+	if err != nil {
+		return err
+	}
+	env := s.envelope(withTraceContext(ctx, msg))
+	if s.reliable != nil {
+		_, err = s.reliable.send(ctx, env)
+	} else {
+		err = s.writer.enqueue(ctx, env, true)
+	}
+	if err != nil {
+		release(1)
+	}
+	return err
+}
+
+// SendReliable queues msg and returns a channel that receives its final
+// delivery outcome. It fails with ErrReliableDisabled unless the stream was
+// served with WithReliableDelivery.
+func (s *grpcStreamSender) SendReliable(ctx context.Context, msg *SimMessage) (<-chan DeliveryOutcome, error) {
+	if s.reliable == nil {
+		return nil, ErrReliableDisabled
+	}
+	if s.closed.Load() {
+		return nil, ErrStreamClosed
+	}
+	release, err := s.acquireCredit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	outcome, err := s.reliable.send(ctx, s.envelope(withTraceContext(ctx, msg)))
+	if err != nil {
+		release(1)
+	}
+	return outcome, err
+}
+
+// SendAt sends msg once the stream's clock reaches simTime. Messages due at
+// the same time are sent in the order they were scheduled.
+func (s *grpcStreamSender) SendAt(simTime time.Time, msg *SimMessage) (*ScheduledMessage, error) {
+	if s.closed.Load() {
+		return nil, ErrStreamClosed
+	}
+	return s.scheduler.At(simTime, msg, s.Send)
+}
+
+// SendAfter sends msg once d of simulation time has passed.
+func (s *grpcStreamSender) SendAfter(d time.Duration, msg *SimMessage) (*ScheduledMessage, error) {
+	if s.closed.Load() {
+		return nil, ErrStreamClosed
+	}
+	return s.scheduler.After(d, msg, s.Send)
+}
+
+// acquireCredit takes one outbound credit. The returned release gives back n
+// credits for messages that were not sent after all.
+func (s *grpcStreamSender) acquireCredit(ctx context.Context) (release func(n int), err error) {
+	if s.flow == nil {
+		return func(int) {}, nil
+	}
+	limited, err := s.flow.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return func(n int) { s.flow.release(n, limited) }, nil
+}
+
+func (s *grpcStreamSender) envelope(msg *SimMessage) *simsdkrpc.PluginMessageEnvelope {
+	return &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_SimMessage{SimMessage: s.protoMessage(msg)},
+	}
+}
+
+// protoMessage converts msg, stamping the sender's component, a message ID
+// when it has none, and the clock's times.
+func (s *grpcStreamSender) protoMessage(msg *SimMessage) *simsdkrpc.SimMessage {
+	out := ToProtoSimMessage(msg)
+	if out.ComponentId == "" {
+		out.ComponentId = s.componentID
+	}
+	if out.MessageId == "" {
+		out.MessageId = NewMessageID()
+	}
+	stampTimes(out, s.clock)
+	s.codec.encode(out)
+	return out
+}
+
+func (s *grpcStreamSender) QueueStats() SendQueueStats {
+	return s.writer.stats()
+}
+
+// FlowStats returns the stream's flow-control metrics, or zero values when flow
+// control is disabled.
+func (s *grpcStreamSender) FlowStats() FlowStats {
+	if s.flow == nil {
+		return FlowStats{}
+	}
+	return s.flow.stats()
+}
+
+func (s *grpcStreamSender) ComponentID() string {
+	return s.componentID
+}
+
+// ServeStream serves a MessageStream with a single handler shared by every
+// component initialized on the stream.
+func ServeStream(handler StreamHandler, stream simsdkrpc.PluginService_MessageStreamServer, opts ...StreamOption) error {
+	return newStreamSession(stream, func() StreamHandler { return handler }, true, opts).serve()
+}
+
+// ServeStreamMux serves a MessageStream that multiplexes many components. Each
+// PluginInit with a new ComponentId gets its own handler from factory and its own
+// StreamSender; inbound SimMessages are routed to the handler for their
+// ComponentId. A PluginShutdown with a ComponentId shuts down only that component.
+func ServeStreamMux(factory func() StreamHandler, stream simsdkrpc.PluginService_MessageStreamServer, opts ...StreamOption) error {
+	return newStreamSession(stream, factory, false, opts).serve()
+}
+
+// streamComponent is a component initialized on a stream.
+type streamComponent struct {
+	id      string
+	handler StreamHandler
+	sender  *grpcStreamSender
+}
+
+// streamSession holds the state of one MessageStream.
+type streamSession struct {
+	stream   simsdkrpc.PluginService_MessageStreamServer
+	ctx      context.Context
+	cancel   context.CancelFunc // ends ctx when serve returns or the peer is dead
+	writer   *streamWriter
+	reliable *reliableTracker
+	flow     *flowController
+	pool     *orderedPool // nil when messages are handled inline
+	inflight *inflightTracker
+	beats    *heartbeatMonitor // nil unless heartbeats are enabled
+	step     chan struct{}     // closed when the running step completes; nil when none runs
+	clock    *SimClock
+	sched    *Scheduler
+	chunks   *chunkAssembler
+	codec    *payloadCodec
+	chain    Middleware // nil without middleware
+	panics   *panicTracker
+	log      *slog.Logger
+	metrics  *sdkMetrics
+	tracer   Tracer        // nil uses DefaultTracer
+	done     chan struct{} // closed when serve returns
+	opts     streamOptions
+	factory  func() StreamHandler
+	shared   StreamHandler // set when every component uses the same handler
+	// sharedInit records that the shared handler was initialized, so it is
+	// shut down when the stream ends even if its components were shut down
+	// one by one.
+	sharedInit bool
+
+	mu         sync.Mutex
+	components map[string]*streamComponent
+	workerErr  error // first error returned by a pooled message
+}
+
+func newStreamSession(stream simsdkrpc.PluginService_MessageStreamServer, factory func() StreamHandler, shared bool, opts []StreamOption) *streamSession {
+	o := defaultStreamOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.recorder != nil {
+		stream = o.recorder.WrapStream(stream)
+	}
+	s := &streamSession{
+		stream:     stream,
+		opts:       o,
+		factory:    factory,
+		inflight:   newInflightTracker(),
+		done:       make(chan struct{}),
+		components: make(map[string]*streamComponent),
+		log:        loggerOr(o.logger),
+		metrics:    o.metrics,
+		tracer:     o.tracer,
+	}
+	s.ctx, s.cancel = context.WithCancel(extractGRPCTraceContext(stream.Context()))
+	if shared {
+		s.shared = factory()
+	}
+	var chunking ChunkingOptions
+	if o.chunking != nil {
+		chunking = *o.chunking
+	}
+	chunking = chunking.withDefaults()
+	var out envelopeSender = stream
+	if o.chunking != nil {
+		out = &chunkingSender{out: stream, size: chunking.ChunkSize}
+	}
+	if o.metrics != nil {
+		out = &countingSender{out: out, metrics: o.metrics}
+	}
+	s.writer = newStreamWriter(out, o.sendQueueSize, o.overflowPolicy)
+	s.chunks = newChunkAssembler(chunking, s.sendNak, s.log)
+	s.codec = newPayloadCodec(o.compression)
+	s.chain = chainOf(o.middleware)
+	s.panics = o.panics
+	if s.panics == nil {
+		s.panics = newPanicTracker(o.quarantine, s.log)
+	}
+	if o.reliable != nil {
+		s.reliable = newReliableTracker(s.writer, *o.reliable)
+	}
+	if o.flow != nil {
+		s.flow = newFlowController(o.flow.Window)
+		s.writer.onDrop = s.releaseDropped
+	}
+	if o.workers > 1 {
+		s.pool = newOrderedPool(o.workers, o.maxPending)
+	}
+	if o.heartbeat != nil {
+		s.beats = newHeartbeatMonitor(*o.heartbeat)
+	}
+	s.clock = o.clock
+	if s.clock == nil {
+		s.clock = NewSimClock(time.Now())
+	}
+	s.sched = newScheduler(s.clock, s.log)
+	return s
+}
+
+// received is the result of one Recv on the stream.
+type received struct {
+	env *simsdkrpc.PluginMessageEnvelope
+	err error
+}
+
+// receive reads the stream on its own goroutine so the serve loop can also
+// react to a dead peer. Liveness is recorded here rather than in the serve
+// loop, so a slow handler does not hide the peer's heartbeats. It stops after
+// the first error or once serve returns.
+func (s *streamSession) receive() <-chan received {
+	ch := make(chan received)
+	go func() {
+		for {
+			env, err := s.stream.Recv()
+			if err == nil && s.beats != nil {
+				s.beats.received()
+			}
+			select {
+			case ch <- received{env, err}:
+			case <-s.done:
+				return
+			}
+			if s.beats != nil {
+				s.beats.delivered()
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func (s *streamSession) serve() error {
+	defer close(s.done)
+	defer s.cancel()
+	defer s.metrics.openStream(s.writer)()
+	defer s.writer.close(s.ctx)
+	defer s.sched.Stop()
+	defer s.chunks.close()
+	if s.reliable != nil {
+		defer s.reliable.close()
+	}
+	if s.flow != nil {
+		defer s.flow.close()
+		_ = s.writer.enqueue(s.ctx, creditEnvelope(uint32(s.flow.window)), false)
+	}
+	if offer := s.codec.offer(); offer != nil {
+		_ = s.writer.enqueue(s.ctx, offer, false)
+	}
+	var dead <-chan struct{}
+	if s.beats != nil {
+		dead = s.beats.dead
+		go s.beats.run(s.done, func() *simsdkrpc.PluginMessageEnvelope {
+			return heartbeatEnvelope(s.load())
+		}, func(env *simsdkrpc.PluginMessageEnvelope) {
+			// A heartbeat that finds the queue full is skipped, so a core
+			// that stops reading cannot block the deadline check.
+			_ = s.writer.tryEnqueue(env)
+		})
+	}
+
+	recv := s.receive()
+	for {
+		var in *simsdkrpc.PluginMessageEnvelope
+		var err error
+		select {
+		case r := <-recv:
+			in, err = r.env, r.err
+		case <-dead:
+			// Workers are not waited for: a handler that never returns
+			// must not keep a dead stream open. Cancelling ends the
+			// pending sends and the flush.
+			s.log.Warn("Heartbeat timeout: no messages from core")
+			s.cancel()
+			s.shutdownAll("heartbeat timeout", false)
+			return ErrHeartbeatTimeout
+		}
+		if err == io.EOF {
+			s.log.Info("Stream closed by client")
+			s.waitStep()
+			s.waitWorkers()
+			s.shutdownAll("stream closed", false)
+			return s.writer.close(s.ctx)
+		}
+		if err != nil {
+			s.waitStep()
+			s.waitWorkers()
+			s.shutdownAll("stream error", false)
+			return fmt.Errorf("ServeStream: failed to receive from stream: %w", err)
+		}
+		if err := s.workerError(); err != nil {
+			s.waitStep()
+			s.waitWorkers()
+			s.shutdownAll("send failed", false)
+			return err
+		}
+		switch in.Content.(type) {
+		case *simsdkrpc.PluginMessageEnvelope_Ack, *simsdkrpc.PluginMessageEnvelope_Nak,
+			*simsdkrpc.PluginMessageEnvelope_BatchResult, *simsdkrpc.PluginMessageEnvelope_Credit,
+			*simsdkrpc.PluginMessageEnvelope_Compression, *simsdkrpc.PluginMessageEnvelope_Heartbeat:
+			// read while a step runs, so the step's sends can get credits
+			// and reliable-window slots
+		default:
+			s.waitStep()
+		}
+		switch msg := in.Content.(type) {
+		case *simsdkrpc.PluginMessageEnvelope_Init:
+			s.log.Info("Received Init message", slog.String("component_id", msg.Init.GetComponentId()))
+			if err := s.initComponent(msg.Init); err != nil {
+				s.shutdownAll("init failed", false)
+				return fmt.Errorf("ServeStream: OnInit failed: %w", err)
+			}
+
+		case *simsdkrpc.PluginMessageEnvelope_SimMessage:
+			s.log.Debug("Received SimMessage", messageAttrs(msg.SimMessage)...)
+			if err := s.deliver(msg.SimMessage, in.Sequence); err != nil {
+				return err
+			}
+
+		case *simsdkrpc.PluginMessageEnvelope_Chunk:
+			if sm := s.chunks.add(msg.Chunk, in.Sequence); sm != nil {
+				s.log.Debug("Received SimMessage", append(messageAttrs(sm), slog.Uint64("chunks", uint64(msg.Chunk.Count)))...)
+				if err := s.deliver(sm, in.Sequence); err != nil {
+					return err
+				}
+			}
+
+		case *simsdkrpc.PluginMessageEnvelope_Batch:
+			s.log.Debug("Received MessageBatch", slog.Int("messages", len(msg.Batch.GetMessages())))
+			s.waitWorkers()
+			if err := s.handleBatch(msg.Batch); err != nil {
+				s.shutdownAll("send failed", false)
+				return err
+			}
+
+		case *simsdkrpc.PluginMessageEnvelope_Shutdown:
+			s.log.Info("Received Shutdown message", slog.String("component_id", msg.Shutdown.GetComponentId()), slog.String("reason", msg.Shutdown.GetReason()))
+			s.waitWorkers()
+			if id := msg.Shutdown.GetComponentId(); id != "" {
+				s.shutdownComponent(id, msg.Shutdown.Reason)
+				continue
+			}
+			s.shutdownAll(msg.Shutdown.Reason, true)
+			return s.writer.close(s.ctx)
+
+		case *simsdkrpc.PluginMessageEnvelope_Ack:
+			if s.reliable != nil {
+				s.reliable.ack(msg.Ack)
+			}
+
+		case *simsdkrpc.PluginMessageEnvelope_Nak:
+			s.log.Debug("Received Nak", slog.String("message_id", msg.Nak.MessageId), slog.String("error", msg.Nak.ErrorMessage))
+			if s.reliable != nil {
+				s.reliable.nak(msg.Nak)
+			}
+
+		case *simsdkrpc.PluginMessageEnvelope_BatchResult:
+			s.handleBatchResult(msg.BatchResult)
+
+		case *simsdkrpc.PluginMessageEnvelope_Compression:
+			s.codec.negotiate(msg.Compression)
+
+		case *simsdkrpc.PluginMessageEnvelope_Credit:
+			if s.flow != nil {
+				s.flow.grant(msg.Credit.GetCredits())
+			}
+
+		case *simsdkrpc.PluginMessageEnvelope_ClockSync:
+			cs := msg.ClockSync
+			s.clock.Sync(fromProtoTime(cs.GetSimTime()), fromProtoTime(cs.GetWallTime()), cs.GetTimeScale(), cs.GetPaused())
+
+		case *simsdkrpc.PluginMessageEnvelope_StepBegin:
+			s.startStep(msg.StepBegin)
+
+		case *simsdkrpc.PluginMessageEnvelope_Heartbeat:
+			// liveness was recorded by receive
+
+		default:
+			s.log.Warn("Unknown message type in PluginMessageEnvelope", slog.String("type", fmt.Sprintf("%T", msg)))
+		}
+	}
+}
+
+// deliver hands an inbound message to the worker pool, or handles it inline.
+// An error ends the stream; the components have been shut down by then.
+func (s *streamSession) deliver(in *simsdkrpc.SimMessage, seq uint64) error {
+	if s.admit(1) == 0 {
+		s.log.Warn("SimMessage sent without credits", messageAttrs(in)...)
+		s.metrics.handled(in.ComponentId, in.MessageType, time.Time{}, true)
+		return s.sendNak(in.MessageId, seq, noCredits)
+	}
+	if s.pool != nil {
+		if err := s.dispatch(in, seq); err != nil {
+			s.waitWorkers()
+			s.shutdownAll("stream closed", false)
+			return err
+		}
+		return nil
+	}
+	if err := s.handleSimMessage(in, seq); err != nil {
+		s.shutdownAll("send failed", false)
+		return err
+	}
+	return nil
+}
+
+// dispatch hands a message to the worker pool, ordered by its ordering key.
+func (s *streamSession) dispatch(in *simsdkrpc.SimMessage, seq uint64) error {
+	key := s.opts.orderingKeyFor(in.ComponentId, in.Metadata)
+	return s.pool.submit(s.ctx, key, func() {
+		if err := s.handleSimMessage(in, seq); err != nil {
+			s.mu.Lock()
+			if s.workerErr == nil {
+				s.workerErr = err
+			}
+			s.mu.Unlock()
+		}
+	})
+}
+
+func (s *streamSession) workerError() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.workerErr
+}
+
+// waitWorkers blocks until every dispatched message has been handled, so
+// shutdowns never overtake messages received before them.
+func (s *streamSession) waitWorkers() {
+	if s.pool != nil {
+		s.pool.wait()
+	}
+}
+
+// load reports how busy the stream is.
+func (s *streamSession) load() StreamLoad {
+	inFlight, oldest := s.inflight.snapshot()
+	l := StreamLoad{
+		QueueDepth:     s.writer.stats().Depth,
+		InFlight:       inFlight,
+		OldestInFlight: oldest,
+	}
+	if s.pool != nil {
+		if pending := s.pool.size() - inFlight; pending > 0 {
+			l.Pending = pending
+		}
+	}
+	s.mu.Lock()
+	l.Components = len(s.components)
+	s.mu.Unlock()
+	return l
+}
+
+// initComponent creates (or re-initializes) the handler for a component and
+// injects its sender view. A handler that may be busy on a worker is only
+// re-initialized once the workers are idle.
+func (s *streamSession) initComponent(init *simsdkrpc.PluginInit) error {
+	s.mu.Lock()
+	_, exists := s.components[init.ComponentId]
+	s.mu.Unlock()
+	if exists || s.shared != nil {
+		// The handler may still be handling messages on a worker.
+		s.waitWorkers()
+	}
+
+	s.mu.Lock()
+	c, ok := s.components[init.ComponentId]
+	if !ok {
+		handler := s.shared
+		if handler == nil {
+			handler = s.factory()
+		}
+		c = &streamComponent{id: init.ComponentId, handler: handler}
+		s.components[init.ComponentId] = c
+	}
+	if s.shared != nil {
+		s.sharedInit = true
+	}
+	if c.sender != nil {
+		c.sender.closed.Store(true)
+	}
+	c.sender = &grpcStreamSender{writer: s.writer, reliable: s.reliable, flow: s.flow, clock: s.clock, scheduler: s.sched, codec: s.codec, componentID: init.ComponentId}
+	s.mu.Unlock()
+
+	return s.panics.run(&Call{Kind: StreamCall, Method: "OnInit", ComponentID: init.ComponentId}, func() error {
+		return s.setUpHandler(c, init)
+	})
+}
+
+// setUpHandler hands the handler its sender and clock, then initializes it.
+func (s *streamSession) setUpHandler(c *streamComponent, init *simsdkrpc.PluginInit) error {
+	// Inject stream sender into handler if supported
+	if setter, ok := c.handler.(StreamSenderSetter); ok {
+		setter.SetStreamSender(c.sender)
+		s.log.Debug("Installed stream sender", slog.String("component_id", init.ComponentId), slog.String("handler", fmt.Sprintf("%T", c.handler)))
+	} else {
+		s.log.Debug("Handler does not implement StreamSenderSetter (stream sender not set)", slog.String("component_id", init.ComponentId), slog.String("handler", fmt.Sprintf("%T", c.handler)))
+	}
+	if setter, ok := c.handler.(ClockSetter); ok {
+		setter.SetClock(s.clock)
+	}
+
+	if err := c.handler.OnInit(init); err != nil {
+		return err
+	}
+	s.declareLookahead(init.ComponentId, c.handler)
+	return nil
+}
+
+// handlerFor returns the handler for a component. A shared handler receives
+// every message; otherwise the component must have been initialized. Messages
+// without a ComponentId go to the only component when there is exactly one.
+func (s *streamSession) handlerFor(componentID string) StreamHandler {
+	if s.shared != nil {
+		return s.shared
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.components[componentID]; ok {
+		return c.handler
+	}
+	if componentID == "" && len(s.components) == 1 {
+		for _, c := range s.components {
+			return c.handler
+		}
+	}
+	return nil
+}
+
+func (s *streamSession) handleSimMessage(in *simsdkrpc.SimMessage, seq uint64) error {
+	defer s.replenishCredits()
+	defer s.inflight.end(s.inflight.begin())
+
+	nak, err := s.processMessage(in)
+	if err != nil {
+		return err
+	}
+	if nak != "" {
+		return s.sendNak(in.MessageId, seq, nak)
+	}
+
+	_ = s.writer.enqueue(s.ctx, &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_Ack{
+			Ack: &simsdkrpc.PluginAck{
+				MessageId: in.MessageId,
+				Sequence:  seq,
+			},
+		},
+	}, false)
+	return nil
+}
+
+// processMessage runs one inbound message through its handler and sends its
+// responses. It returns the reason to nak the message, if any; err is a send
+// failure that ends the stream.
+func (s *streamSession) processMessage(in *simsdkrpc.SimMessage) (nak string, err error) {
+	start := time.Now()
+	defer func() { s.metrics.handled(in.ComponentId, in.MessageType, start, nak != "") }()
+
+	handler := s.handlerFor(in.ComponentId)
+	if handler == nil {
+		s.log.Warn("No handler for component", messageAttrs(in)...)
+		return fmt.Sprintf("no handler initialized for component %q", in.ComponentId), nil
+	}
+
+	if err := s.panics.check(in.ComponentId); err != nil {
+		return err.Error(), nil
+	}
+
+	if err := s.codec.decode(in); err != nil {
+		return err.Error(), nil
+	}
+	msg := FromProtoSimMessage(in)
+	ctx, span := StartMessageSpan(s.ctx, s.tracer, "simsdk.OnSimMessage", SpanKindConsumer, msg)
+	defer span.End()
+	responses, err := intercept(s.chain, ctx, &Call{Kind: StreamCall, Method: "OnSimMessage", ComponentID: msg.ComponentID, Message: msg},
+		func(_ context.Context, call *Call) (out []*SimMessage, err error) {
+			err = s.panics.run(call, func() (err error) {
+				out, err = handler.OnSimMessage(call.Message)
+				return err
+			})
+			return out, err
+		})
+	if err != nil {
+		s.log.Warn("OnSimMessage failed", append(messageAttrs(in), slog.Any("error", err))...)
+		span.RecordError(err)
+		return err.Error(), nil
+	}
+	for i, resp := range responses {
+		responses[i] = withTraceContext(ctx, stampResponse(msg, resp))
+	}
+	return "", s.sendResponses(responses)
+}
+
+// sendResponses sends handler responses, scheduling those stamped with a
+// future simulation time. Until the clock has been synced it does not tell
+// simulation time, so responses are sent right away.
+func (s *streamSession) sendResponses(responses []*SimMessage) error {
+	synced := s.clock.Synced()
+	for _, resp := range responses {
+		if synced && resp.SimTime.After(s.clock.Now()) {
+			s.log.Debug("Scheduling response message", append(simMessageAttrs(resp), slog.Time("sim_time", resp.SimTime))...)
+			if _, err := s.sched.At(resp.SimTime, resp, s.sendResponse); err != nil {
+				return fmt.Errorf("ServeStream: failed to schedule SimMessage: %w", err)
+			}
+			continue
+		}
+		s.log.Debug("Sending response message", simMessageAttrs(resp)...)
+		if err := s.sendResponse(resp); err != nil {
+			return fmt.Errorf("ServeStream: failed to send SimMessage: %w", err)
+		}
+	}
+	return nil
+}
+
+// sendResponse queues a response returned from OnSimMessage. Responses are
+// never dropped by the overflow policy.
+func (s *streamSession) sendResponse(resp *SimMessage) error {
+	out := ToProtoSimMessage(resp)
+	stampTimes(out, s.clock)
+	s.codec.encode(out)
+	return s.writer.enqueue(s.ctx, &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_SimMessage{SimMessage: out},
+	}, false)
+}
+
+// admit takes inbound credits for up to n messages and returns how many the
+// core was entitled to send. All are admitted without flow control.
+func (s *streamSession) admit(n int) int {
+	if s.flow == nil {
+		return n
+	}
+	return s.flow.admit(n)
+}
+
+// releaseDropped returns the outbound credits of a message the overflow policy
+// dropped. Reliable messages keep theirs: they are redelivered until acked.
+func (s *streamSession) releaseDropped(env *simsdkrpc.PluginMessageEnvelope) {
+	if env.Sequence != 0 {
+		return
+	}
+	switch c := env.Content.(type) {
+	case *simsdkrpc.PluginMessageEnvelope_SimMessage:
+		s.flow.release(1, true)
+	case *simsdkrpc.PluginMessageEnvelope_Batch:
+		s.flow.release(len(c.Batch.GetMessages()), true)
+	}
+}
+
+// replenishCredits grants the core more inbound credits once enough messages
+// have been handled.
+func (s *streamSession) replenishCredits() {
+	if s.flow == nil {
+		return
+	}
+	if n := s.flow.consume(); n > 0 {
+		_ = s.writer.enqueue(s.ctx, creditEnvelope(n), false)
+	}
+}
+
+func (s *streamSession) sendNak(messageID string, seq uint64, reason string) error {
+	_ = s.writer.enqueue(s.ctx, &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_Nak{
+			Nak: &simsdkrpc.PluginNak{
+				MessageId:    messageID,
+				ErrorMessage: reason,
+				Sequence:     seq,
+			},
+		},
+	}, false)
+	return nil
+}
+
+// shutdownComponent shuts down a single component and closes its sender view.
+// A shared handler still serves the other components, so it is only shut down
+// when the stream ends.
+func (s *streamSession) shutdownComponent(componentID, reason string) {
+	s.mu.Lock()
+	c, ok := s.components[componentID]
+	delete(s.components, componentID)
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	c.sender.closed.Store(true)
+	if s.shared == nil {
+		s.shutdownHandler(componentID, c.handler, reason)
+	}
+	s.panics.clear(componentID)
+}
+
+// shutdownAll shuts down every component still live on the stream. A shared
+// handler is shut down once, if it was initialized or shutdown was requested.
+func (s *streamSession) shutdownAll(reason string, requested bool) {
+	s.mu.Lock()
+	components := s.components
+	s.components = make(map[string]*streamComponent)
+	sharedInit := s.sharedInit
+	s.mu.Unlock()
+
+	for _, c := range components {
+		c.sender.closed.Store(true)
+		if s.shared == nil {
+			s.shutdownHandler(c.id, c.handler, reason)
+		}
+	}
+	if s.shared != nil && (requested || sharedInit) {
+		s.shutdownHandler("", s.shared, reason)
+	}
+}
+
+// shutdownHandler calls OnShutdown, recovering a panic so the remaining
+// components are still shut down.
+func (s *streamSession) shutdownHandler(componentID string, h StreamHandler, reason string) {
+	_ = s.panics.run(&Call{Kind: StreamCall, Method: "OnShutdown", ComponentID: componentID}, func() error {
+		h.OnShutdown(reason)
+		return nil
+	})
+}
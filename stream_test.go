@@ -0,0 +1,176 @@
+package simsdk
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHandler is a per-component handler that echoes through its sender.
+type recordingHandler struct {
+	mu       sync.Mutex
+	sender   StreamSender
+	inits    []string
+	messages []string
+	shutdown []string
+}
+
+func (h *recordingHandler) SetStreamSender(s StreamSender) { h.sender = s }
+
+func (h *recordingHandler) OnInit(init *simsdkrpc.PluginInit) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.inits = append(h.inits, init.ComponentId)
+	return nil
+}
+
+func (h *recordingHandler) OnSimMessage(msg *SimMessage) ([]*SimMessage, error) {
+	h.mu.Lock()
+	h.messages = append(h.messages, msg.MessageID)
+	h.mu.Unlock()
+	// push an unsolicited message through the component's own sender view
+	return nil, h.sender.Send(&SimMessage{MessageID: "pushed-" + msg.MessageID})
+}
+
+func (h *recordingHandler) OnShutdown(reason string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.shutdown = append(h.shutdown, reason)
+}
+
+func initEnvelope(id string) *simsdkrpc.PluginMessageEnvelope {
+	return &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_Init{Init: &simsdkrpc.PluginInit{ComponentId: id}},
+	}
+}
+
+func simMessageEnvelope(id, componentID string) *simsdkrpc.PluginMessageEnvelope {
+	return &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_SimMessage{
+			SimMessage: &simsdkrpc.SimMessage{MessageId: id, ComponentId: componentID},
+		},
+	}
+}
+
+func shutdownEnvelope(reason, componentID string) *simsdkrpc.PluginMessageEnvelope {
+	return &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_Shutdown{
+			Shutdown: &simsdkrpc.PluginShutdown{Reason: reason, ComponentId: componentID},
+		},
+	}
+}
+
+func TestServeStreamMux_RoutesPerComponent(t *testing.T) {
+	handlers := map[string]*recordingHandler{}
+	var created []*recordingHandler
+	factory := func() StreamHandler {
+		h := &recordingHandler{}
+		created = append(created, h)
+		return h
+	}
+
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		initEnvelope("b"),
+		simMessageEnvelope("m1", "a"),
+		simMessageEnvelope("m2", "b"),
+		simMessageEnvelope("m3", "ghost"),
+		shutdownEnvelope("done with a", "a"),
+		simMessageEnvelope("m4", "a"),
+		simMessageEnvelope("m5", "b"),
+	}}
+
+	require.NoError(t, ServeStreamMux(factory, stream))
+	require.Len(t, created, 2)
+	for _, h := range created {
+		handlers[h.inits[0]] = h
+	}
+
+	a, b := handlers["a"], handlers["b"]
+	require.Equal(t, []string{"m1"}, a.messages)
+	require.Equal(t, []string{"m2", "m5"}, b.messages)
+	require.Equal(t, []string{"done with a"}, a.shutdown)
+	require.Equal(t, []string{"stream closed"}, b.shutdown, "remaining components are shut down at EOF")
+	require.ErrorIs(t, a.sender.Send(&SimMessage{}), ErrStreamClosed)
+
+	var pushed = map[string]string{}
+	var naks []string
+	for _, env := range stream.sent {
+		switch m := env.Content.(type) {
+		case *simsdkrpc.PluginMessageEnvelope_SimMessage:
+			pushed[m.SimMessage.MessageId] = m.SimMessage.ComponentId
+		case *simsdkrpc.PluginMessageEnvelope_Nak:
+			naks = append(naks, m.Nak.MessageId)
+		}
+	}
+	require.Equal(t, map[string]string{"pushed-m1": "a", "pushed-m2": "b", "pushed-m5": "b"}, pushed)
+	require.Equal(t, []string{"m3", "m4"}, naks)
+}
+
+func TestServeStream_SharedHandlerReceivesEverything(t *testing.T) {
+	h := &recordingHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		simMessageEnvelope("m1", "a"),
+		simMessageEnvelope("m2", "other"),
+		shutdownEnvelope("bye", ""),
+	}}
+
+	require.NoError(t, ServeStream(h, stream))
+	require.Equal(t, []string{"m1", "m2"}, h.messages)
+	require.Equal(t, []string{"bye"}, h.shutdown)
+}
+
+func TestServeStream_ComponentShutdownKeepsSharedHandler(t *testing.T) {
+	h := &recordingHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		initEnvelope("b"),
+		shutdownEnvelope("a done", "a"),
+		simMessageEnvelope("m1", "b"),
+		shutdownEnvelope("b done", "b"),
+	}}
+
+	require.NoError(t, ServeStream(h, stream))
+	require.Equal(t, []string{"m1"}, h.messages, "b is still served after a was shut down")
+	require.Equal(t, []string{"stream closed"}, h.shutdown, "the shared handler is shut down once, when the stream ends")
+}
+
+// streamPlugin hands out a new recordingHandler for every GetStreamHandler call.
+type streamPlugin struct {
+	dummyPlugin
+	handlers []*recordingHandler
+}
+
+func (p *streamPlugin) GetStreamHandler() StreamHandler {
+	h := &recordingHandler{}
+	p.handlers = append(p.handlers, h)
+	return h
+}
+
+func TestGRPCAdapter_MessageStreamMuxIsOptIn(t *testing.T) {
+	incoming := func() []*simsdkrpc.PluginMessageEnvelope {
+		return []*simsdkrpc.PluginMessageEnvelope{initEnvelope("c1"), simMessageEnvelope("m1", "c2")}
+	}
+
+	plugin := &streamPlugin{}
+	stream := &mockStream{incoming: incoming()}
+	require.NoError(t, NewGRPCAdapter(plugin).MessageStream(stream))
+	require.Len(t, plugin.handlers, 1)
+	require.Equal(t, []string{"m1"}, plugin.handlers[0].messages, "one handler serves the whole stream")
+
+	plugin = &streamPlugin{}
+	stream = &mockStream{incoming: incoming()}
+	require.NoError(t, NewGRPCAdapter(plugin, WithStreamMux()).MessageStream(stream))
+	require.Len(t, plugin.handlers, 1)
+	require.Empty(t, plugin.handlers[0].messages)
+	var naks int
+	for _, env := range stream.sent {
+		if nak := env.GetNak(); nak != nil && nak.MessageId == "m1" {
+			naks++
+		}
+	}
+	require.Equal(t, 1, naks, "a message for a component not initialized on the stream is nak'd")
+}
@@ -0,0 +1,226 @@
+package simsdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+)
+
+// OverflowPolicy decides what StreamSender.Send does when the outbound queue is full.
+type OverflowPolicy int
+
+const (
+	OverflowBlock      OverflowPolicy = iota // Wait for space (or context cancellation)
+	OverflowDropOldest                       // Discard the oldest queued plugin message
+	OverflowError                            // Fail fast with ErrSendQueueFull
+)
+
+// DefaultSendQueueSize is the default outbound queue capacity per stream.
+const DefaultSendQueueSize = 256
+
+var (
+	// ErrSendQueueFull is returned by Send under OverflowError when the queue is full.
+	ErrSendQueueFull = fmt.Errorf("%w: stream send queue full", ErrUnavailable)
+	// ErrStreamClosed is returned by Send once the stream has been torn down.
+	ErrStreamClosed = fmt.Errorf("%w: stream closed", ErrUnavailable)
+)
+
+// SendQueueStats reports the state of a stream's outbound queue.
+type SendQueueStats struct {
+	Depth    int    `json:"depth"`
+	Capacity int    `json:"capacity"`
+	Dropped  uint64 `json:"dropped"`
+}
+
+// QueuedStreamSender is implemented by the SDK's stream senders. Send may be
+// called from any goroutine; SendContext additionally honors ctx while waiting
+// for queue space.
+type QueuedStreamSender interface {
+	StreamSender
+	SendContext(ctx context.Context, msg *SimMessage) error
+	QueueStats() SendQueueStats
+}
+
+type envelopeSender interface {
+	Send(*simsdkrpc.PluginMessageEnvelope) error
+}
+
+type outboundEnvelope struct {
+	env       *simsdkrpc.PluginMessageEnvelope
+	droppable bool // plugin-originated messages; acks and responses are never dropped
+}
+
+// streamWriter owns the only goroutine allowed to call Send on a gRPC stream.
+// Everything else enqueues envelopes into a bounded FIFO.
+type streamWriter struct {
+	out      envelopeSender
+	capacity int
+	policy   OverflowPolicy
+
+	mu      sync.Mutex
+	items   []outboundEnvelope
+	closed  bool
+	err     error
+	dropped uint64
+	space   chan struct{} // closed and replaced whenever an item is dequeued
+	onDrop  func(*simsdkrpc.PluginMessageEnvelope)
+
+	wake chan struct{}
+	done chan struct{}
+}
+
+func newStreamWriter(out envelopeSender, capacity int, policy OverflowPolicy) *streamWriter {
+	if capacity <= 0 {
+		capacity = DefaultSendQueueSize
+	}
+	w := &streamWriter{
+		out:      out,
+		capacity: capacity,
+		policy:   policy,
+		space:    make(chan struct{}),
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// enqueue adds an envelope to the queue. Droppable envelopes are subject to the
+// overflow policy; the others always wait for space.
+func (w *streamWriter) enqueue(ctx context.Context, env *simsdkrpc.PluginMessageEnvelope, droppable bool) error {
+	for {
+		w.mu.Lock()
+		if w.closed {
+			err := w.err
+			w.mu.Unlock()
+			if err != nil {
+				return err
+			}
+			return ErrStreamClosed
+		}
+		if len(w.items) < w.capacity {
+			w.push(outboundEnvelope{env: env, droppable: droppable})
+			w.mu.Unlock()
+			return nil
+		}
+		if droppable {
+			switch w.policy {
+			case OverflowError:
+				w.mu.Unlock()
+				return ErrSendQueueFull
+			case OverflowDropOldest:
+				if w.dropOldest() {
+					w.push(outboundEnvelope{env: env, droppable: droppable})
+					w.mu.Unlock()
+					return nil
+				}
+			}
+		}
+		space := w.space
+		w.mu.Unlock()
+
+		select {
+		case <-space:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.done:
+		}
+	}
+}
+
+// tryEnqueue adds an envelope only if the queue has space, and never waits.
+// It reports whether the envelope was queued.
+func (w *streamWriter) tryEnqueue(env *simsdkrpc.PluginMessageEnvelope) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed || len(w.items) >= w.capacity {
+		return false
+	}
+	w.push(outboundEnvelope{env: env})
+	return true
+}
+
+// push appends an item and wakes the writer. Caller holds w.mu.
+func (w *streamWriter) push(item outboundEnvelope) {
+	w.items = append(w.items, item)
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dropOldest removes the oldest droppable item and reports it to onDrop.
+// Caller holds w.mu.
+func (w *streamWriter) dropOldest() bool {
+	for i, item := range w.items {
+		if item.droppable {
+			w.items = append(w.items[:i], w.items[i+1:]...)
+			w.dropped++
+			if w.onDrop != nil {
+				w.onDrop(item.env)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (w *streamWriter) run() {
+	defer close(w.done)
+	for {
+		w.mu.Lock()
+		for len(w.items) == 0 {
+			if w.closed {
+				w.mu.Unlock()
+				return
+			}
+			w.mu.Unlock()
+			<-w.wake
+			w.mu.Lock()
+		}
+		item := w.items[0]
+		w.items = w.items[1:]
+		close(w.space)
+		w.space = make(chan struct{})
+		w.mu.Unlock()
+
+		if err := w.out.Send(item.env); err != nil {
+			w.mu.Lock()
+			w.closed = true
+			w.err = fmt.Errorf("%w: %w", ErrStreamClosed, err)
+			w.items = nil
+			w.mu.Unlock()
+			return
+		}
+	}
+}
+
+// close stops accepting envelopes, flushes what is queued and waits for the
+// writer goroutine to exit or ctx to end. It returns the first send error, if any.
+func (w *streamWriter) close(ctx context.Context) error {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+func (w *streamWriter) stats() SendQueueStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return SendQueueStats{Depth: len(w.items), Capacity: w.capacity, Dropped: w.dropped}
+}
@@ -0,0 +1,149 @@
+package simsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedSender records envelopes and blocks each Send until released.
+type gatedSender struct {
+	mu     sync.Mutex
+	sent   []*simsdkrpc.PluginMessageEnvelope
+	gate   chan struct{}
+	err    error
+	active int
+	maxAct int
+}
+
+func (g *gatedSender) Send(env *simsdkrpc.PluginMessageEnvelope) error {
+	g.mu.Lock()
+	g.active++
+	if g.active > g.maxAct {
+		g.maxAct = g.active
+	}
+	g.mu.Unlock()
+
+	if g.gate != nil {
+		<-g.gate
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.active--
+	if g.err != nil {
+		return g.err
+	}
+	g.sent = append(g.sent, env)
+	return nil
+}
+
+func (g *gatedSender) ids() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var ids []string
+	for _, env := range g.sent {
+		ids = append(ids, env.GetSimMessage().GetMessageId())
+	}
+	return ids
+}
+
+func simEnvelope(id string) *simsdkrpc.PluginMessageEnvelope {
+	return &simsdkrpc.PluginMessageEnvelope{
+		Content: &simsdkrpc.PluginMessageEnvelope_SimMessage{
+			SimMessage: &simsdkrpc.SimMessage{MessageId: id},
+		},
+	}
+}
+
+func TestStreamWriter_ConcurrentSendsAreSerialized(t *testing.T) {
+	out := &gatedSender{}
+	w := newStreamWriter(out, 8, OverflowBlock)
+	sender := &grpcStreamSender{writer: w, componentID: "c1"}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				require.NoError(t, sender.Send(&SimMessage{MessageID: fmt.Sprintf("%d-%d", g, i)}))
+			}
+		}(g)
+	}
+	wg.Wait()
+	require.NoError(t, w.close(context.Background()))
+
+	require.Len(t, out.ids(), 400)
+	require.Equal(t, 1, out.maxAct, "stream.Send must never run concurrently")
+	require.ErrorIs(t, sender.Send(&SimMessage{}), ErrStreamClosed)
+}
+
+func TestStreamWriter_OverflowPolicies(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		out := &gatedSender{gate: make(chan struct{})}
+		w := newStreamWriter(out, 2, OverflowError)
+		defer close(out.gate)
+
+		require.NoError(t, w.enqueue(context.Background(), simEnvelope("in-flight"), true))
+		require.Eventually(t, func() bool { return w.stats().Depth == 0 }, time.Second, time.Millisecond)
+		require.NoError(t, w.enqueue(context.Background(), simEnvelope("a"), true))
+		require.NoError(t, w.enqueue(context.Background(), simEnvelope("b"), true))
+
+		err := w.enqueue(context.Background(), simEnvelope("c"), true)
+		require.ErrorIs(t, err, ErrSendQueueFull)
+		require.ErrorIs(t, err, ErrUnavailable)
+		require.Equal(t, SendQueueStats{Depth: 2, Capacity: 2}, w.stats())
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		out := &gatedSender{gate: make(chan struct{}, 16)}
+		w := newStreamWriter(out, 2, OverflowDropOldest)
+
+		require.NoError(t, w.enqueue(context.Background(), simEnvelope("in-flight"), true))
+		require.Eventually(t, func() bool { return w.stats().Depth == 0 }, time.Second, time.Millisecond)
+		require.NoError(t, w.enqueue(context.Background(), simEnvelope("ack"), false))
+		require.NoError(t, w.enqueue(context.Background(), simEnvelope("a"), true))
+		require.NoError(t, w.enqueue(context.Background(), simEnvelope("b"), true))
+		require.NoError(t, w.enqueue(context.Background(), simEnvelope("c"), true))
+		require.EqualValues(t, 2, w.stats().Dropped)
+
+		for i := 0; i < 16; i++ {
+			out.gate <- struct{}{}
+		}
+		require.NoError(t, w.close(context.Background()))
+		require.Equal(t, []string{"in-flight", "ack", "c"}, out.ids(), "non-droppable envelopes survive")
+	})
+
+	t.Run("block honors context", func(t *testing.T) {
+		out := &gatedSender{gate: make(chan struct{})}
+		w := newStreamWriter(out, 1, OverflowBlock)
+		defer close(out.gate)
+		sender := &grpcStreamSender{writer: w}
+
+		require.NoError(t, sender.Send(&SimMessage{MessageID: "in-flight"}))
+		require.Eventually(t, func() bool { return sender.QueueStats().Depth == 0 }, time.Second, time.Millisecond)
+		require.NoError(t, sender.Send(&SimMessage{MessageID: "queued"}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, sender.SendContext(ctx, &SimMessage{MessageID: "late"}), context.DeadlineExceeded)
+	})
+}
+
+func TestStreamWriter_SendErrorClosesWriter(t *testing.T) {
+	out := &gatedSender{err: errors.New("transport is closing")}
+	w := newStreamWriter(out, 4, OverflowBlock)
+
+	require.NoError(t, w.enqueue(context.Background(), simEnvelope("a"), true))
+	err := w.close(context.Background())
+	require.ErrorIs(t, err, ErrStreamClosed)
+	require.ErrorContains(t, err, "transport is closing")
+	require.ErrorIs(t, w.enqueue(context.Background(), simEnvelope("b"), true), ErrStreamClosed)
+}
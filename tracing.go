@@ -0,0 +1,298 @@
+package simsdk
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// W3C Trace Context keys, used both in SimMessage metadata and in gRPC metadata.
+const (
+	TraceParentKey = "traceparent"
+	TraceStateKey  = "tracestate"
+)
+
+// TraceFlagsSampled is the W3C trace flag marking a sampled trace.
+const TraceFlagsSampled byte = 0x01
+
+// SpanContext identifies a span within a trace, as carried by the W3C
+// traceparent and tracestate headers.
+type SpanContext struct {
+	TraceID    [16]byte
+	SpanID     [8]byte
+	TraceFlags byte
+	TraceState string // Vendor-specific tracestate, passed through as is
+}
+
+// IsValid reports whether sc has a non-zero trace and span ID.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// IsSampled reports whether the sampled flag is set.
+func (sc SpanContext) IsSampled() bool { return sc.TraceFlags&TraceFlagsSampled != 0 }
+
+// TraceParent formats sc as a version 00 traceparent header.
+func (sc SpanContext) TraceParent() string {
+	return fmt.Sprintf("00-%x-%x-%02x", sc.TraceID, sc.SpanID, sc.TraceFlags)
+}
+
+// ErrInvalidTraceParent is returned by ParseTraceParent for malformed headers.
+var ErrInvalidTraceParent = errors.New("invalid traceparent")
+
+// ParseTraceParent parses a W3C traceparent header. Headers of future versions
+// are accepted as long as they start with the version 00 fields.
+func ParseTraceParent(s string) (SpanContext, error) {
+	var sc SpanContext
+	if len(s) < 55 || s[2] != '-' || s[35] != '-' || s[52] != '-' || (len(s) > 55 && s[55] != '-') {
+		return sc, ErrInvalidTraceParent
+	}
+	version, ok := decodeHex(s[0:2], 1)
+	if !ok || version[0] == 0xff || (version[0] == 0 && len(s) != 55) {
+		return sc, ErrInvalidTraceParent
+	}
+	traceID, ok1 := decodeHex(s[3:35], 16)
+	spanID, ok2 := decodeHex(s[36:52], 8)
+	flags, ok3 := decodeHex(s[53:55], 1)
+	if !ok1 || !ok2 || !ok3 {
+		return sc, ErrInvalidTraceParent
+	}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.TraceFlags = flags[0]
+	if !sc.IsValid() {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+	return sc, nil
+}
+
+// decodeHex decodes n bytes of lowercase hex, as W3C trace context requires.
+func decodeHex(s string, n int) ([]byte, bool) {
+	if strings.ToLower(s) != s {
+		return nil, false
+	}
+	b, err := hex.DecodeString(s)
+	return b, err == nil && len(b) == n
+}
+
+// SpanKind tells a tracer how a span relates to its remote peers.
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = iota
+	SpanKindServer            // Handling a unary call from the core
+	SpanKindClient
+	SpanKindProducer // Sending a message on, e.g. to a device or the core
+	SpanKindConsumer // Handling a message received on a stream
+)
+
+// Span is an operation being traced.
+type Span interface {
+	SpanContext() SpanContext
+	SetAttributes(attrs ...slog.Attr)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans. Start must parent the span on SpanContextFromContext(ctx)
+// when it is valid. An OpenTelemetry implementation lives in the
+// otel/simsdkotel package.
+type Tracer interface {
+	Start(ctx context.Context, name string, kind SpanKind, attrs ...slog.Attr) (context.Context, Span)
+}
+
+// NoopTracer records nothing. Its spans carry their parent's span context, so
+// trace context still flows from inbound to outbound messages.
+type NoopTracer struct{}
+
+func (NoopTracer) Start(ctx context.Context, _ string, _ SpanKind, _ ...slog.Attr) (context.Context, Span) {
+	return ctx, noopSpan{sc: SpanContextFromContext(ctx)}
+}
+
+type noopSpan struct{ sc SpanContext }
+
+func (s noopSpan) SpanContext() SpanContext { return s.sc }
+func (noopSpan) SetAttributes(...slog.Attr) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+type tracerHolder struct{ Tracer }
+
+var defaultTracer atomic.Pointer[tracerHolder]
+
+// SetDefaultTracer sets the tracer used where none is configured, e.g. by
+// the transport base plugins. A nil tracer restores the NoopTracer.
+func SetDefaultTracer(t Tracer) {
+	if t == nil {
+		defaultTracer.Store(nil)
+		return
+	}
+	defaultTracer.Store(&tracerHolder{t})
+}
+
+// DefaultTracer returns the tracer set by SetDefaultTracer, or a NoopTracer.
+func DefaultTracer() Tracer {
+	if h := defaultTracer.Load(); h != nil {
+		return h.Tracer
+	}
+	return NoopTracer{}
+}
+
+// tracerOr returns t, or the default tracer when t is nil.
+func tracerOr(t Tracer) Tracer {
+	if t == nil {
+		return DefaultTracer()
+	}
+	return t
+}
+
+// WithTracer traces the adapter's unary messages and every MessageStream it
+// serves with t instead of the default tracer.
+func WithTracer(t Tracer) AdapterOption {
+	return func(g *grpcAdapter) {
+		g.tracer = t
+		g.streamOptions = append(g.streamOptions, WithStreamTracer(t))
+	}
+}
+
+// WithStreamTracer traces the messages handled by ServeStream with t instead
+// of the default tracer.
+func WithStreamTracer(t Tracer) StreamOption {
+	return func(o *streamOptions) { o.tracer = t }
+}
+
+type spanKey struct{}
+
+type remoteSpanKey struct{}
+
+// StartSpan starts a span with t and returns a context carrying it, so that
+// SpanContextFromContext and InjectTraceContext see the new span.
+func StartSpan(ctx context.Context, t Tracer, name string, kind SpanKind, attrs ...slog.Attr) (context.Context, Span) {
+	ctx, span := tracerOr(t).Start(ctx, name, kind, attrs...)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// ContextWithRemoteSpanContext returns a context whose parent span is sc, a
+// span in another process.
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteSpanKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context of the span started by
+// StartSpan in ctx, or else the remote span context in ctx.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		return span.SpanContext()
+	}
+	sc, _ := ctx.Value(remoteSpanKey{}).(SpanContext)
+	return sc
+}
+
+// InjectTraceContext returns a copy of metadata carrying the span context in
+// ctx. Metadata is returned unchanged when ctx carries no valid span context.
+func InjectTraceContext(ctx context.Context, metadata map[string]string) map[string]string {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return metadata
+	}
+	out := make(map[string]string, len(metadata)+2)
+	maps.Copy(out, metadata)
+	out[TraceParentKey] = sc.TraceParent()
+	delete(out, TraceStateKey)
+	if sc.TraceState != "" {
+		out[TraceStateKey] = sc.TraceState
+	}
+	return out
+}
+
+// ExtractTraceContext returns ctx with the span context in metadata as its
+// remote parent. Ctx is returned unchanged when metadata carries no valid
+// traceparent.
+func ExtractTraceContext(ctx context.Context, metadata map[string]string) context.Context {
+	sc, err := ParseTraceParent(metadata[TraceParentKey])
+	if err != nil {
+		return ctx
+	}
+	sc.TraceState = metadata[TraceStateKey]
+	return ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// extractGRPCTraceContext takes the remote parent from incoming gRPC metadata.
+func extractGRPCTraceContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get(TraceParentKey)) == 0 {
+		return ctx
+	}
+	carrier := map[string]string{TraceParentKey: md.Get(TraceParentKey)[0]}
+	if ts := md.Get(TraceStateKey); len(ts) > 0 {
+		carrier[TraceStateKey] = strings.Join(ts, ",")
+	}
+	return ExtractTraceContext(ctx, carrier)
+}
+
+// injectGRPCTraceContext adds the span context in ctx to outgoing gRPC metadata.
+func injectGRPCTraceContext(ctx context.Context) context.Context {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ctx
+	}
+	kv := []string{TraceParentKey, sc.TraceParent()}
+	if sc.TraceState != "" {
+		kv = append(kv, TraceStateKey, sc.TraceState)
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// UnaryClientTracingInterceptor sends the span context in the call's context
+// to the plugin as W3C trace context gRPC metadata.
+func UnaryClientTracingInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(injectGRPCTraceContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientTracingInterceptor does the same for streams such as MessageStream.
+func StreamClientTracingInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(injectGRPCTraceContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+// StartMessageSpan starts a span for handling or sending msg. The span's
+// parent is the trace context in msg's metadata, or else the one in ctx.
+// Msg's metadata is then pointed at the new span, so whatever the message
+// travels through next continues the trace from it.
+func StartMessageSpan(ctx context.Context, t Tracer, name string, kind SpanKind, msg *SimMessage) (context.Context, Span) {
+	if _, ok := msg.Metadata[TraceParentKey]; ok {
+		ctx = ExtractTraceContext(ctx, msg.Metadata)
+	}
+	ctx, span := StartSpan(ctx, t, name, kind,
+		slog.String("component_id", msg.ComponentID),
+		slog.String("message_id", msg.MessageID),
+		slog.String("message_type", msg.MessageType))
+	msg.Metadata = InjectTraceContext(ctx, msg.Metadata)
+	return ctx, span
+}
+
+// withTraceContext returns msg, or a copy of it carrying the span context in
+// ctx when msg has no trace context of its own.
+func withTraceContext(ctx context.Context, msg *SimMessage) *SimMessage {
+	if _, ok := msg.Metadata[TraceParentKey]; ok {
+		return msg
+	}
+	md := InjectTraceContext(ctx, msg.Metadata)
+	if _, ok := md[TraceParentKey]; !ok {
+		return msg
+	}
+	out := *msg
+	out.Metadata = md
+	return &out
+}
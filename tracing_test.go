@@ -0,0 +1,133 @@
+package simsdk
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	sc, err := ParseTraceParent(testTraceParent)
+	require.NoError(t, err)
+	require.True(t, sc.IsSampled())
+	require.Equal(t, testTraceParent, sc.TraceParent())
+
+	_, err = ParseTraceParent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future")
+	require.NoError(t, err, "later versions may append fields")
+
+	for _, bad := range []string{
+		"",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", // uppercase
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01", // zero trace ID
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", // zero span ID
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", // forbidden version
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-600f067aa0ba902b7-01",
+	} {
+		_, err := ParseTraceParent(bad)
+		require.ErrorIs(t, err, ErrInvalidTraceParent, bad)
+	}
+}
+
+// recordingTracer records the spans it starts. Span IDs count up from 1.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type recordedSpan struct {
+	name   string
+	kind   SpanKind
+	parent SpanContext
+	sc     SpanContext
+	err    error
+	ended  bool
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, kind SpanKind, _ ...slog.Attr) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parent := SpanContextFromContext(ctx)
+	s := &recordedSpan{name: name, kind: kind, parent: parent, sc: parent}
+	if !parent.IsValid() {
+		s.sc.TraceID[0] = 0xaa
+	}
+	s.sc.SpanID = [8]byte{7: byte(len(t.spans) + 1)}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+func (s *recordedSpan) SpanContext() SpanContext   { return s.sc }
+func (s *recordedSpan) SetAttributes(...slog.Attr) {}
+func (s *recordedSpan) RecordError(err error)      { s.err = err }
+func (s *recordedSpan) End()                       { s.ended = true }
+
+// tracedEnvelope is a SimMessage envelope carrying testTraceParent.
+func tracedEnvelope(id, componentID string) *simsdkrpc.PluginMessageEnvelope {
+	env := simMessageEnvelope(id, componentID)
+	env.GetSimMessage().Metadata = map[string]string{TraceParentKey: testTraceParent, TraceStateKey: "vendor=abc"}
+	return env
+}
+
+func responseTraceParent(t *testing.T, stream *mockStream, id string) string {
+	for _, env := range stream.sent {
+		if sm := env.GetSimMessage(); sm != nil && sm.MessageId == id {
+			return sm.Metadata[TraceParentKey]
+		}
+	}
+	t.Fatalf("no response %s", id)
+	return ""
+}
+
+func TestServeStream_TracesOnSimMessage(t *testing.T) {
+	tracer := &recordingTracer{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{initEnvelope("a"), tracedEnvelope("m1", "a")}}
+	require.NoError(t, ServeStream(&echoHandler{}, stream, WithStreamTracer(tracer)))
+
+	require.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	require.Equal(t, "simsdk.OnSimMessage", span.name)
+	require.Equal(t, SpanKindConsumer, span.kind)
+	require.Equal(t, testTraceParent, span.parent.TraceParent())
+	require.Equal(t, "vendor=abc", span.parent.TraceState)
+	require.True(t, span.ended)
+	require.Equal(t, span.sc.TraceParent(), responseTraceParent(t, stream, "echo-m1"), "responses continue the handler's span")
+}
+
+func TestServeStream_NoopTracerPropagatesTraceContext(t *testing.T) {
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{initEnvelope("a"), tracedEnvelope("m1", "a")}}
+	require.NoError(t, ServeStream(&echoHandler{}, stream))
+	require.Equal(t, testTraceParent, responseTraceParent(t, stream, "echo-m1"))
+}
+
+func TestGRPCAdapter_TracesHandleMessageFromGRPCMetadata(t *testing.T) {
+	tracer := &recordingTracer{}
+	adapter := NewGRPCAdapter(&dummyPlugin{}, WithTracer(tracer))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(TraceParentKey, testTraceParent))
+
+	resp, err := adapter.HandleMessage(ctx, &simsdkrpc.SimMessage{MessageId: "m1", ComponentId: "c1"})
+	require.NoError(t, err)
+	require.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	require.Equal(t, "simsdk.HandleMessage", span.name)
+	require.Equal(t, SpanKindServer, span.kind)
+	require.Equal(t, testTraceParent, span.parent.TraceParent())
+	require.Equal(t, span.sc.TraceParent(), resp.OutboundMessages[0].Metadata[TraceParentKey])
+}
+
+func TestInjectAndExtractTraceContext(t *testing.T) {
+	ctx := ExtractTraceContext(context.Background(), map[string]string{TraceParentKey: testTraceParent, TraceStateKey: "k=v"})
+	md := InjectTraceContext(ctx, map[string]string{"x": "y"})
+	require.Equal(t, map[string]string{"x": "y", TraceParentKey: testTraceParent, TraceStateKey: "k=v"}, md)
+
+	original := map[string]string{"x": "y"}
+	require.Equal(t, original, InjectTraceContext(context.Background(), original), "no trace context, no change")
+	require.Equal(t, context.Background(), ExtractTraceContext(context.Background(), map[string]string{TraceParentKey: "garbage"}))
+}
@@ -10,36 +10,115 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/neurosimio/simsdk-go"
 	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
 	"github.com/neurosimio/simsdk-go/transport/registration"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
+// DefaultHealthInterval is how often readiness checks are re-evaluated.
+const DefaultHealthInterval = 5 * time.Second
+
+// ServeOption customizes ServePluginWithRegistration.
+type ServeOption func(*serveOptions)
+
+type serveOptions struct {
+	healthInterval time.Duration
+	checks         map[string]simsdk.ReadinessCheck
+	adapterOptions []simsdk.AdapterOption
+	metrics        *simsdk.Metrics
+	metricsAddr    string
+	logger         *slog.Logger
+}
+
+// WithLogger logs through logger, taking precedence over the *log.Logger
+// argument of ServePluginWithRegistration. The logger is also passed to the
+// registration and to the adapter; see simsdk.WithLogger.
+func WithLogger(logger *slog.Logger) ServeOption {
+	return func(o *serveOptions) { o.logger = logger }
+}
+
+// WithAdapterOptions passes options through to simsdk.NewGRPCAdapter.
+func WithAdapterOptions(opts ...simsdk.AdapterOption) ServeOption {
+	return func(o *serveOptions) { o.adapterOptions = append(o.adapterOptions, opts...) }
+}
+
+// WithCompression negotiates payload compression on every MessageStream the
+// plugin serves; see simsdk.WithCompression.
+func WithCompression(opts simsdk.CompressionOptions) ServeOption {
+	return WithAdapterOptions(simsdk.WithStreamOptions(simsdk.WithCompression(opts)))
+}
+
+// WithMetrics records the SDK's metrics into m, or into a new registry when m
+// is nil, and serves them in the Prometheus text format at /metrics on addr.
+// An empty addr records without serving, e.g. when the plugin exposes m itself.
+// Pass the same registry to Forwarders to count their drops.
+func WithMetrics(m *simsdk.Metrics, addr string) ServeOption {
+	return func(o *serveOptions) {
+		if m == nil {
+			m = simsdk.NewMetrics()
+		}
+		o.metrics, o.metricsAddr = m, addr
+	}
+}
+
+// WithHealthInterval sets how often readiness checks and component health are refreshed.
+func WithHealthInterval(d time.Duration) ServeOption {
+	return func(o *serveOptions) { o.healthInterval = d }
+}
+
+// WithReadinessCheck adds a named readiness check; the plugin reports NOT_SERVING
+// while any check fails.
+func WithReadinessCheck(name string, check simsdk.ReadinessCheck) ServeOption {
+	return func(o *serveOptions) { o.checks[name] = check }
+}
+
+// ServePluginWithRegistration registers the plugin with the core and serves it
+// until ctx ends or the process is signalled. It logs through the WithLogger
+// option when given, else through logger; a nil logger uses slog.Default().
 func ServePluginWithRegistration(
 	ctx context.Context,
 	plugin simsdk.PluginWithHandlers,
 	cfg registration.RegistrationConfig,
 	httpClient registration.HTTPClient,
 	logger *log.Logger,
+	opts ...ServeOption,
 ) error {
+	o := serveOptions{
+		healthInterval: DefaultHealthInterval,
+		checks:         make(map[string]simsdk.ReadinessCheck),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	slogger := o.logger
+	if slogger == nil {
+		slogger = simsdk.LoggerFromLog(logger)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	sigc := make(chan os.Signal, 1)
 	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
-	go func() { <-sigc; logger.Printf("🛑 shutdown: %s", cfg.PluginName); cancel() }()
+	pluginLog := slogger.With(slog.String("plugin", cfg.PluginName))
+	go func() { <-sigc; pluginLog.Info("Shutdown signal received"); cancel() }()
 
 	init := &registration.TransportInitializer{
 		Config:     cfg,
 		HTTPClient: httpClient,
-		Logger:     logger,
+		Slog:       slogger,
+		Metrics:    o.metrics,
 	}
 	port, err := init.Init(ctx)
 	if err != nil {
@@ -52,20 +131,63 @@ func ServePluginWithRegistration(
 		return fmt.Errorf("listen failed: %w", err)
 	}
 
+	// Health reports NOT_SERVING until the server is up and again while draining.
+	monitor := simsdk.NewHealthMonitor(plugin)
+	for name, check := range o.checks {
+		monitor.AddCheck(name, check)
+	}
+
 	grpcServer := grpc.NewServer()
-	simsdkrpc.RegisterPluginServiceServer(grpcServer, simsdk.NewGRPCAdapter(plugin))
+	adapterOptions := []simsdk.AdapterOption{simsdk.WithLogger(slogger)}
+	if o.metrics != nil {
+		adapterOptions = append(adapterOptions, simsdk.WithMetrics(o.metrics))
+	}
+	adapterOptions = append(adapterOptions, o.adapterOptions...)
+	simsdkrpc.RegisterPluginServiceServer(grpcServer, simsdk.NewGRPCAdapter(plugin, adapterOptions...))
+	healthpb.RegisterHealthServer(grpcServer, monitor.Server())
 	reflection.Register(grpcServer)
 
 	go func() {
-		logger.Printf("🚀 %s listening on %s", cfg.PluginName, addr)
+		pluginLog.Info("Plugin listening", slog.String("addr", addr))
 		if err := grpcServer.Serve(lis); err != nil {
-			logger.Printf("❌ gRPC serve error: %v", err)
+			pluginLog.Error("gRPC serve failed", slog.Any("error", err))
 			cancel()
 		}
 	}()
 
+	if o.metricsAddr != "" {
+		metricsServer, err := serveMetrics(o.metrics, o.metricsAddr, pluginLog)
+		if err != nil {
+			grpcServer.Stop()
+			return err
+		}
+		defer metricsServer.Close()
+	}
+
+	monitor.SetReady(true)
+	go monitor.Run(ctx, o.healthInterval)
+
 	<-ctx.Done()
-	logger.Printf("🛑 stopping gRPC server: %s", cfg.PluginName)
+	pluginLog.Info("Stopping gRPC server")
+	monitor.Shutdown()
 	grpcServer.GracefulStop()
 	return nil
 }
+
+// serveMetrics serves m at /metrics on addr until the returned server is closed.
+func serveMetrics(m *simsdk.Metrics, addr string, logger *slog.Logger) (*http.Server, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("metrics listen failed: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		logger.Info("Serving metrics", slog.String("addr", lis.Addr().String()))
+		if err := srv.Serve(lis); err != nil && err != http.ErrServerClosed {
+			logger.Error("Metrics server failed", slog.Any("error", err))
+		}
+	}()
+	return srv, nil
+}
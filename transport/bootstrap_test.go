@@ -0,0 +1,136 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go"
+	"github.com/neurosimio/simsdk-go/transport/registration"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type fakeCore struct{ port int }
+
+func (c *fakeCore) Do(req *http.Request) (*http.Response, error) {
+	body := "{}"
+	if req.URL.Path == "/allocate" {
+		body = fmt.Sprintf(`{"port": %d, "ip": "127.0.0.1"}`, c.port)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+	return lis.Addr().(*net.TCPAddr).Port
+}
+
+func TestServePluginWithRegistration_Health(t *testing.T) {
+	port := freePort(t)
+	plugin := NewSenderPlugin(
+		simsdk.Manifest{Name: "health-test"},
+		func(simsdk.CreateComponentRequest) TransportSender { return &mockSender{} },
+		nil,
+	)
+	require.NoError(t, plugin.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "s1"}))
+
+	ready := make(chan bool, 1)
+	ready <- false
+	check := func(context.Context) error {
+		ok := <-ready
+		ready <- ok
+		if !ok {
+			return fmt.Errorf("not yet")
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- ServePluginWithRegistration(ctx, plugin,
+			registration.RegistrationConfig{PluginName: "health-test", CoreAPIBaseURL: "http://core"},
+			&fakeCore{port: port},
+			log.New(io.Discard, "", 0),
+			WithHealthInterval(10*time.Millisecond),
+			WithReadinessCheck("gate", check),
+		)
+	}()
+
+	conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	statusOf := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return healthpb.HealthCheckResponse_UNKNOWN
+		}
+		return resp.Status
+	}
+
+	require.Eventually(t, func() bool {
+		return statusOf("") == healthpb.HealthCheckResponse_NOT_SERVING
+	}, 2*time.Second, 10*time.Millisecond)
+
+	<-ready
+	ready <- true
+	require.Eventually(t, func() bool {
+		return statusOf("") == healthpb.HealthCheckResponse_SERVING &&
+			statusOf(simsdk.ComponentHealthService("s1")) == healthpb.HealthCheckResponse_SERVING
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestServePluginWithRegistration_Metrics(t *testing.T) {
+	port, metricsPort := freePort(t), freePort(t)
+	plugin := NewSenderPlugin(
+		simsdk.Manifest{Name: "metrics-test"},
+		func(simsdk.CreateComponentRequest) TransportSender { return &mockSender{} },
+		nil,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- ServePluginWithRegistration(ctx, plugin,
+			registration.RegistrationConfig{PluginName: "metrics-test", CoreAPIBaseURL: "http://core"},
+			&fakeCore{port: port},
+			nil,
+			WithLogger(slog.New(slog.DiscardHandler)),
+			WithMetrics(nil, fmt.Sprintf("127.0.0.1:%d", metricsPort)),
+		)
+	}()
+
+	var body string
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/metrics", metricsPort))
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		body = string(b)
+		return resp.StatusCode == http.StatusOK
+	}, 2*time.Second, 10*time.Millisecond)
+	require.Contains(t, body, `simsdk_registration_status{plugin="metrics-test"} 1`)
+
+	cancel()
+	require.NoError(t, <-done)
+}
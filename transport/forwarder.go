@@ -27,37 +27,91 @@
 //	fwd := &transport.Forwarder{Log: logger}
 //	fwd.Start(ctx, receiver, streamSender, nil)
 //
-// This will forward all inbound messages without transformation.
+// This will forward all inbound messages without transformation. Set
+// Pauses (usually the plugin returned by NewReceiverPlugin) to drop
+// messages while the sender's component is paused; the plugin then also
+// counts the forwarded messages in the component's status, and the
+// Forwarder follows the receiver when a reset or restore replaces it.
 package transport
 
 import (
 	"context"
 	"log"
+	"log/slog"
 
 	"github.com/neurosimio/simsdk-go"
 )
 
 // Forwarder relays messages from a TransportReceiver to core via a StreamSender.
 type Forwarder struct {
-	Log *log.Logger
+	Log     *log.Logger
+	Slog    *slog.Logger    // Optional: preferred over Log; defaults to slog.Default(); each message is logged at debug level
+	Pauses  PauseChecker    // Optional: messages are dropped while the component is paused
+	Metrics *simsdk.Metrics // Optional: counts dropped messages as simsdk_forwarder_dropped_total
+	Tracer  simsdk.Tracer   // Optional: traces each send; defaults to simsdk.DefaultTracer()
+	Counter MessageCounter  // Optional: counts relayed messages; defaults to Pauses when it is a MessageCounter
+	// Optional: follows rcv when it is replaced; defaults to Pauses when it is a ReceiverSource
+	Receivers ReceiverSource
 }
 
 // Start launches a goroutine that forwards messages until ctx is cancelled or the channel closes.
+// With a ReceiverSource, a closed channel instead waits for the receiver to be replaced.
 func (f *Forwarder) Start(
 	ctx context.Context,
 	rcv TransportReceiver,
 	snd simsdk.StreamSender,
 	transform func(*simsdk.SimMessage) *simsdk.SimMessage,
 ) {
+	logger := f.Slog
+	if logger == nil {
+		logger = simsdk.LoggerFromLog(f.Log)
+	}
+	logger = logger.With(slog.String("component_id", snd.ComponentID()))
+	counter := f.Counter
+	if counter == nil {
+		counter, _ = f.Pauses.(MessageCounter)
+	}
+	source := f.Receivers
+	if source == nil {
+		source, _ = f.Pauses.(ReceiverSource)
+	}
+	var replaced <-chan struct{}
+	if source != nil {
+		_, replaced = source.Receiver(snd.ComponentID())
+	}
+	dropped := f.Metrics.Counter("simsdk_forwarder_dropped_total", "Messages the forwarder did not deliver to the core.", "component_id", "reason")
+
 	go func() {
 		ch := rcv.GetInboundChan()
 		for {
 			select {
 			case <-ctx.Done():
 				return
+			case <-replaced:
+				var next TransportReceiver
+				next, replaced = source.Receiver(snd.ComponentID())
+				ch = nil
+				if next != nil {
+					ch = next.GetInboundChan()
+				}
+				logger.Debug("Following replaced receiver", slog.Bool("live", next != nil))
 			case m, ok := <-ch:
 				if !ok {
-					return
+					if replaced == nil {
+						return
+					}
+					// a stopped receiver may be about to be replaced
+					ch = nil
+					continue
+				}
+				if counter != nil {
+					counter.MessageReceived(snd.ComponentID())
+				}
+
+				if f.Pauses != nil && f.Pauses.IsPaused(snd.ComponentID()) {
+					logger.Debug("Dropping message while paused", slog.String("message_id", m.MessageID), slog.String("message_type", m.MessageType))
+					dropped.Inc(snd.ComponentID(), "paused")
+					continue
 				}
 
 				// Work with a pointer
@@ -70,8 +124,22 @@ func (f *Forwarder) Start(
 					}
 				}
 
-				// Send the final message
-				_ = snd.Send(msg)
+				// Send the final message, continuing the trace it arrived with
+				_, span := simsdk.StartMessageSpan(ctx, f.Tracer, "simsdk.Forwarder.Send", simsdk.SpanKindProducer, msg)
+				err := snd.Send(msg)
+				if err != nil {
+					span.RecordError(err)
+				}
+				span.End()
+				if counter != nil {
+					counter.MessageForwarded(snd.ComponentID(), err)
+				}
+				if err != nil {
+					logger.Warn("Failed to forward message", slog.String("message_id", msg.MessageID), slog.String("message_type", msg.MessageType), slog.Any("error", err))
+					dropped.Inc(snd.ComponentID(), "send_failed")
+					continue
+				}
+				logger.Debug("Forwarded message", slog.String("message_id", msg.MessageID), slog.String("message_type", msg.MessageType))
 			}
 		}
 	}()
@@ -2,6 +2,8 @@ package transport
 
 import (
 	"context"
+	"log/slog"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -95,3 +97,60 @@ func TestForwarder_BasicAndTransformAndCancel(t *testing.T) {
 	require.Equal(t, "y", snd.log[0].Metadata["x"])
 	snd.mu.Unlock()
 }
+
+type failingSender struct{ sinkSender }
+
+func (s *failingSender) Send(*simsdk.SimMessage) error { return simsdk.ErrStreamClosed }
+
+func TestForwarder_CountsDrops(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	metrics := simsdk.NewMetrics()
+	rcv := &chanReceiver{ch: make(chan simsdk.SimMessage, 4)}
+	(&Forwarder{Metrics: metrics}).Start(ctx, rcv, &failingSender{}, nil)
+	rcv.ch <- simsdk.SimMessage{MessageID: "a"}
+	rcv.ch <- simsdk.SimMessage{MessageID: "b"}
+
+	require.Eventually(t, func() bool {
+		var buf strings.Builder
+		require.NoError(t, metrics.WritePrometheus(&buf))
+		return strings.Contains(buf.String(), `simsdk_forwarder_dropped_total{component_id="sink",reason="send_failed"} 2`)
+	}, time.Second, 10*time.Millisecond)
+}
+
+// spanTracer starts spans that keep their parent's trace and use span ID 1.
+type spanTracer struct{ names chan string }
+
+func (t *spanTracer) Start(ctx context.Context, name string, _ simsdk.SpanKind, _ ...slog.Attr) (context.Context, simsdk.Span) {
+	t.names <- name
+	sc := simsdk.SpanContextFromContext(ctx)
+	sc.SpanID = [8]byte{7: 1}
+	return ctx, fixedSpan{sc}
+}
+
+type fixedSpan struct{ sc simsdk.SpanContext }
+
+func (s fixedSpan) SpanContext() simsdk.SpanContext { return s.sc }
+func (fixedSpan) SetAttributes(...slog.Attr)        {}
+func (fixedSpan) RecordError(error)                 {}
+func (fixedSpan) End()                              {}
+
+func TestForwarder_TracesSends(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tracer := &spanTracer{names: make(chan string, 1)}
+	rcv := &chanReceiver{ch: make(chan simsdk.SimMessage, 1)}
+	snd := &sinkSender{}
+	(&Forwarder{Tracer: tracer}).Start(ctx, rcv, snd, nil)
+	rcv.ch <- simsdk.SimMessage{MessageID: "a", Metadata: map[string]string{
+		simsdk.TraceParentKey: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}}
+
+	require.Equal(t, "simsdk.Forwarder.Send", <-tracer.names)
+	require.Eventually(t, func() bool {
+		snd.mu.Lock()
+		defer snd.mu.Unlock()
+		return len(snd.log) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000001-01", snd.log[0].Metadata[simsdk.TraceParentKey])
+}
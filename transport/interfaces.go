@@ -36,3 +36,57 @@ type StreamHandlerFactory func() simsdk.StreamHandler
 type FullSender interface {
 	SendSim(ctx context.Context, msg simsdk.SimMessage) error
 }
+
+// StatusReporter may be implemented by a TransportSender or TransportReceiver to
+// contribute plugin-defined fields (e.g., connection state) to ComponentStatus.
+type StatusReporter interface {
+	Status() map[string]string
+}
+
+// Pausable may be implemented by a TransportSender or TransportReceiver that can
+// suspend its own I/O. The base plugins call it on PauseComponent/ResumeComponent.
+type Pausable interface {
+	Pause(ctx context.Context) error
+	Resume(ctx context.Context) error
+}
+
+// Resettable may be implemented by a TransportSender or TransportReceiver that can
+// return to its initial state in place. Without it, the base plugins reset a
+// component by closing it and creating a fresh instance from the original request.
+type Resettable interface {
+	Reset(ctx context.Context) error
+}
+
+// PauseChecker reports whether a component is paused. The base sender and
+// receiver plugins implement it so a Forwarder can stop forwarding while paused.
+type PauseChecker interface {
+	IsPaused(componentID string) bool
+}
+
+// MessageCounter counts the messages a Forwarder relays for a component. The
+// base receiver plugin implements it, so its MessagesReceived and MessagesSent
+// report the messages its receivers forwarded.
+type MessageCounter interface {
+	// MessageReceived counts a message the receiver delivered to the forwarder.
+	MessageReceived(componentID string)
+	// MessageForwarded counts a message sent to the core, or records err when
+	// the send failed.
+	MessageForwarded(componentID string, err error)
+}
+
+// ReceiverSource reports the live receiver for a component. The base receiver
+// plugin implements it so a Forwarder follows a receiver that a reset or
+// restore replaced with a new instance.
+type ReceiverSource interface {
+	// Receiver returns the live receiver for componentID, or nil when there
+	// is none, and a channel that is closed when the instance is next replaced.
+	Receiver(componentID string) (TransportReceiver, <-chan struct{})
+}
+
+// StateSnapshotter may be implemented by a TransportSender or TransportReceiver
+// whose internal state should survive a snapshot/restore. Without it, the base
+// plugins restore a component by recreating it from its original request.
+type StateSnapshotter interface {
+	SnapshotState(ctx context.Context) (version uint32, state []byte, err error)
+	RestoreState(ctx context.Context, version uint32, state []byte) error
+}
@@ -0,0 +1,225 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSenderPlugin_PauseResumeReset(t *testing.T) {
+	var created []*mockSender
+	p := NewSenderPlugin(
+		simsdk.Manifest{},
+		func(simsdk.CreateComponentRequest) TransportSender {
+			s := &mockSender{}
+			created = append(created, s)
+			return s
+		},
+		nil,
+	)
+	lc := p.(simsdk.ComponentLifecycle)
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "s1", ComponentType: "amqp"}))
+
+	require.NoError(t, lc.PauseComponent("s1"))
+	require.True(t, p.(PauseChecker).IsPaused("s1"))
+	_, err := p.HandleMessage(simsdk.SimMessage{ComponentID: "s1"})
+	require.ErrorIs(t, err, simsdk.ErrFailedPrecondition)
+	require.ErrorIs(t, lc.PauseComponent("s1"), simsdk.ErrFailedPrecondition)
+
+	require.NoError(t, lc.ResumeComponent("s1"))
+	_, err = p.HandleMessage(simsdk.SimMessage{ComponentID: "s1", MessageType: "t"})
+	require.NoError(t, err)
+
+	require.NoError(t, lc.ResetComponent("s1"))
+	require.Len(t, created, 2)
+	require.True(t, created[0].closed)
+	require.True(t, created[1].started)
+
+	st, err := p.(simsdk.ComponentStatusProvider).GetComponentStatus("s1")
+	require.NoError(t, err)
+	require.Equal(t, simsdk.ComponentRunning, st.State)
+	require.Zero(t, st.MessagesReceived)
+	require.Equal(t, "amqp", st.ComponentType)
+
+	require.ErrorIs(t, lc.PauseComponent("missing"), simsdk.ErrNotFound)
+}
+
+type resettableReceiver struct {
+	mockReceiver
+	resets int
+}
+
+func (r *resettableReceiver) Reset(context.Context) error { r.resets++; return nil }
+
+func TestReceiverPlugin_PauseStopsForwarding(t *testing.T) {
+	rcv := &resettableReceiver{mockReceiver: mockReceiver{ch: make(chan simsdk.SimMessage, 4)}}
+	p := NewReceiverPlugin(
+		simsdk.Manifest{},
+		func(simsdk.CreateComponentRequest) TransportReceiver { return rcv },
+		nil,
+	)
+	lc := p.(simsdk.ComponentLifecycle)
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "sink"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	snd := &sinkSender{}
+	(&Forwarder{Pauses: p.(PauseChecker)}).Start(ctx, rcv, snd, nil)
+
+	require.NoError(t, lc.PauseComponent("sink"))
+	rcv.ch <- simsdk.SimMessage{MessageID: "dropped"}
+	time.Sleep(50 * time.Millisecond)
+
+	require.NoError(t, lc.ResumeComponent("sink"))
+	rcv.ch <- simsdk.SimMessage{MessageID: "kept"}
+	require.Eventually(t, func() bool {
+		snd.mu.Lock()
+		defer snd.mu.Unlock()
+		return len(snd.log) == 1
+	}, 200*time.Millisecond, 10*time.Millisecond)
+
+	snd.mu.Lock()
+	require.Equal(t, "kept", snd.log[0].MessageID)
+	snd.mu.Unlock()
+
+	require.NoError(t, lc.ResetComponent("sink"))
+	require.Equal(t, 1, rcv.resets)
+}
+
+// strictSender fails sends made after it was closed. It starts slowly, which
+// widens the window in which a reset races other calls.
+type strictSender struct{ closed atomic.Bool }
+
+func (s *strictSender) Start(context.Context) error { time.Sleep(time.Millisecond); return nil }
+func (s *strictSender) SendSim(context.Context, simsdk.SimMessage) error {
+	if s.closed.Load() {
+		return errors.New("send on closed sender")
+	}
+	return nil
+}
+func (s *strictSender) Close(context.Context) error { s.closed.Store(true); return nil }
+
+func TestSenderPlugin_ResetRacingDestroyAndMessages(t *testing.T) {
+	p := NewSenderPlugin(simsdk.Manifest{}, func(simsdk.CreateComponentRequest) TransportSender { return &strictSender{} }, nil)
+	lc := p.(simsdk.ComponentLifecycle)
+
+	for range 50 {
+		require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "s1"}))
+		var wg sync.WaitGroup
+		wg.Add(6)
+		go func() { defer wg.Done(); _ = lc.ResetComponent("s1") }()
+		go func() { defer wg.Done(); _ = p.DestroyComponentInstance("s1") }()
+		for range 4 {
+			go func() {
+				defer wg.Done()
+				_, err := p.HandleMessage(simsdk.SimMessage{ComponentID: "s1"})
+				if err != nil {
+					assert.ErrorIs(t, err, simsdk.ErrNotFound)
+				}
+			}()
+		}
+		wg.Wait()
+
+		_, err := p.HandleMessage(simsdk.SimMessage{ComponentID: "s1"})
+		require.ErrorIs(t, err, simsdk.ErrNotFound, "a reset must not resurrect a destroyed sender")
+	}
+}
+
+func TestReceiverPlugin_ResetMovesForwarderToNewReceiver(t *testing.T) {
+	var mu sync.Mutex
+	var created []*chanReceiver
+	p := NewReceiverPlugin(
+		simsdk.Manifest{},
+		func(simsdk.CreateComponentRequest) TransportReceiver {
+			mu.Lock()
+			defer mu.Unlock()
+			r := &chanReceiver{ch: make(chan simsdk.SimMessage, 4)}
+			created = append(created, r)
+			return r
+		},
+		nil,
+	)
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "sink"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	snd := &sinkSender{}
+	(&Forwarder{Pauses: p.(PauseChecker)}).Start(ctx, created[0], snd, nil)
+
+	require.NoError(t, p.(simsdk.ComponentLifecycle).ResetComponent("sink"))
+	mu.Lock()
+	require.Len(t, created, 2)
+	fresh := created[1]
+	mu.Unlock()
+
+	fresh.ch <- simsdk.SimMessage{MessageID: "after-reset"}
+	require.Eventually(t, func() bool {
+		snd.mu.Lock()
+		defer snd.mu.Unlock()
+		return len(snd.log) == 1 && snd.log[0].MessageID == "after-reset"
+	}, time.Second, 10*time.Millisecond)
+}
+
+// slowStopReceiver blocks in Stop until release is closed.
+type slowStopReceiver struct {
+	chanReceiver
+	stopping chan struct{}
+	release  chan struct{}
+}
+
+func (r *slowStopReceiver) Stop(ctx context.Context) error {
+	close(r.stopping)
+	<-r.release
+	return r.chanReceiver.Stop(ctx)
+}
+
+func TestReceiverPlugin_ResetDoesNotBlockOtherComponents(t *testing.T) {
+	slow := &slowStopReceiver{
+		chanReceiver: chanReceiver{ch: make(chan simsdk.SimMessage)},
+		stopping:     make(chan struct{}),
+		release:      make(chan struct{}),
+	}
+	p := NewReceiverPlugin(
+		simsdk.Manifest{},
+		func(req simsdk.CreateComponentRequest) TransportReceiver {
+			if req.ComponentID == "slow" && slow != nil {
+				r := slow
+				slow = nil
+				return r
+			}
+			return &chanReceiver{ch: make(chan simsdk.SimMessage)}
+		},
+		nil,
+	)
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "slow"}))
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "other"}))
+	r, _ := p.(ReceiverSource).Receiver("slow")
+	stuck := r.(*slowStopReceiver)
+
+	done := make(chan error, 1)
+	go func() { done <- p.(simsdk.ComponentLifecycle).ResetComponent("slow") }()
+	<-stuck.stopping
+
+	checked := make(chan struct{})
+	go func() {
+		defer close(checked)
+		p.(PauseChecker).IsPaused("other")
+		p.(MessageCounter).MessageReceived("other")
+		_, _ = p.(simsdk.ComponentStatusProvider).GetComponentStatus("other")
+	}()
+	select {
+	case <-checked:
+	case <-time.After(time.Second):
+		t.Fatal("a reset in progress blocked calls for another component")
+	}
+
+	close(stuck.release)
+	require.NoError(t, <-done)
+}
@@ -8,7 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+
+	"github.com/neurosimio/simsdk-go"
 )
 
 // Registrar is implemented by anything that can register a service (e.g., Consul registrar plugin).
@@ -41,7 +44,17 @@ type TransportInitializer struct {
 	Config     RegistrationConfig
 	HTTPClient HTTPClient
 	Logger     *log.Logger
-	Registrar  Registrar // Optional: for Consul or other service registration
+	Slog       *slog.Logger    // Optional: preferred over Logger; defaults to slog.Default() when both are nil
+	Registrar  Registrar       // Optional: for Consul or other service registration
+	Metrics    *simsdk.Metrics // Optional: records simsdk_registration_status
+}
+
+func (t *TransportInitializer) log() *slog.Logger {
+	logger := t.Slog
+	if logger == nil {
+		logger = simsdk.LoggerFromLog(t.Logger)
+	}
+	return logger.With(slog.String("plugin", t.Config.PluginName))
 }
 
 // Init retrieves a port and IP from core, optionally registers with Consul, and informs core of the final plugin address.
@@ -50,6 +63,9 @@ func (t *TransportInitializer) Init(ctx context.Context) (int, error) {
 		return 0, fmt.Errorf("registration: ServiceAddress is required for Consul registration")
 	}
 
+	registered := t.Metrics.Gauge("simsdk_registration_status", "1 if the plugin is registered with the core, 0 otherwise.", "plugin")
+	registered.Set(0, t.Config.PluginName)
+
 	assignment, err := t.fetchPortFromCore(ctx)
 	if err != nil {
 		return 0, err
@@ -62,10 +78,10 @@ func (t *TransportInitializer) Init(ctx context.Context) (int, error) {
 			PluginType:     t.Config.PluginType,
 			CoreAPIBaseURL: t.Config.CoreAPIBaseURL,
 		})
-		t.Logger.Printf("[registration] 🧭 Consul registration started for %s at %s:%d",
-			t.Config.PluginName, t.Config.ServiceAddress, assignment.Port)
+		t.log().Info("Consul registration started",
+			slog.String("address", t.Config.ServiceAddress), slog.Int("port", assignment.Port))
 	} else {
-		t.Logger.Printf("[registration] ℹ️ Consul registration disabled for %s", t.Config.PluginName)
+		t.log().Info("Consul registration disabled")
 	}
 
 	ipToUse := t.Config.ServiceAddress
@@ -74,7 +90,9 @@ func (t *TransportInitializer) Init(ctx context.Context) (int, error) {
 	}
 
 	if err := t.registerWithCore(ctx, ipToUse, assignment.Port); err != nil {
-		t.Logger.Printf("[registration] ❌ Failed to register with core: %v", err)
+		t.log().Error("Failed to register with core", slog.Any("error", err))
+	} else {
+		registered.Set(1, t.Config.PluginName)
 	}
 
 	return assignment.Port, nil
@@ -104,8 +122,8 @@ func (t *TransportInitializer) fetchPortFromCore(ctx context.Context) (PortAssig
 		return PortAssignment{}, fmt.Errorf("failed to decode response from core: %w", err)
 	}
 
-	t.Logger.Printf("[registration] 📦 Received port %d and IP %s from core for plugin %s",
-		result.Port, result.IP, t.Config.PluginName)
+	t.log().Info("Received port assignment from core",
+		slog.Int("port", result.Port), slog.String("ip", result.IP))
 	return result, nil
 }
 
@@ -140,7 +158,6 @@ func (t *TransportInitializer) registerWithCore(ctx context.Context, ip string,
 		return fmt.Errorf("unexpected response from core register: %s", resp.Status)
 	}
 
-	t.Logger.Printf("[registration] 📡 Plugin %s registered with core at %s:%d",
-		t.Config.PluginName, ip, port)
+	t.log().Info("Registered with core", slog.String("ip", ip), slog.Int("port", port))
 	return nil
 }
@@ -0,0 +1,72 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/neurosimio/simsdk-go"
+)
+
+// snapshotInstance captures inst, including its state when it implements
+// StateSnapshotter.
+func snapshotInstance(componentID string, req simsdk.CreateComponentRequest, inst any) (simsdk.ComponentSnapshot, error) {
+	snap := simsdk.ComponentSnapshot{ComponentID: componentID, CreateRequest: req}
+	if ss, ok := inst.(StateSnapshotter); ok {
+		version, state, err := ss.SnapshotState(context.Background())
+		if err != nil {
+			return simsdk.ComponentSnapshot{}, err
+		}
+		snap.Version, snap.State = version, state
+	}
+	return snap, nil
+}
+
+// restorer is implemented by the base plugins so restoreInstance can reach
+// their instances.
+type restorer interface {
+	simsdk.PluginWithHandlers
+	instance(componentID string) any
+	markStopped(req simsdk.CreateComponentRequest, err error)
+}
+
+// restoreInstance recreates snap.ComponentID from its creation request,
+// replacing any live instance, then loads the saved state into the new
+// instance. The request is checked against the manifest before the live
+// instance is destroyed; a replacement that cannot be created leaves the
+// component listed as Stopped.
+func restoreInstance(p restorer, snap simsdk.ComponentSnapshot) error {
+	req := snap.CreateRequest
+	req.ComponentID = snap.ComponentID
+	if err := validateComponentType(p.GetManifest(), req); err != nil {
+		return err
+	}
+	if err := p.DestroyComponentInstance(req.ComponentID); err != nil {
+		return err
+	}
+	if err := p.CreateComponentInstance(req); err != nil {
+		p.markStopped(req, err)
+		return err
+	}
+	if len(snap.State) == 0 {
+		return nil
+	}
+	ss, ok := p.instance(req.ComponentID).(StateSnapshotter)
+	if !ok {
+		return simsdk.NewError(simsdk.ErrFailedPrecondition, req.ComponentID,
+			"component %q was recreated but cannot restore its saved state", req.ComponentID)
+	}
+	return ss.RestoreState(context.Background(), snap.Version, snap.State)
+}
+
+// validateComponentType rejects a request for a component type the manifest
+// does not declare. Manifests without component types accept any type.
+func validateComponentType(m simsdk.Manifest, req simsdk.CreateComponentRequest) error {
+	if len(m.ComponentTypes) == 0 {
+		return nil
+	}
+	for _, ct := range m.ComponentTypes {
+		if ct.ID == req.ComponentType {
+			return nil
+		}
+	}
+	return simsdk.NewError(simsdk.ErrInvalidArgument, req.ComponentID, "unknown component type %q", req.ComponentType)
+}
@@ -0,0 +1,156 @@
+package transport
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go"
+	"github.com/stretchr/testify/require"
+)
+
+// statefulSender remembers how many messages it sent across snapshots.
+type statefulSender struct {
+	mockSender
+	sent byte
+}
+
+func (s *statefulSender) SendSim(ctx context.Context, msg simsdk.SimMessage) error {
+	s.sent++
+	return s.mockSender.SendSim(ctx, msg)
+}
+
+func (s *statefulSender) SnapshotState(context.Context) (uint32, []byte, error) {
+	return 1, []byte{s.sent}, nil
+}
+
+func (s *statefulSender) RestoreState(_ context.Context, _ uint32, state []byte) error {
+	s.sent = state[0]
+	return nil
+}
+
+func TestSenderPlugin_SnapshotRestoresState(t *testing.T) {
+	var created []*statefulSender
+	p := NewSenderPlugin(simsdk.Manifest{}, func(simsdk.CreateComponentRequest) TransportSender {
+		s := &statefulSender{}
+		created = append(created, s)
+		return s
+	}, nil)
+	sn := p.(simsdk.Snapshotter)
+
+	_, err := sn.SnapshotComponent("s1")
+	require.ErrorIs(t, err, simsdk.ErrNotFound)
+
+	req := simsdk.CreateComponentRequest{ComponentID: "s1", ComponentType: "amqp", Parameters: map[string]string{"queue": "q"}}
+	require.NoError(t, p.CreateComponentInstance(req))
+	_, err = p.HandleMessage(simsdk.SimMessage{ComponentID: "s1"})
+	require.NoError(t, err)
+
+	snap, err := sn.SnapshotComponent("s1")
+	require.NoError(t, err)
+	require.Equal(t, req, snap.CreateRequest)
+	require.Equal(t, []byte{1}, snap.State)
+
+	require.NoError(t, sn.RestoreComponent(snap))
+	require.Len(t, created, 2)
+	require.True(t, created[0].closed)
+	require.Equal(t, byte(1), created[1].sent)
+}
+
+func TestReceiverPlugin_RestoreRecreatesFromRequest(t *testing.T) {
+	var created []simsdk.CreateComponentRequest
+	p := NewReceiverPlugin(simsdk.Manifest{}, func(req simsdk.CreateComponentRequest) TransportReceiver {
+		created = append(created, req)
+		return &mockReceiver{}
+	}, nil)
+	sn := p.(simsdk.Snapshotter)
+
+	req := simsdk.CreateComponentRequest{ComponentID: "r1", ComponentType: "mqtt"}
+	require.NoError(t, p.CreateComponentInstance(req))
+	snap, err := sn.SnapshotComponent("r1")
+	require.NoError(t, err)
+	require.Empty(t, snap.State)
+
+	snap.ComponentID = "r2"
+	require.NoError(t, sn.RestoreComponent(snap))
+	require.Equal(t, "r2", created[1].ComponentID)
+	st, err := p.(simsdk.ComponentStatusProvider).GetComponentStatus("r2")
+	require.NoError(t, err)
+	require.Equal(t, simsdk.ComponentRunning, st.State)
+
+	snap.State = []byte("opaque")
+	require.ErrorIs(t, sn.RestoreComponent(snap), simsdk.ErrFailedPrecondition)
+}
+
+func TestSenderPlugin_RestoreChecksComponentTypeFirst(t *testing.T) {
+	live := &mockSender{}
+	p := NewSenderPlugin(simsdk.Manifest{ComponentTypes: []simsdk.ComponentType{{ID: "amqp"}}},
+		func(simsdk.CreateComponentRequest) TransportSender { return live }, nil)
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "s1", ComponentType: "amqp"}))
+
+	snap := simsdk.ComponentSnapshot{ComponentID: "s1", CreateRequest: simsdk.CreateComponentRequest{ComponentType: "mqtt"}}
+	require.ErrorIs(t, p.(simsdk.Snapshotter).RestoreComponent(snap), simsdk.ErrInvalidArgument)
+	require.False(t, live.closed, "the live instance is kept")
+	_, err := p.HandleMessage(simsdk.SimMessage{ComponentID: "s1"})
+	require.NoError(t, err)
+}
+
+func TestSenderPlugin_FailedRestoreReportsStopped(t *testing.T) {
+	calls := 0
+	p := NewSenderPlugin(simsdk.Manifest{}, func(simsdk.CreateComponentRequest) TransportSender {
+		calls++
+		if calls > 1 {
+			return nil
+		}
+		return &mockSender{}
+	}, nil)
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "s1", ComponentType: "amqp"}))
+
+	snap, err := p.(simsdk.Snapshotter).SnapshotComponent("s1")
+	require.NoError(t, err)
+	require.Error(t, p.(simsdk.Snapshotter).RestoreComponent(snap))
+
+	st, err := p.(simsdk.ComponentStatusProvider).GetComponentStatus("s1")
+	require.NoError(t, err)
+	require.Equal(t, simsdk.ComponentStopped, st.State)
+	require.Equal(t, "amqp", st.ComponentType)
+	require.Contains(t, st.LastError, "factory returned nil")
+
+	require.NoError(t, p.DestroyComponentInstance("s1"))
+	_, err = p.(simsdk.ComponentStatusProvider).GetComponentStatus("s1")
+	require.ErrorIs(t, err, simsdk.ErrNotFound)
+}
+
+func TestReceiverPlugin_RestoreMovesForwarderToNewReceiver(t *testing.T) {
+	var mu sync.Mutex
+	var created []*chanReceiver
+	p := NewReceiverPlugin(simsdk.Manifest{}, func(simsdk.CreateComponentRequest) TransportReceiver {
+		mu.Lock()
+		defer mu.Unlock()
+		r := &chanReceiver{ch: make(chan simsdk.SimMessage, 4)}
+		created = append(created, r)
+		return r
+	}, nil)
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "sink"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	snd := &sinkSender{}
+	(&Forwarder{Pauses: p.(PauseChecker)}).Start(ctx, created[0], snd, nil)
+
+	snap, err := p.(simsdk.Snapshotter).SnapshotComponent("sink")
+	require.NoError(t, err)
+	require.NoError(t, p.(simsdk.Snapshotter).RestoreComponent(snap))
+	mu.Lock()
+	require.Len(t, created, 2)
+	fresh := created[1]
+	mu.Unlock()
+
+	fresh.ch <- simsdk.SimMessage{MessageID: "after-restore"}
+	require.Eventually(t, func() bool {
+		snd.mu.Lock()
+		defer snd.mu.Unlock()
+		return len(snd.log) == 1 && snd.log[0].MessageID == "after-restore"
+	}, time.Second, 10*time.Millisecond)
+}
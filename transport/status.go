@@ -0,0 +1,105 @@
+package transport
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/neurosimio/simsdk-go"
+)
+
+// componentStats tracks the runtime state of a single component instance
+// hosted by the base sender/receiver plugins.
+type componentStats struct {
+	componentType string
+	received      atomic.Uint64
+	sent          atomic.Uint64
+
+	// lifecycle is held exclusively while the instance is reset or
+	// destroyed, and shared while it handles a message, so messages never
+	// reach a closed instance.
+	lifecycle sync.RWMutex
+
+	mu        sync.Mutex
+	state     simsdk.ComponentState
+	startTime time.Time
+	lastErr   string
+}
+
+func newComponentStats(componentType string) *componentStats {
+	return &componentStats{componentType: componentType, state: simsdk.ComponentCreated}
+}
+
+func (s *componentStats) setState(state simsdk.ComponentState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+	if state == simsdk.ComponentRunning && s.startTime.IsZero() {
+		s.startTime = time.Now()
+	}
+}
+
+// transition moves to the given state if the component state machine allows it.
+func (s *componentStats) transition(componentID string, to simsdk.ComponentState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := simsdk.ValidateTransition(componentID, s.state, to); err != nil {
+		return err
+	}
+	s.state = to
+	return nil
+}
+
+func (s *componentStats) currentState() simsdk.ComponentState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// reset clears counters and errors and marks the component running again.
+func (s *componentStats) reset() {
+	s.received.Store(0)
+	s.sent.Store(0)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = simsdk.ComponentRunning
+	s.startTime = time.Now()
+	s.lastErr = ""
+}
+
+func (s *componentStats) recordError(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.lastErr = err.Error()
+	s.mu.Unlock()
+}
+
+// snapshot builds a ComponentStatus, merging in plugin-defined fields when the
+// underlying transport implements StatusReporter.
+func (s *componentStats) snapshot(id string, instance any) simsdk.ComponentStatus {
+	s.mu.Lock()
+	cs := simsdk.ComponentStatus{
+		ComponentID:      id,
+		ComponentType:    s.componentType,
+		State:            s.state,
+		StartTime:        s.startTime,
+		MessagesReceived: s.received.Load(),
+		MessagesSent:     s.sent.Load(),
+		LastError:        s.lastErr,
+	}
+	s.mu.Unlock()
+
+	if r, ok := instance.(StatusReporter); ok {
+		cs.Fields = r.Status()
+	}
+	return cs
+}
+
+// sortStatuses orders statuses by component ID for stable listings.
+func sortStatuses(list []simsdk.ComponentStatus) []simsdk.ComponentStatus {
+	sort.Slice(list, func(i, j int) bool { return list[i].ComponentID < list[j].ComponentID })
+	return list
+}
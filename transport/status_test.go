@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/neurosimio/simsdk-go"
+	"github.com/stretchr/testify/require"
+)
+
+type reportingSender struct{ mockSender }
+
+func (r *reportingSender) Status() map[string]string { return map[string]string{"conn": "up"} }
+
+func TestSenderPlugin_ComponentStatus(t *testing.T) {
+	p := NewSenderPlugin(
+		simsdk.Manifest{},
+		func(req simsdk.CreateComponentRequest) TransportSender {
+			if req.ComponentID == "s2" {
+				return &reportingSender{}
+			}
+			return &mockSender{}
+		},
+		nil,
+	)
+	provider := p.(simsdk.ComponentStatusProvider)
+
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "s2", ComponentType: "amqp"}))
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "s1", ComponentType: "amqp"}))
+
+	_, err := p.HandleMessage(simsdk.SimMessage{ComponentID: "s1"})
+	require.NoError(t, err)
+
+	s, _ := getSenderForTest(p, "s1")
+	s.(*mockSender).sendErr = errors.New("broker gone")
+	_, err = p.HandleMessage(simsdk.SimMessage{ComponentID: "s1"})
+	require.Error(t, err)
+
+	list := provider.ListComponents()
+	require.Len(t, list, 2)
+	require.Equal(t, "s1", list[0].ComponentID)
+	require.Equal(t, "s2", list[1].ComponentID)
+
+	st, err := provider.GetComponentStatus("s1")
+	require.NoError(t, err)
+	require.Equal(t, "amqp", st.ComponentType)
+	require.Equal(t, simsdk.ComponentRunning, st.State)
+	require.False(t, st.StartTime.IsZero())
+	require.EqualValues(t, 2, st.MessagesReceived)
+	require.EqualValues(t, 1, st.MessagesSent)
+	require.Equal(t, "broker gone", st.LastError)
+
+	st2, err := provider.GetComponentStatus("s2")
+	require.NoError(t, err)
+	require.Equal(t, "up", st2.Fields["conn"])
+
+	require.NoError(t, p.DestroyComponentInstance("s1"))
+	_, err = provider.GetComponentStatus("s1")
+	require.ErrorIs(t, err, simsdk.ErrNotFound)
+	require.Len(t, provider.ListComponents(), 1)
+}
+
+func TestReceiverPlugin_ComponentStatus(t *testing.T) {
+	p := NewReceiverPlugin(
+		simsdk.Manifest{},
+		func(req simsdk.CreateComponentRequest) TransportReceiver {
+			if req.ComponentID == "bad" {
+				return &mockReceiver{startErr: errors.New("no route")}
+			}
+			return &mockReceiver{}
+		},
+		nil,
+	)
+	provider := p.(simsdk.ComponentStatusProvider)
+
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "r1", ComponentType: "udp"}))
+	require.Error(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "bad", ComponentType: "udp"}))
+
+	st, err := provider.GetComponentStatus("r1")
+	require.NoError(t, err)
+	require.Equal(t, simsdk.ComponentRunning, st.State)
+	require.Equal(t, "udp", st.ComponentType)
+
+	bad, err := provider.GetComponentStatus("bad")
+	require.NoError(t, err)
+	require.Equal(t, simsdk.ComponentStopped, bad.State)
+	require.Equal(t, "no route", bad.LastError)
+
+	require.Len(t, provider.ListComponents(), 2)
+	_, err = provider.GetComponentStatus("nope")
+	require.ErrorIs(t, err, simsdk.ErrNotFound)
+}
@@ -19,6 +19,9 @@ func NewReceiverPlugin(
 		factory:              factory,
 		streamHandlerFactory: streamHandlerFactory,
 		instances:            make(map[string]TransportReceiver),
+		stats:                make(map[string]*componentStats),
+		requests:             make(map[string]simsdk.CreateComponentRequest),
+		replaced:             make(map[string]chan struct{}),
 	}
 }
 
@@ -29,35 +32,59 @@ type baseReceiverPlugin struct {
 
 	mu        sync.Mutex
 	instances map[string]TransportReceiver
+	stats     map[string]*componentStats
+	requests  map[string]simsdk.CreateComponentRequest
+
+	// replaced holds, per component, a channel closed when its instance is
+	// next replaced, so a Forwarder can follow the new instance.
+	replaced map[string]chan struct{}
 }
 
 func (p *baseReceiverPlugin) GetManifest() simsdk.Manifest { return p.manifest }
 
+// CreateComponentInstance starts a receiver for req. An ID that is already in
+// use is rejected with ErrAlreadyExists.
 func (p *baseReceiverPlugin) CreateComponentInstance(req simsdk.CreateComponentRequest) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	if _, exists := p.instances[req.ComponentID]; exists {
-		return nil
+		return simsdk.NewError(simsdk.ErrAlreadyExists, req.ComponentID, "receiver instance %q already exists", req.ComponentID)
 	}
 	r := p.factory(req)
 	if r == nil {
 		return fmt.Errorf("receiver factory returned nil")
 	}
-	p.instances[req.ComponentID] = r
-	return r.Start(context.Background())
+	st := newComponentStats(req.ComponentType)
+	p.setInstanceLocked(req.ComponentID, r)
+	p.stats[req.ComponentID] = st
+	p.requests[req.ComponentID] = req
+
+	if err := r.Start(context.Background()); err != nil {
+		st.recordError(err)
+		st.setState(simsdk.ComponentStopped)
+		return err
+	}
+	st.setState(simsdk.ComponentRunning)
+	return nil
 }
 
 func (p *baseReceiverPlugin) DestroyComponentInstance(id string) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	if st := p.statsFor(id); st != nil {
+		st.lifecycle.Lock()
+		defer st.lifecycle.Unlock()
+	}
 
+	p.mu.Lock()
 	r, ok := p.instances[id]
-	if !ok {
-		return nil
+	p.setInstanceLocked(id, nil)
+	delete(p.stats, id)
+	delete(p.requests, id)
+	p.mu.Unlock()
+
+	if ok {
+		_ = r.Stop(context.Background())
 	}
-	_ = r.Stop(context.Background())
-	delete(p.instances, id)
 	return nil
 }
 
@@ -66,9 +93,245 @@ func (p *baseReceiverPlugin) HandleMessage(_ simsdk.SimMessage) ([]simsdk.SimMes
 	return nil, nil
 }
 
+// ListComponents reports every live receiver instance.
+func (p *baseReceiverPlugin) ListComponents() []simsdk.ComponentStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]simsdk.ComponentStatus, 0, len(p.stats))
+	for id, st := range p.stats {
+		out = append(out, st.snapshot(id, p.instances[id]))
+	}
+	return sortStatuses(out)
+}
+
+// GetComponentStatus reports a single receiver instance.
+func (p *baseReceiverPlugin) GetComponentStatus(componentID string) (simsdk.ComponentStatus, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	st, ok := p.stats[componentID]
+	if !ok {
+		return simsdk.ComponentStatus{}, simsdk.NewError(simsdk.ErrNotFound, componentID, "no receiver instance for %q", componentID)
+	}
+	return st.snapshot(componentID, p.instances[componentID]), nil
+}
+
 func (p *baseReceiverPlugin) GetStreamHandler() simsdk.StreamHandler {
 	if p.streamHandlerFactory != nil {
 		return p.streamHandlerFactory()
 	}
 	return &DefaultPerInstanceStreamHandler{}
 }
+
+// PauseComponent marks a receiver paused. Receivers implementing Pausable are
+// paused directly; otherwise a Forwarder using this plugin as its PauseChecker
+// drops inbound messages until the receiver is resumed.
+func (p *baseReceiverPlugin) PauseComponent(componentID string) error {
+	return p.setPaused(componentID, true)
+}
+
+// ResumeComponent resumes forwarding for a paused receiver.
+func (p *baseReceiverPlugin) ResumeComponent(componentID string) error {
+	return p.setPaused(componentID, false)
+}
+
+func (p *baseReceiverPlugin) setPaused(componentID string, paused bool) error {
+	p.mu.Lock()
+	r, st := p.instances[componentID], p.stats[componentID]
+	p.mu.Unlock()
+	if r == nil {
+		return simsdk.NewError(simsdk.ErrNotFound, componentID, "no receiver instance for %q", componentID)
+	}
+
+	from, to := simsdk.ComponentRunning, simsdk.ComponentPaused
+	if !paused {
+		from, to = to, from
+	}
+	if err := st.transition(componentID, to); err != nil {
+		return err
+	}
+
+	if pa, ok := r.(Pausable); ok {
+		var err error
+		if paused {
+			err = pa.Pause(context.Background())
+		} else {
+			err = pa.Resume(context.Background())
+		}
+		if err != nil {
+			st.recordError(err)
+			st.setState(from)
+			return err
+		}
+	}
+	return nil
+}
+
+// ResetComponent returns a receiver to its initial state. Receivers implementing
+// Resettable are reset in place; others are stopped and recreated from their
+// original CreateComponentRequest; a Forwarder whose ReceiverSource is this
+// plugin moves to the new instance.
+func (p *baseReceiverPlugin) ResetComponent(componentID string) error {
+	st := p.statsFor(componentID)
+	if st == nil {
+		return simsdk.NewError(simsdk.ErrNotFound, componentID, "no receiver instance for %q", componentID)
+	}
+	st.lifecycle.Lock()
+	defer st.lifecycle.Unlock()
+
+	// A destroy may have won the race for the lifecycle lock.
+	p.mu.Lock()
+	r, req := p.instances[componentID], p.requests[componentID]
+	current := p.stats[componentID] == st
+	p.mu.Unlock()
+	if r == nil || !current {
+		return simsdk.NewError(simsdk.ErrNotFound, componentID, "no receiver instance for %q", componentID)
+	}
+
+	if rs, ok := r.(Resettable); ok {
+		if err := rs.Reset(context.Background()); err != nil {
+			st.recordError(err)
+			return err
+		}
+		st.reset()
+		return nil
+	}
+
+	_ = r.Stop(context.Background())
+	fresh := p.factory(req)
+	if fresh == nil {
+		err := fmt.Errorf("receiver factory returned nil")
+		st.recordError(err)
+		st.setState(simsdk.ComponentStopped)
+		return err
+	}
+	p.mu.Lock()
+	p.setInstanceLocked(componentID, fresh)
+	p.mu.Unlock()
+	if err := fresh.Start(context.Background()); err != nil {
+		st.recordError(err)
+		st.setState(simsdk.ComponentStopped)
+		return err
+	}
+	st.reset()
+	return nil
+}
+
+// IsPaused reports whether the receiver instance is paused.
+func (p *baseReceiverPlugin) IsPaused(componentID string) bool {
+	p.mu.Lock()
+	st := p.stats[componentID]
+	p.mu.Unlock()
+	return st != nil && st.currentState() == simsdk.ComponentPaused
+}
+
+// MessageReceived counts a message a receiver delivered to its Forwarder.
+func (p *baseReceiverPlugin) MessageReceived(componentID string) {
+	if st := p.statsFor(componentID); st != nil {
+		st.received.Add(1)
+	}
+}
+
+// MessageForwarded counts a message a Forwarder sent to the core, or records
+// the error when the send failed.
+func (p *baseReceiverPlugin) MessageForwarded(componentID string, err error) {
+	st := p.statsFor(componentID)
+	if st == nil {
+		return
+	}
+	if err != nil {
+		st.recordError(err)
+		return
+	}
+	st.sent.Add(1)
+}
+
+// Receiver returns the live receiver instance for componentID and a channel
+// closed when a reset, restore or destroy replaces it.
+func (p *baseReceiverPlugin) Receiver(componentID string) (TransportReceiver, <-chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ch, ok := p.replaced[componentID]
+	if !ok {
+		ch = make(chan struct{})
+		p.replaced[componentID] = ch
+	}
+	return p.instances[componentID], ch
+}
+
+// setInstanceLocked installs r as componentID's instance, or removes the
+// instance when r is nil, and wakes any Forwarder following the component.
+// p.mu must be held.
+func (p *baseReceiverPlugin) setInstanceLocked(componentID string, r TransportReceiver) {
+	if r == nil {
+		delete(p.instances, componentID)
+	} else {
+		p.instances[componentID] = r
+	}
+	if ch, ok := p.replaced[componentID]; ok {
+		close(ch)
+		delete(p.replaced, componentID)
+	}
+}
+
+func (p *baseReceiverPlugin) statsFor(componentID string) *componentStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats[componentID]
+}
+
+// CheckComponentReadiness delegates to the receiver instance when it implements
+// simsdk.ReadinessChecker (e.g. to report its connection state).
+func (p *baseReceiverPlugin) CheckComponentReadiness(ctx context.Context, componentID string) error {
+	p.mu.Lock()
+	inst, ok := p.instances[componentID]
+	p.mu.Unlock()
+	if !ok {
+		return simsdk.NewError(simsdk.ErrNotFound, componentID, "no receiver instance for %q", componentID)
+	}
+	if rc, ok := inst.(simsdk.ReadinessChecker); ok {
+		return rc.CheckReadiness(ctx)
+	}
+	return nil
+}
+
+// SnapshotComponent captures a receiver's creation request, plus its state
+// when the receiver implements StateSnapshotter.
+func (p *baseReceiverPlugin) SnapshotComponent(componentID string) (simsdk.ComponentSnapshot, error) {
+	p.mu.Lock()
+	r, ok := p.instances[componentID]
+	req := p.requests[componentID]
+	p.mu.Unlock()
+	if !ok {
+		return simsdk.ComponentSnapshot{}, simsdk.NewError(simsdk.ErrNotFound, componentID, "no receiver instance for %q", componentID)
+	}
+	return snapshotInstance(componentID, req, r)
+}
+
+// RestoreComponent recreates a receiver from a snapshot, replacing any live
+// instance with the same ID.
+func (p *baseReceiverPlugin) RestoreComponent(snap simsdk.ComponentSnapshot) error {
+	return restoreInstance(p, snap)
+}
+
+func (p *baseReceiverPlugin) instance(componentID string) any {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.instances[componentID]
+}
+
+// markStopped lists a component whose instance could not be created as
+// Stopped with the error. A receiver that failed to start is already listed.
+func (p *baseReceiverPlugin) markStopped(req simsdk.CreateComponentRequest, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.stats[req.ComponentID]; ok {
+		return
+	}
+	st := newComponentStats(req.ComponentType)
+	st.setState(simsdk.ComponentStopped)
+	st.recordError(err)
+	p.stats[req.ComponentID] = st
+	p.requests[req.ComponentID] = req
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/neurosimio/simsdk-go"
 	"github.com/stretchr/testify/require"
@@ -124,3 +125,46 @@ func TestReceiverPlugin_StreamHandlerFactory_DefaultVsCustom(t *testing.T) {
 	_, isCustom := h2.(*mockStreamHandler)
 	require.True(t, isCustom)
 }
+
+func TestReceiverPlugin_CountsForwardedMessages(t *testing.T) {
+	rcv := &mockReceiver{ch: make(chan simsdk.SimMessage, 4)}
+	p := NewReceiverPlugin(
+		simsdk.Manifest{},
+		func(simsdk.CreateComponentRequest) TransportReceiver { return rcv },
+		nil,
+	)
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "sink"}))
+	reporter := p.(simsdk.ComponentStatusProvider)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	(&Forwarder{Pauses: p.(PauseChecker)}).Start(ctx, rcv, &sinkSender{}, nil)
+	rcv.ch <- simsdk.SimMessage{MessageID: "a"}
+	rcv.ch <- simsdk.SimMessage{MessageID: "b"}
+
+	require.Eventually(t, func() bool {
+		st, err := reporter.GetComponentStatus("sink")
+		return err == nil && st.MessagesReceived == 2 && st.MessagesSent == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestReceiverPlugin_CreateRejectsDuplicateID(t *testing.T) {
+	var created []*mockReceiver
+	p := NewReceiverPlugin(
+		simsdk.Manifest{},
+		func(simsdk.CreateComponentRequest) TransportReceiver {
+			r := &mockReceiver{}
+			created = append(created, r)
+			return r
+		},
+		nil,
+	)
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "r1"}))
+	require.ErrorIs(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "r1"}), simsdk.ErrAlreadyExists)
+
+	require.Len(t, created, 1)
+	require.False(t, created[0].stopped)
+	st, err := p.(simsdk.ComponentStatusProvider).GetComponentStatus("r1")
+	require.NoError(t, err)
+	require.Equal(t, simsdk.ComponentRunning, st.State)
+}
@@ -24,6 +24,8 @@ func NewSenderPlugin(
 		streamHandlerFactory: streamHandlerFactory,
 		instances:            make(map[string]TransportSender),
 		cancels:              make(map[string]context.CancelFunc), // NEW
+		stats:                make(map[string]*componentStats),
+		requests:             make(map[string]simsdk.CreateComponentRequest),
 	}
 }
 
@@ -37,12 +39,19 @@ type baseSenderPlugin struct {
 	instances map[string]TransportSender
 
 	// NEW
-	cancels map[string]context.CancelFunc
+	cancels  map[string]context.CancelFunc
+	stats    map[string]*componentStats
+	requests map[string]simsdk.CreateComponentRequest
 }
 
 func (p *baseSenderPlugin) GetManifest() simsdk.Manifest { return p.manifest }
 
+// CreateComponentInstance starts a sender for req. An ID that is already in
+// use is rejected with ErrAlreadyExists, so the live sender is never leaked.
 func (p *baseSenderPlugin) CreateComponentInstance(req simsdk.CreateComponentRequest) error {
+	if p.exists(req.ComponentID) {
+		return simsdk.NewError(simsdk.ErrAlreadyExists, req.ComponentID, "sender instance %q already exists", req.ComponentID)
+	}
 	s := p.factory(req)
 	if s == nil {
 		return fmt.Errorf("sender factory returned nil")
@@ -56,14 +65,41 @@ func (p *baseSenderPlugin) CreateComponentInstance(req simsdk.CreateComponentReq
 		return err
 	}
 
+	st := newComponentStats(req.ComponentType)
+	st.setState(simsdk.ComponentRunning)
+
 	p.mu.Lock()
+	if _, ok := p.instances[req.ComponentID]; ok {
+		// another create for the same ID won the race
+		p.mu.Unlock()
+		cancel()
+		_ = s.Close(context.Background())
+		return simsdk.NewError(simsdk.ErrAlreadyExists, req.ComponentID, "sender instance %q already exists", req.ComponentID)
+	}
 	p.instances[req.ComponentID] = s
 	p.cancels[req.ComponentID] = cancel
+	p.stats[req.ComponentID] = st
+	p.requests[req.ComponentID] = req
 	p.mu.Unlock()
 	return nil
 }
 
+func (p *baseSenderPlugin) exists(componentID string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.instances[componentID]
+	return ok
+}
+
 func (p *baseSenderPlugin) DestroyComponentInstance(componentID string) error {
+	p.mu.RLock()
+	st := p.stats[componentID]
+	p.mu.RUnlock()
+	if st != nil {
+		st.lifecycle.Lock()
+		defer st.lifecycle.Unlock()
+	}
+
 	p.mu.Lock()
 	s, ok := p.instances[componentID]
 	cancel, hasCancel := p.cancels[componentID]
@@ -73,6 +109,8 @@ func (p *baseSenderPlugin) DestroyComponentInstance(componentID string) error {
 	if hasCancel {
 		delete(p.cancels, componentID)
 	}
+	delete(p.stats, componentID)
+	delete(p.requests, componentID)
 	p.mu.Unlock()
 
 	if !ok {
@@ -88,28 +126,240 @@ func (p *baseSenderPlugin) DestroyComponentInstance(componentID string) error {
 // Creates a per-call context (with optional timeout) and does not hold locks
 // while invoking external code.
 func (p *baseSenderPlugin) HandleMessage(msg simsdk.SimMessage) ([]simsdk.SimMessage, error) {
-	// read under RLock
+	// read under RLock; the instance is read again once a reset or destroy
+	// in progress has finished
 	p.mu.RLock()
-	s := p.instances[msg.ComponentID]
+	st := p.stats[msg.ComponentID]
 	p.mu.RUnlock()
+	var s TransportSender
+	if st != nil {
+		st.lifecycle.RLock()
+		defer st.lifecycle.RUnlock()
+		p.mu.RLock()
+		s = p.instances[msg.ComponentID]
+		p.mu.RUnlock()
+	}
 
 	if s == nil {
-		return nil, fmt.Errorf("no sender instance for %q", msg.ComponentID)
+		return nil, simsdk.NewError(simsdk.ErrNotFound, msg.ComponentID, "no sender instance for %q", msg.ComponentID)
+	}
+	if state := st.currentState(); state != simsdk.ComponentRunning {
+		return nil, simsdk.NewError(simsdk.ErrFailedPrecondition, msg.ComponentID, "sender %q is %s", msg.ComponentID, state)
 	}
 
 	// per-call context (adjust timeout to taste or make configurable)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// trace the send with the default tracer; the message carries the span on
+	ctx, span := simsdk.StartMessageSpan(ctx, nil, "simsdk.transport.SendSim", simsdk.SpanKindProducer, &msg)
+	defer span.End()
+
+	st.received.Add(1)
 	if err := s.SendSim(ctx, msg); err != nil {
+		st.recordError(err)
+		span.RecordError(err)
 		return nil, err
 	}
+	st.sent.Add(1)
 	return nil, nil
 }
 
+// ListComponents reports every live sender instance.
+func (p *baseSenderPlugin) ListComponents() []simsdk.ComponentStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	out := make([]simsdk.ComponentStatus, 0, len(p.stats))
+	for id, st := range p.stats {
+		out = append(out, st.snapshot(id, p.instances[id]))
+	}
+	return sortStatuses(out)
+}
+
+// GetComponentStatus reports a single sender instance.
+func (p *baseSenderPlugin) GetComponentStatus(componentID string) (simsdk.ComponentStatus, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	st, ok := p.stats[componentID]
+	if !ok {
+		return simsdk.ComponentStatus{}, simsdk.NewError(simsdk.ErrNotFound, componentID, "no sender instance for %q", componentID)
+	}
+	return st.snapshot(componentID, p.instances[componentID]), nil
+}
+
 func (p *baseSenderPlugin) GetStreamHandler() simsdk.StreamHandler {
 	if p.streamHandlerFactory != nil {
 		return p.streamHandlerFactory()
 	}
 	return &DefaultPerInstanceStreamHandler{}
 }
+
+// PauseComponent stops the sender from accepting messages; HandleMessage rejects
+// messages for a paused sender with ErrFailedPrecondition.
+func (p *baseSenderPlugin) PauseComponent(componentID string) error {
+	return p.setPaused(componentID, true)
+}
+
+// ResumeComponent lets a paused sender accept messages again.
+func (p *baseSenderPlugin) ResumeComponent(componentID string) error {
+	return p.setPaused(componentID, false)
+}
+
+func (p *baseSenderPlugin) setPaused(componentID string, paused bool) error {
+	p.mu.RLock()
+	s, st := p.instances[componentID], p.stats[componentID]
+	p.mu.RUnlock()
+	if s == nil {
+		return simsdk.NewError(simsdk.ErrNotFound, componentID, "no sender instance for %q", componentID)
+	}
+
+	from, to := simsdk.ComponentRunning, simsdk.ComponentPaused
+	if !paused {
+		from, to = to, from
+	}
+	if err := st.transition(componentID, to); err != nil {
+		return err
+	}
+
+	if pa, ok := s.(Pausable); ok {
+		var err error
+		if paused {
+			err = pa.Pause(context.Background())
+		} else {
+			err = pa.Resume(context.Background())
+		}
+		if err != nil {
+			st.recordError(err)
+			st.setState(from)
+			return err
+		}
+	}
+	return nil
+}
+
+// ResetComponent returns a sender to its initial state. Senders implementing
+// Resettable are reset in place; others are closed and recreated from their
+// original CreateComponentRequest. Messages wait while a reset is in progress.
+func (p *baseSenderPlugin) ResetComponent(componentID string) error {
+	p.mu.RLock()
+	st := p.stats[componentID]
+	p.mu.RUnlock()
+	if st == nil {
+		return simsdk.NewError(simsdk.ErrNotFound, componentID, "no sender instance for %q", componentID)
+	}
+	st.lifecycle.Lock()
+	defer st.lifecycle.Unlock()
+
+	// A destroy may have won the race for the lifecycle lock.
+	p.mu.RLock()
+	s, cancel, req := p.instances[componentID], p.cancels[componentID], p.requests[componentID]
+	current := p.stats[componentID] == st
+	p.mu.RUnlock()
+	if s == nil || !current {
+		return simsdk.NewError(simsdk.ErrNotFound, componentID, "no sender instance for %q", componentID)
+	}
+
+	if r, ok := s.(Resettable); ok {
+		if err := r.Reset(context.Background()); err != nil {
+			st.recordError(err)
+			return err
+		}
+		st.reset()
+		return nil
+	}
+
+	cancel()
+	_ = s.Close(context.Background())
+
+	fresh := p.factory(req)
+	if fresh == nil {
+		err := fmt.Errorf("sender factory returned nil")
+		st.recordError(err)
+		st.setState(simsdk.ComponentStopped)
+		return err
+	}
+	ctx, freshCancel := context.WithCancel(context.Background())
+	if err := fresh.Start(ctx); err != nil {
+		freshCancel()
+		st.recordError(err)
+		st.setState(simsdk.ComponentStopped)
+		return err
+	}
+
+	p.mu.Lock()
+	if p.instances[componentID] != s {
+		p.mu.Unlock()
+		freshCancel()
+		_ = fresh.Close(context.Background())
+		return simsdk.NewError(simsdk.ErrNotFound, componentID, "sender %q was removed during reset", componentID)
+	}
+	p.instances[componentID] = fresh
+	p.cancels[componentID] = freshCancel
+	p.mu.Unlock()
+	st.reset()
+	return nil
+}
+
+// IsPaused reports whether the sender instance is paused.
+func (p *baseSenderPlugin) IsPaused(componentID string) bool {
+	p.mu.RLock()
+	st := p.stats[componentID]
+	p.mu.RUnlock()
+	return st != nil && st.currentState() == simsdk.ComponentPaused
+}
+
+// CheckComponentReadiness delegates to the sender instance when it implements
+// simsdk.ReadinessChecker (e.g. to report its connection state).
+func (p *baseSenderPlugin) CheckComponentReadiness(ctx context.Context, componentID string) error {
+	p.mu.RLock()
+	inst, ok := p.instances[componentID]
+	p.mu.RUnlock()
+	if !ok {
+		return simsdk.NewError(simsdk.ErrNotFound, componentID, "no sender instance for %q", componentID)
+	}
+	if rc, ok := inst.(simsdk.ReadinessChecker); ok {
+		return rc.CheckReadiness(ctx)
+	}
+	return nil
+}
+
+// SnapshotComponent captures a sender's creation request, plus its state when
+// the sender implements StateSnapshotter.
+func (p *baseSenderPlugin) SnapshotComponent(componentID string) (simsdk.ComponentSnapshot, error) {
+	p.mu.RLock()
+	s, req := p.instances[componentID], p.requests[componentID]
+	p.mu.RUnlock()
+	if s == nil {
+		return simsdk.ComponentSnapshot{}, simsdk.NewError(simsdk.ErrNotFound, componentID, "no sender instance for %q", componentID)
+	}
+	return snapshotInstance(componentID, req, s)
+}
+
+// RestoreComponent recreates a sender from a snapshot, replacing any live
+// instance with the same ID.
+func (p *baseSenderPlugin) RestoreComponent(snap simsdk.ComponentSnapshot) error {
+	return restoreInstance(p, snap)
+}
+
+func (p *baseSenderPlugin) instance(componentID string) any {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.instances[componentID]
+}
+
+// markStopped lists a component whose instance could not be created as
+// Stopped with the error.
+func (p *baseSenderPlugin) markStopped(req simsdk.CreateComponentRequest, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.stats[req.ComponentID]; ok {
+		return
+	}
+	st := newComponentStats(req.ComponentType)
+	st.setState(simsdk.ComponentStopped)
+	st.recordError(err)
+	p.stats[req.ComponentID] = st
+	p.requests[req.ComponentID] = req
+}
@@ -151,3 +151,33 @@ func TestSenderPlugin_StreamHandlerFactory_DefaultVsCustom(t *testing.T) {
 	_, isCustom := h2.(*mockStreamHandler)
 	require.True(t, isCustom)
 }
+
+func TestSenderPlugin_HandleMessageUnknownComponent(t *testing.T) {
+	p := NewSenderPlugin(
+		simsdk.Manifest{},
+		func(simsdk.CreateComponentRequest) TransportSender { return &mockSender{} },
+		nil,
+	)
+	_, err := p.HandleMessage(simsdk.SimMessage{ComponentID: "missing"})
+	require.ErrorIs(t, err, simsdk.ErrNotFound)
+}
+
+func TestSenderPlugin_CreateRejectsDuplicateID(t *testing.T) {
+	var created []*mockSender
+	p := NewSenderPlugin(
+		simsdk.Manifest{},
+		func(simsdk.CreateComponentRequest) TransportSender {
+			s := &mockSender{}
+			created = append(created, s)
+			return s
+		},
+		nil,
+	)
+	require.NoError(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "s1"}))
+	require.ErrorIs(t, p.CreateComponentInstance(simsdk.CreateComponentRequest{ComponentID: "s1"}), simsdk.ErrAlreadyExists)
+
+	require.Len(t, created, 1, "no second sender is started")
+	require.False(t, created[0].closed)
+	_, err := p.HandleMessage(simsdk.SimMessage{ComponentID: "s1", MessageType: "t"})
+	require.NoError(t, err)
+}
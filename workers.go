@@ -0,0 +1,127 @@
+package simsdk
+
+import (
+	"context"
+	"sync"
+)
+
+// WithWorkers processes inbound SimMessages on up to n goroutines at once.
+// Messages that share an ordering key are still handled one at a time, in the
+// order they arrived; by default the key is the message's ComponentID. At most
+// maxPending messages are queued or running before the stream stops reading;
+// zero means 4*n. Handlers shared across components must be safe for
+// concurrent use when n > 1. The default, n <= 1, handles messages inline.
+func WithWorkers(n, maxPending int) StreamOption {
+	return func(o *streamOptions) {
+		o.workers = n
+		o.maxPending = maxPending
+	}
+}
+
+// WithOrderingKey orders messages by the value of the given metadata key
+// instead of by ComponentID. Messages without the key fall back to ComponentID.
+func WithOrderingKey(metadataKey string) StreamOption {
+	return func(o *streamOptions) { o.orderingKey = metadataKey }
+}
+
+// orderingKeyFor returns the key that serializes a message under the stream's options.
+func (o streamOptions) orderingKeyFor(componentID string, metadata map[string]string) string {
+	if o.orderingKey != "" {
+		if k, ok := metadata[o.orderingKey]; ok {
+			return k
+		}
+	}
+	return componentID
+}
+
+// orderedPool runs tasks concurrently across keys and sequentially within a
+// key. Each key with queued work has one goroutine draining its FIFO; the
+// number of tasks running at once is bounded by the worker semaphore.
+type orderedPool struct {
+	sem   chan struct{}
+	limit int
+
+	mu      sync.Mutex
+	queues  map[string][]func()
+	pending int
+	space   chan struct{} // closed and replaced whenever a task finishes
+	wg      sync.WaitGroup
+}
+
+func newOrderedPool(workers, maxPending int) *orderedPool {
+	if maxPending <= 0 {
+		maxPending = 4 * workers
+	}
+	return &orderedPool{
+		sem:    make(chan struct{}, workers),
+		limit:  maxPending,
+		queues: make(map[string][]func()),
+		space:  make(chan struct{}),
+	}
+}
+
+// submit queues task behind any earlier tasks for key. It blocks while the
+// pool is at its pending limit.
+func (p *orderedPool) submit(ctx context.Context, key string, task func()) error {
+	for {
+		p.mu.Lock()
+		if p.pending < p.limit {
+			break
+		}
+		space := p.space
+		p.mu.Unlock()
+		select {
+		case <-space:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	defer p.mu.Unlock()
+
+	p.pending++
+	p.wg.Add(1)
+	q, active := p.queues[key]
+	p.queues[key] = append(q, task)
+	if !active {
+		go p.drain(key)
+	}
+	return nil
+}
+
+func (p *orderedPool) drain(key string) {
+	for {
+		p.mu.Lock()
+		q := p.queues[key]
+		if len(q) == 0 {
+			delete(p.queues, key)
+			p.mu.Unlock()
+			return
+		}
+		task := q[0]
+		p.mu.Unlock()
+
+		p.sem <- struct{}{}
+		task()
+		<-p.sem
+
+		p.mu.Lock()
+		p.queues[key] = p.queues[key][1:]
+		p.pending--
+		close(p.space)
+		p.space = make(chan struct{})
+		p.mu.Unlock()
+		p.wg.Done()
+	}
+}
+
+// size returns the number of tasks queued or running.
+func (p *orderedPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.pending
+}
+
+// wait blocks until every submitted task has finished.
+func (p *orderedPool) wait() {
+	p.wg.Wait()
+}
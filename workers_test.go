@@ -0,0 +1,161 @@
+package simsdk
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neurosimio/simsdk-go/rpc/simsdkrpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedPool_SerializesPerKey(t *testing.T) {
+	p := newOrderedPool(4, 0)
+	var mu sync.Mutex
+	got := map[string][]int{}
+	for i := 0; i < 50; i++ {
+		key := []string{"a", "b", "c"}[i%3]
+		i := i
+		require.NoError(t, p.submit(context.Background(), key, func() {
+			mu.Lock()
+			got[key] = append(got[key], i)
+			mu.Unlock()
+		}))
+	}
+	p.wait()
+
+	for _, seq := range got {
+		for j := 1; j < len(seq); j++ {
+			require.Less(t, seq[j-1], seq[j])
+		}
+	}
+	require.Len(t, got["a"], 17)
+}
+
+func TestOrderedPool_BoundsConcurrency(t *testing.T) {
+	p := newOrderedPool(2, 0)
+	var active, peak atomic.Int32
+	for i := 0; i < 8; i++ {
+		key := string(rune('a' + i))
+		require.NoError(t, p.submit(context.Background(), key, func() {
+			n := active.Add(1)
+			for {
+				old := peak.Load()
+				if n <= old || peak.CompareAndSwap(old, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			active.Add(-1)
+		}))
+	}
+	p.wait()
+	require.Equal(t, int32(2), peak.Load())
+}
+
+func TestOrderedPool_SubmitBlocksAtPendingLimit(t *testing.T) {
+	p := newOrderedPool(1, 1)
+	release := make(chan struct{})
+	require.NoError(t, p.submit(context.Background(), "a", func() { <-release }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, p.submit(ctx, "b", func() {}), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, p.submit(context.Background(), "b", func() {}))
+	p.wait()
+}
+
+// blockingHandler blocks OnSimMessage for component "slow" until released.
+type blockingHandler struct {
+	release chan struct{}
+	mu      sync.Mutex
+	order   []string
+}
+
+func (h *blockingHandler) OnInit(*simsdkrpc.PluginInit) error { return nil }
+func (h *blockingHandler) OnShutdown(string)                  {}
+
+func (h *blockingHandler) OnSimMessage(msg *SimMessage) ([]*SimMessage, error) {
+	if msg.ComponentID == "slow" {
+		<-h.release
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.order = append(h.order, msg.MessageID)
+	if msg.MessageID == "f2" {
+		close(h.release)
+	}
+	return nil, nil
+}
+
+func TestServeStream_WorkersDoNotBlockOtherComponents(t *testing.T) {
+	handler := &blockingHandler{release: make(chan struct{})}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("slow"),
+		initEnvelope("fast"),
+		simMessageEnvelope("s1", "slow"),
+		simMessageEnvelope("s2", "slow"),
+		simMessageEnvelope("f1", "fast"),
+		simMessageEnvelope("f2", "fast"),
+	}}
+
+	require.NoError(t, ServeStream(handler, stream, WithWorkers(2, 0)))
+
+	// fast messages finish while slow ones are blocked; slow ones keep their order
+	require.Equal(t, []string{"f1", "f2", "s1", "s2"}, handler.order)
+
+	var acked []string
+	for _, env := range stream.sent {
+		if ack := env.GetAck(); ack != nil {
+			acked = append(acked, ack.MessageId)
+		}
+	}
+	require.ElementsMatch(t, []string{"s1", "s2", "f1", "f2"}, acked)
+}
+
+func TestStreamOptions_OrderingKeyFromMetadata(t *testing.T) {
+	o := defaultStreamOptions()
+	WithOrderingKey("entity")(&o)
+	require.Equal(t, "tank-1", o.orderingKeyFor("a", map[string]string{"entity": "tank-1"}))
+	require.Equal(t, "a", o.orderingKeyFor("a", nil))
+}
+
+// reinitHandler records whether OnInit ran while OnSimMessage was busy.
+type reinitHandler struct {
+	busy    atomic.Bool
+	overlap atomic.Bool
+	inits   atomic.Int32
+}
+
+func (h *reinitHandler) OnInit(*simsdkrpc.PluginInit) error {
+	h.inits.Add(1)
+	if h.busy.Load() {
+		h.overlap.Store(true)
+	}
+	return nil
+}
+
+func (h *reinitHandler) OnShutdown(string) {}
+
+func (h *reinitHandler) OnSimMessage(*SimMessage) ([]*SimMessage, error) {
+	h.busy.Store(true)
+	defer h.busy.Store(false)
+	time.Sleep(20 * time.Millisecond)
+	return nil, nil
+}
+
+func TestServeStreamMux_ReinitWaitsForWorkers(t *testing.T) {
+	handler := &reinitHandler{}
+	stream := &mockStream{incoming: []*simsdkrpc.PluginMessageEnvelope{
+		initEnvelope("a"),
+		simMessageEnvelope("m1", "a"),
+		initEnvelope("a"),
+	}}
+	require.NoError(t, ServeStreamMux(func() StreamHandler { return handler }, stream, WithWorkers(2, 0)))
+	require.Equal(t, int32(2), handler.inits.Load())
+	require.False(t, handler.overlap.Load(), "re-init ran while a worker was handling a message")
+}